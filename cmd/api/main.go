@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -13,7 +14,28 @@ import (
 
 	"astralis/internal/adapters/primary/rest"
 	"astralis/internal/adapters/secondary/astronomyapi"
+	"astralis/internal/adapters/secondary/aurora"
+	"astralis/internal/adapters/secondary/conjunctions"
+	"astralis/internal/adapters/secondary/deepsky"
+	"astralis/internal/adapters/secondary/eclipses"
+	"astralis/internal/adapters/secondary/ephemeris"
+	"astralis/internal/adapters/secondary/fireballs"
+	"astralis/internal/adapters/secondary/horizons"
+	"astralis/internal/adapters/secondary/ical"
+	"astralis/internal/adapters/secondary/jupitermoons"
+	"astralis/internal/adapters/secondary/launches"
+	"astralis/internal/adapters/secondary/lunar"
+	"astralis/internal/adapters/secondary/meteorshowers"
 	"astralis/internal/adapters/secondary/nasaapi"
+	"astralis/internal/adapters/secondary/neows"
+	"astralis/internal/adapters/secondary/occultations"
+	"astralis/internal/adapters/secondary/planets"
+	"astralis/internal/adapters/secondary/satellites"
+	"astralis/internal/adapters/secondary/seasons"
+	"astralis/internal/adapters/secondary/smallbodies"
+	"astralis/internal/adapters/secondary/twilight"
+	"astralis/internal/adapters/secondary/variablestars"
+	"astralis/internal/core/domain"
 	"astralis/internal/core/ports"
 	"astralis/internal/core/service"
 	"astralis/pkg/config"
@@ -25,25 +47,155 @@ func main() {
 	c := config.LoadConfig()
 	l.Printf("loading config...")
 
+	observer := domain.Observer{
+		Latitude:  c.Latitude(),
+		Longitude: c.Longitude(),
+		Elevation: c.Elevation(),
+		TimeZone:  c.TimeZone(),
+	}
+	if !observer.IsValid() {
+		l.Fatalf("invalid observer: %+v", observer)
+	}
+	locations, err := domain.ParseLocations(c.Locations())
+	if err != nil {
+		l.Fatalf("loading locations: %s", err)
+	}
+	l.Printf("loading %d saved locations...", len(locations))
+
 	var repositories []ports.EventRepository
 	astronomyRepo := astronomyapi.NewAstronomyAPIRepository()
 	repositories = append(repositories, astronomyRepo)
 	l.Printf("loading AstronomyAPI...")
 
+	ephemerisRepo := ephemeris.NewEphemerisRepository()
+	repositories = append(repositories, ephemerisRepo)
+	l.Printf("loading Ephemeris...")
+
+	lunarRepo := lunar.NewLunarRepository()
+	repositories = append(repositories, lunarRepo)
+	l.Printf("loading Lunar...")
+
+	meteorShowerRepo, err := meteorshowers.NewMeteorShowerRepository()
+	if err != nil {
+		l.Fatalf("loading meteor showers: %s", err)
+	}
+	repositories = append(repositories, meteorShowerRepo)
+	l.Printf("loading MeteorShowers...")
+
+	eclipseRepo := eclipses.NewEclipseRepository()
+	repositories = append(repositories, eclipseRepo)
+	l.Printf("loading Eclipses...")
+
+	conjunctionRepo := conjunctions.NewConjunctionRepository(c.ConjunctionSeparation())
+	repositories = append(repositories, conjunctionRepo)
+	l.Printf("loading Conjunctions...")
+
+	planetRepo := planets.NewPlanetRepository()
+	repositories = append(repositories, planetRepo)
+	l.Printf("loading Planet Phenomena...")
+
+	twilightRepo := twilight.NewTwilightRepository()
+	repositories = append(repositories, twilightRepo)
+	l.Printf("loading Twilight...")
+
+	seasonRepo := seasons.NewSeasonRepository()
+	repositories = append(repositories, seasonRepo)
+	l.Printf("loading Seasons...")
+
+	occultationRepo, err := occultations.NewOccultationRepository(c.OccultationMagnitude())
+	if err != nil {
+		l.Fatalf("loading occultations: %s", err)
+	}
+	repositories = append(repositories, occultationRepo)
+	l.Printf("loading Occultations...")
+
+	jupiterMoonsRepo := jupitermoons.NewJupiterMoonsRepository()
+	repositories = append(repositories, jupiterMoonsRepo)
+	l.Printf("loading Jupiter Moons...")
+
+	deepSkyRepo, err := deepsky.NewDeepSkyRepository(c.DeepSkyMinAltitude())
+	if err != nil {
+		l.Fatalf("loading deep-sky catalog: %s", err)
+	}
+	repositories = append(repositories, deepSkyRepo)
+	l.Printf("loading Deep Sky...")
+
+	variableStarRepo, err := variablestars.NewVariableStarRepository()
+	if err != nil {
+		l.Fatalf("loading variable stars: %s", err)
+	}
+	repositories = append(repositories, variableStarRepo)
+	l.Printf("loading Variable Stars...")
+
+	if c.TLEPath() != "" {
+		satelliteRepo, err := satellites.NewSatelliteRepository(c.TLEPath(), c.SatelliteMinElevation())
+		if err != nil {
+			l.Fatalf("loading satellites: %s", err)
+		}
+		repositories = append(repositories, satelliteRepo)
+		l.Printf("loading Satellites...")
+	}
+
+	if c.SmallBodyPath() != "" {
+		smallBodyRepo, err := smallbodies.NewSmallBodyRepository(c.SmallBodyPath(), c.SmallBodyMagnitude())
+		if err != nil {
+			l.Fatalf("loading small bodies: %s", err)
+		}
+		repositories = append(repositories, smallBodyRepo)
+		l.Printf("loading Small Bodies...")
+	}
+
 	if c.NasaAPIKey() != "" {
 		nasaRepo := nasaapi.NewNASARepository(c.NasaAPIKey())
 		repositories = append(repositories, nasaRepo)
 		l.Printf("loading NasaAPI...")
+
+		repositories = append(repositories, aurora.NewAuroraRepository(nasaRepo))
+		l.Printf("loading Aurora...")
+
+		repositories = append(repositories, neows.NewNeoWsRepository(c.NasaAPIKey()))
+		l.Printf("loading NeoWs...")
 	}
 
+	if c.Fireballs() {
+		repositories = append(repositories, fireballs.NewFireballRepository())
+		l.Printf("loading Fireballs...")
+	}
+
+	if c.LaunchURL() != "" {
+		repositories = append(repositories, launches.NewLaunchRepository(c.LaunchURL()))
+		l.Printf("loading Launches...")
+	}
+
+	if c.ICalFeeds() != "" {
+		categories, err := ical.ParseCategories(c.ICalCategories())
+		if err != nil {
+			l.Fatalf("loading iCalendar categories: %s", err)
+		}
+		for _, feed := range strings.Split(c.ICalFeeds(), ",") {
+			if feed = strings.TrimSpace(feed); feed == "" {
+				continue
+			}
+			calendarRepo := ical.NewCalendarRepository(feed, categories)
+			repositories = append(repositories, calendarRepo)
+			l.Printf("loading %s...", calendarRepo.Name())
+		}
+	}
+
+	horizonsRepo := horizons.NewHorizonsRepository(c.HorizonsURL(), c.HorizonsBodies())
+	repositories = append(repositories, horizonsRepo)
+	l.Printf("loading Horizons...")
+
 	// Initialize service
 	eventService := service.NewEventService(repositories)
 
 	// Initialize REST handler
-	handler := rest.NewHandler(eventService)
+	handler := rest.NewHandler(eventService, observer, locations, horizonsRepo)
 
 	// Create router and register routes
 	router := gin.Default()
+	// Body IDs such as "C/2023 A3" hold escaped slashes
+	router.UseRawPath = true
 	handler.RegisterRoutes(router)
 
 	l.Printf("Server starting on port %s", c.APIPort())
@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -26,19 +27,79 @@ const (
   @@@@@@@@@@@@@@@@
  @@@@@@@@@@@@@@@@@@
 @@@@@@@@@@@@@@@@@@@@
+`
+	newMoonArt = `
+      .-----.
+    /         \
+   |           |
+    \         /
+      '-----'
+`
+	firstQuarterArt = `
+      .--@@@.
+    /    @@@@@\
+   |     @@@@@@|
+    \    @@@@@/
+      '--@@@'
+`
+	fullMoonArt = `
+      .@@@@@.
+    @@@@@@@@@@@
+   @@@@@@@@@@@@@
+    @@@@@@@@@@@
+      '@@@@@'
+`
+	lastQuarterArt = `
+      .@@@--.
+    /@@@@@    \
+   |@@@@@@     |
+    \@@@@@    /
+      '@@@--'
 `
 )
 
+// moonPhaseArt returns the ASCII art for a lunar phase
+func moonPhaseArt(moon *domain.MoonDetails) string {
+	if moon == nil {
+		return ""
+	}
+	switch moon.Phase {
+	case domain.NewMoon:
+		return newMoonArt
+	case domain.FirstQuarter:
+		return firstQuarterArt
+	case domain.FullMoon:
+		return fullMoonArt
+	case domain.LastQuarter:
+		return lastQuarterArt
+	}
+	return ""
+}
+
 func main() {
 	baseURL := flag.String("api", "http://localhost:8080", "Base URL of the Astralis API")
+	location := flag.String("location", "", "Saved location name known to the API")
+	lat := flag.String("lat", "", "Observer latitude in degrees, north positive")
+	lon := flag.String("lon", "", "Observer longitude in degrees, east positive")
+	elevation := flag.String("elevation", "", "Observer elevation in meters")
+	tz := flag.String("tz", "", "Observer IANA time zone")
 	flag.Parse()
 
 	// Get current time in RFC3339 format
 	now := time.Now().Format(time.RFC3339)
 	endTime := time.Now().AddDate(0, 1, 0).Format(time.RFC3339)
 
+	query := url.Values{"start": {now}, "end": {endTime}}
+	for key, value := range map[string]string{
+		"location": *location, "lat": *lat, "lon": *lon, "elevation": *elevation, "tz": *tz,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+
 	// Fetch events from the API
-	resp, err := http.Get(fmt.Sprintf("%s/events?start=%s&end=%s", *baseURL, now, endTime))
+	resp, err := http.Get(fmt.Sprintf("%s/events?%s", *baseURL, query.Encode()))
 	if err != nil {
 		fmt.Printf("Error fetching events: %v\n", err)
 		os.Exit(1)
@@ -68,11 +129,13 @@ func main() {
 		fmt.Printf("Description: %s\n", event.Description)
 
 		// Display ASCII art based on event type
-		switch event.Type {
-		case domain.MeteorShower:
+		switch art := moonPhaseArt(event.Moon); {
+		case event.Type == domain.MeteorShower:
 			fmt.Print(meteorShowerArt)
-		case domain.Eclipse:
+		case event.Type == domain.Eclipse:
 			fmt.Print(eclipseArt)
+		case art != "":
+			fmt.Print(art)
 		default:
 			fmt.Println("*    *    *")
 		}
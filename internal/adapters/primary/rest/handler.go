@@ -1,7 +1,11 @@
 package rest
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -10,20 +14,137 @@ import (
 	"astralis/internal/core/ports"
 )
 
+// maxEphemerisRows bounds the size of the tables returned by
+// GET /bodies/:id/ephemeris
+const maxEphemerisRows = 5000
+
+// maxRangeDays bounds the time range of GET /events and
+// GET /events/type/:type, as sources such as the NeoWs feed are fetched a
+// page at a time across the range
+const maxRangeDays = 366
+
 type Handler struct {
 	service ports.EventService
+	// observer is used when a request does not name a location
+	observer  domain.Observer
+	locations map[string]domain.Observer
+	// ephemeris tabulates the positions of bodies; without it the
+	// ephemeris endpoint is unavailable
+	ephemeris ports.EphemerisRepository
 }
 
-func NewHandler(service ports.EventService) *Handler {
+func NewHandler(service ports.EventService, observer domain.Observer, locations map[string]domain.Observer, ephemeris ports.EphemerisRepository) *Handler {
 	return &Handler{
-		service: service,
+		service:   service,
+		observer:  observer,
+		locations: locations,
+		ephemeris: ephemeris,
 	}
 }
 
 func (h *Handler) RegisterRoutes(router *gin.Engine) {
 	router.GET("/events", h.GetEvents)
 	router.GET("/events/:id", h.GetEventByID)
+	router.GET("/events/:id/related", h.GetRelatedEvents)
 	router.GET("/events/type/:type", h.GetEventsByType)
+	router.GET("/locations", h.GetLocations)
+	router.GET("/bodies/:id/ephemeris", h.GetEphemeris)
+}
+
+// requestObserver resolves the observer of a request. A saved location is
+// picked with location, explicit coordinates are given with lat and lon, and
+// elevation and tz refine either; without any, the default observer is used.
+func (h *Handler) requestObserver(c *gin.Context) (domain.Observer, error) {
+	observer := h.observer
+
+	if name := c.Query("location"); name != "" {
+		saved, ok := h.locations[name]
+		if !ok {
+			return domain.Observer{}, fmt.Errorf("unknown location %q", name)
+		}
+		observer = saved
+	}
+
+	latStr, lonStr := c.Query("lat"), c.Query("lon")
+	if (latStr == "") != (lonStr == "") {
+		return domain.Observer{}, errors.New("both lat and lon are required")
+	}
+	if latStr != "" {
+		lat, err := strconv.ParseFloat(latStr, 64)
+		if err != nil {
+			return domain.Observer{}, errors.New("invalid lat format")
+		}
+		lon, err := strconv.ParseFloat(lonStr, 64)
+		if err != nil {
+			return domain.Observer{}, errors.New("invalid lon format")
+		}
+		// The elevation and time zone of the saved or default location
+		// still apply unless overridden below
+		observer.Name = ""
+		observer.Latitude, observer.Longitude = lat, lon
+	}
+
+	if elevationStr := c.Query("elevation"); elevationStr != "" {
+		elevation, err := strconv.ParseFloat(elevationStr, 64)
+		if err != nil {
+			return domain.Observer{}, errors.New("invalid elevation format")
+		}
+		observer.Elevation = elevation
+	}
+	if tz := c.Query("tz"); tz != "" {
+		observer.TimeZone = tz
+	}
+
+	if !observer.IsValid() {
+		return domain.Observer{}, errors.New("observer coordinates or time zone out of range")
+	}
+	return observer.WithLocation(), nil
+}
+
+// requestMaxDistance reads max_distance, the distance in kilometers from the
+// observer beyond which events placed on Earth, such as fireballs, are
+// dropped. Zero means no limit.
+func requestMaxDistance(c *gin.Context) (float64, error) {
+	maxDistanceStr := c.Query("max_distance")
+	if maxDistanceStr == "" {
+		return 0, nil
+	}
+	maxDistance, err := strconv.ParseFloat(maxDistanceStr, 64)
+	if err != nil || maxDistance <= 0 {
+		return 0, errors.New("invalid max_distance, expected a positive number of kilometers")
+	}
+	return maxDistance, nil
+}
+
+// withinDistance keeps the events that are not placed on Earth and those
+// placed within maxDistance kilometers of the observer
+func withinDistance(events []domain.Event, observer domain.Observer, maxDistance float64) []domain.Event {
+	if maxDistance == 0 {
+		return events
+	}
+	result := make([]domain.Event, 0, len(events))
+	for _, event := range events {
+		if lat, lon, ok := event.Position(); ok && observer.DistanceKm(lat, lon) > maxDistance {
+			continue
+		}
+		result = append(result, event)
+	}
+	return result
+}
+
+func (h *Handler) GetLocations(c *gin.Context) {
+	locations := make([]domain.Observer, 0, len(h.locations))
+	for _, location := range h.locations {
+		locations = append(locations, location)
+	}
+	sort.Slice(locations, func(i, j int) bool {
+		return locations[i].Name < locations[j].Name
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"default":   h.observer,
+		"locations": locations,
+	})
 }
 
 func (h *Handler) GetEvents(c *gin.Context) {
@@ -52,27 +173,49 @@ func (h *Handler) GetEvents(c *gin.Context) {
 	} else {
 		end = start.AddDate(0, 1, 0) // Default to 1 month range
 	}
+	if end.Before(start) || end.Sub(start) > maxRangeDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Range must run forward and span at most %d days", maxRangeDays)})
+		return
+	}
+
+	observer, err := h.requestObserver(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	maxDistance, err := requestMaxDistance(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	timeRange := domain.TimeRange{
 		Start: start,
 		End:   end,
 	}
 
-	events, err := h.service.GetUpcomingEvents(c.Request.Context(), timeRange)
+	events, err := h.service.GetUpcomingEvents(c.Request.Context(), timeRange, observer)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"events": events,
+		"events": withinDistance(events, observer, maxDistance),
 	})
 }
 
 func (h *Handler) GetEventByID(c *gin.Context) {
 	id := c.Param("id")
 
-	event, err := h.service.GetEventByID(c.Request.Context(), id)
+	observer, err := h.requestObserver(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	event, err := h.service.GetEventByID(c.Request.Context(), id, observer)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -88,6 +231,40 @@ func (h *Handler) GetEventByID(c *gin.Context) {
 	})
 }
 
+// GetRelatedEvents returns an event along with the events it links to, such
+// as the coronal mass ejection that followed a flare
+func (h *Handler) GetRelatedEvents(c *gin.Context) {
+	id := c.Param("id")
+
+	observer, err := h.requestObserver(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	event, err := h.service.GetEventByID(c.Request.Context(), id, observer)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if event == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Object not found"})
+		return
+	}
+
+	related, err := h.service.GetRelatedEvents(c.Request.Context(), *event, observer)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"event":   event,
+		"related": related,
+	})
+}
+
 func (h *Handler) GetEventsByType(c *gin.Context) {
 	eventType := domain.EventType(c.Param("type"))
 
@@ -116,19 +293,106 @@ func (h *Handler) GetEventsByType(c *gin.Context) {
 	} else {
 		end = start.AddDate(0, 0, 1) // Default to 1 day range
 	}
+	if end.Before(start) || end.Sub(start) > maxRangeDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Range must run forward and span at most %d days", maxRangeDays)})
+		return
+	}
+
+	observer, err := h.requestObserver(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	maxDistance, err := requestMaxDistance(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	timeRange := domain.TimeRange{
 		Start: start,
 		End:   end,
 	}
 
-	events, err := h.service.GetEventsByType(c.Request.Context(), eventType, timeRange)
+	events, err := h.service.GetEventsByType(c.Request.Context(), eventType, timeRange, observer)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"events": events,
+		"events": withinDistance(events, observer, maxDistance),
+	})
+}
+
+// GetEphemeris returns a table of the positions of a body seen by the
+// observer from start to end, every step
+func (h *Handler) GetEphemeris(c *gin.Context) {
+	if h.ephemeris == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "No ephemeris source configured"})
+		return
+	}
+
+	start, end := time.Now(), time.Time{}
+	var err error
+	if startStr := c.Query("start"); startStr != "" {
+		start, err = time.Parse(time.RFC3339, startStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format"})
+			return
+		}
+	}
+	if endStr := c.Query("end"); endStr != "" {
+		end, err = time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format"})
+			return
+		}
+	} else {
+		end = start.AddDate(0, 0, 1) // Default to 1 day range
+	}
+	if end.Before(start) || end.Sub(start) > maxRangeDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Range must run forward and span at most %d days", maxRangeDays)})
+		return
+	}
+
+	step := time.Hour
+	if stepStr := c.Query("step"); stepStr != "" {
+		step, err = time.ParseDuration(stepStr)
+		if err != nil || step < time.Minute {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid step, expected a duration of at least 1m"})
+			return
+		}
+	}
+	if end.Before(start) || end.Sub(start)/step > maxEphemerisRows {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Range must run forward and span at most %d steps", maxEphemerisRows)})
+		return
+	}
+
+	observer, err := h.requestObserver(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	timeRange := domain.TimeRange{
+		Start: start,
+		End:   end,
+	}
+
+	ephemeris, err := h.ephemeris.GetEphemeris(c.Request.Context(), c.Param("id"), timeRange, step, observer)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if ephemeris == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Body not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ephemeris": ephemeris,
 	})
 }
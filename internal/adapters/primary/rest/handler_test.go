@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sort"
 	"testing"
 	"time"
 
@@ -17,6 +18,8 @@ import (
 // mockService implements ports.EventService for testing
 type mockService struct {
 	events map[string]domain.Event
+	// observer records the observer of the last query
+	observer domain.Observer
 }
 
 func newMockService() *mockService {
@@ -29,6 +32,7 @@ func newMockService() *mockService {
 			StartTime:   now.Add(1 * time.Hour),
 			EndTime:     now.Add(24 * time.Hour),
 			Type:        domain.MeteorShower,
+			Related:     []domain.EventLink{{ID: "test-2", Relation: domain.FollowedBy}},
 		},
 		"test-2": {
 			ID:          "test-2",
@@ -43,7 +47,8 @@ func newMockService() *mockService {
 	return &mockService{events: events}
 }
 
-func (s *mockService) GetUpcomingEvents(_ context.Context, timeRange domain.TimeRange) ([]domain.Event, error) {
+func (s *mockService) GetUpcomingEvents(_ context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	s.observer = observer
 	var result []domain.Event
 	for _, event := range s.events {
 		if event.StartTime.After(timeRange.Start) && event.StartTime.Before(timeRange.End) {
@@ -53,14 +58,16 @@ func (s *mockService) GetUpcomingEvents(_ context.Context, timeRange domain.Time
 	return result, nil
 }
 
-func (s *mockService) GetEventByID(_ context.Context, id string) (*domain.Event, error) {
+func (s *mockService) GetEventByID(_ context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	s.observer = observer
 	if event, ok := s.events[id]; ok {
 		return &event, nil
 	}
 	return nil, nil
 }
 
-func (s *mockService) GetEventsByType(_ context.Context, eventType domain.EventType, timeRange domain.TimeRange) ([]domain.Event, error) {
+func (s *mockService) GetEventsByType(_ context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	s.observer = observer
 	var result []domain.Event
 	for _, event := range s.events {
 		if event.Type == eventType && event.StartTime.After(timeRange.Start) && event.StartTime.Before(timeRange.End) {
@@ -70,7 +77,19 @@ func (s *mockService) GetEventsByType(_ context.Context, eventType domain.EventT
 	return result, nil
 }
 
-func (s *mockService) GetEventsByDate(_ context.Context, date time.Time) ([]domain.Event, error) {
+func (s *mockService) GetRelatedEvents(_ context.Context, event domain.Event, observer domain.Observer) ([]domain.Event, error) {
+	s.observer = observer
+	var result []domain.Event
+	for _, link := range event.Related {
+		if related, ok := s.events[link.ID]; ok {
+			result = append(result, related)
+		}
+	}
+	return result, nil
+}
+
+func (s *mockService) GetEventsByDate(_ context.Context, date time.Time, observer domain.Observer) ([]domain.Event, error) {
+	s.observer = observer
 	var result []domain.Event
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
@@ -83,7 +102,8 @@ func (s *mockService) GetEventsByDate(_ context.Context, date time.Time) ([]doma
 	return result, nil
 }
 
-func (s *mockService) GetEventsByDateRange(_ context.Context, start, end time.Time) ([]domain.Event, error) {
+func (s *mockService) GetEventsByDateRange(_ context.Context, start, end time.Time, observer domain.Observer) ([]domain.Event, error) {
+	s.observer = observer
 	var result []domain.Event
 	for _, event := range s.events {
 		if event.StartTime.After(start) && event.StartTime.Before(end) {
@@ -93,9 +113,27 @@ func (s *mockService) GetEventsByDateRange(_ context.Context, start, end time.Ti
 	return result, nil
 }
 
+// mockEphemeris implements ports.EphemerisRepository for testing
+type mockEphemeris struct {
+	// step records the step of the last query
+	step time.Duration
+}
+
+func (e *mockEphemeris) GetEphemeris(_ context.Context, body string, timeRange domain.TimeRange, step time.Duration, observer domain.Observer) (*domain.Ephemeris, error) {
+	e.step = step
+	if body != "C/2023 A3" {
+		return nil, nil
+	}
+	ephemeris := &domain.Ephemeris{Body: body, Name: "C/2023 A3 (Tsuchinshan-ATLAS)", Observer: observer}
+	for t := timeRange.Start; !t.After(timeRange.End); t = t.Add(step) {
+		ephemeris.Rows = append(ephemeris.Rows, domain.EphemerisRow{Time: t})
+	}
+	return ephemeris, nil
+}
+
 func TestHandler_GetEvents(t *testing.T) {
 	mockSvc := newMockService()
-	handler := NewHandler(mockSvc)
+	handler := NewHandler(mockSvc, domain.Observer{}, nil, nil)
 
 	router := gin.Default()
 	handler.RegisterRoutes(router)
@@ -134,6 +172,24 @@ func TestHandler_GetEvents(t *testing.T) {
 			wantStatus: http.StatusBadRequest,
 			wantCount:  0,
 		},
+		{
+			name:       "range of the longest length",
+			url:        fmt.Sprintf("/events?start=%s&end=%s", startTime, now.AddDate(0, 0, maxRangeDays).Format(time.RFC3339)),
+			wantStatus: http.StatusOK,
+			wantCount:  2,
+		},
+		{
+			name:       "range too long",
+			url:        fmt.Sprintf("/events?start=%s&end=%s", startTime, now.AddDate(0, 0, maxRangeDays+1).Format(time.RFC3339)),
+			wantStatus: http.StatusBadRequest,
+			wantCount:  0,
+		},
+		{
+			name:       "range ending before it starts",
+			url:        fmt.Sprintf("/events?start=%s&end=%s", endTime, startTime),
+			wantStatus: http.StatusBadRequest,
+			wantCount:  0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -165,7 +221,7 @@ func TestHandler_GetEvents(t *testing.T) {
 
 func TestHandler_GetEventByID(t *testing.T) {
 	mockSvc := newMockService()
-	handler := NewHandler(mockSvc)
+	handler := NewHandler(mockSvc, domain.Observer{}, nil, nil)
 
 	router := gin.Default()
 	handler.RegisterRoutes(router)
@@ -217,9 +273,77 @@ func TestHandler_GetEventByID(t *testing.T) {
 	}
 }
 
+func TestHandler_GetRelatedEvents(t *testing.T) {
+	mockSvc := newMockService()
+	handler := NewHandler(mockSvc, domain.Observer{}, nil, nil)
+
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	tests := []struct {
+		name        string
+		eventID     string
+		wantStatus  int
+		wantRelated []string
+	}{
+		{
+			name:        "linked event",
+			eventID:     "test-1",
+			wantStatus:  http.StatusOK,
+			wantRelated: []string{"test-2"},
+		},
+		{
+			name:        "event without links",
+			eventID:     "test-2",
+			wantStatus:  http.StatusOK,
+			wantRelated: nil,
+		},
+		{
+			name:       "non-existing event",
+			eventID:    "non-existing",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/events/"+tt.eventID+"/related", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetRelatedEvents() status code = %v, want %v", w.Code, tt.wantStatus)
+			}
+
+			if tt.wantStatus == http.StatusOK {
+				var response struct {
+					Event   domain.Event   `json:"event"`
+					Related []domain.Event `json:"related"`
+				}
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Errorf("GetRelatedEvents() error decoding response = %v", err)
+				}
+
+				if response.Event.ID != tt.eventID {
+					t.Errorf("GetRelatedEvents() event ID = %v, want %v", response.Event.ID, tt.eventID)
+				}
+				if len(response.Related) != len(tt.wantRelated) {
+					t.Fatalf("GetRelatedEvents() returned %d related events, want %d", len(response.Related), len(tt.wantRelated))
+				}
+				for i, id := range tt.wantRelated {
+					if response.Related[i].ID != id {
+						t.Errorf("GetRelatedEvents() related ID = %v, want %v", response.Related[i].ID, id)
+					}
+				}
+			}
+		})
+	}
+}
+
 func TestHandler_GetEventsByType(t *testing.T) {
 	mockSvc := newMockService()
-	handler := NewHandler(mockSvc)
+	handler := NewHandler(mockSvc, domain.Observer{}, nil, nil)
 
 	router := gin.Default()
 	handler.RegisterRoutes(router)
@@ -276,6 +400,14 @@ func TestHandler_GetEventsByType(t *testing.T) {
 			wantStatus: http.StatusBadRequest,
 			wantCount:  0,
 		},
+		{
+			name:       "range too long",
+			eventType:  domain.MeteorShower,
+			startTime:  startTime,
+			endTime:    now.AddDate(0, 0, maxRangeDays+1).Format(time.RFC3339),
+			wantStatus: http.StatusBadRequest,
+			wantCount:  0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -312,3 +444,292 @@ func TestHandler_GetEventsByType(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_Observer(t *testing.T) {
+	mockSvc := newMockService()
+	defaultObserver := domain.Observer{Latitude: 51.4769, TimeZone: "Europe/London"}
+	home := domain.Observer{Name: "home", Latitude: 40.7128, Longitude: -74.0060, Elevation: 10, TimeZone: "America/New_York"}
+	handler := NewHandler(mockSvc, defaultObserver, map[string]domain.Observer{"home": home}, nil)
+
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	tests := []struct {
+		name         string
+		query        string
+		wantStatus   int
+		wantObserver domain.Observer
+	}{
+		{
+			name:         "default observer",
+			query:        "",
+			wantStatus:   http.StatusOK,
+			wantObserver: defaultObserver,
+		},
+		{
+			name:         "saved location",
+			query:        "?location=home",
+			wantStatus:   http.StatusOK,
+			wantObserver: home,
+		},
+		{
+			name:         "coordinates",
+			query:        "?lat=-33.8688&lon=151.2093&elevation=58&tz=Australia/Sydney",
+			wantStatus:   http.StatusOK,
+			wantObserver: domain.Observer{Latitude: -33.8688, Longitude: 151.2093, Elevation: 58, TimeZone: "Australia/Sydney"},
+		},
+		{
+			name:         "coordinates near a saved location",
+			query:        "?location=home&lat=40.7812&lon=-73.9665",
+			wantStatus:   http.StatusOK,
+			wantObserver: domain.Observer{Latitude: 40.7812, Longitude: -73.9665, Elevation: 10, TimeZone: "America/New_York"},
+		},
+		{
+			name:       "unknown location",
+			query:      "?location=cabin",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "latitude without longitude",
+			query:      "?lat=10",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "latitude out of range",
+			query:      "?lat=100&lon=0",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown time zone",
+			query:      "?tz=Nowhere/Special",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		for _, path := range []string{"/events", "/events/type/ECLIPSE", "/events/test-1"} {
+			t.Run(tt.name+" "+path, func(t *testing.T) {
+				mockSvc.observer = domain.Observer{}
+				req := httptest.NewRequest(http.MethodGet, path+tt.query, nil)
+				w := httptest.NewRecorder()
+
+				router.ServeHTTP(w, req)
+
+				if w.Code != tt.wantStatus {
+					t.Errorf("status code = %v, want %v", w.Code, tt.wantStatus)
+				}
+				got := mockSvc.observer
+				if got.Name != tt.wantObserver.Name || got.Latitude != tt.wantObserver.Latitude || got.Longitude != tt.wantObserver.Longitude ||
+					got.Elevation != tt.wantObserver.Elevation || got.TimeZone != tt.wantObserver.TimeZone {
+					t.Errorf("observer = %v, want %v", got, tt.wantObserver)
+				}
+				if tt.wantStatus == http.StatusOK && got.Location().String() != tt.wantObserver.Location().String() {
+					t.Errorf("observer location = %v, want %v", got.Location(), tt.wantObserver.Location())
+				}
+			})
+		}
+	}
+}
+
+func TestHandler_MaxDistance(t *testing.T) {
+	mockSvc := newMockService()
+	now := time.Now()
+	for id, place := range map[string][2]float64{"fireball-near": {41.2, -72.9}, "fireball-far": {-24.3, 147.1}} {
+		mockSvc.events[id] = domain.Event{
+			ID:          id,
+			Title:       "Fireball",
+			Description: "A fireball",
+			StartTime:   now.Add(3 * time.Hour),
+			Type:        domain.Fireball,
+			Fireball:    &domain.FireballDetails{Latitude: place[0], Longitude: place[1]},
+		}
+	}
+	handler := NewHandler(mockSvc, domain.Observer{Latitude: 40.7128, Longitude: -74.0060}, nil, nil)
+
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+		wantIDs    []string
+	}{
+		{
+			name:       "without a limit",
+			url:        "/events/type/FIREBALL",
+			wantStatus: http.StatusOK,
+			wantIDs:    []string{"fireball-far", "fireball-near"},
+		},
+		{
+			name:       "nearby fireballs",
+			url:        "/events/type/FIREBALL?max_distance=500",
+			wantStatus: http.StatusOK,
+			wantIDs:    []string{"fireball-near"},
+		},
+		{
+			name:       "events without a place are kept",
+			url:        "/events?max_distance=500",
+			wantStatus: http.StatusOK,
+			wantIDs:    []string{"fireball-near", "test-1", "test-2"},
+		},
+		{
+			name:       "distance from another observer",
+			url:        "/events/type/FIREBALL?max_distance=1000&lat=-27.47&lon=153.03",
+			wantStatus: http.StatusOK,
+			wantIDs:    []string{"fireball-far"},
+		},
+		{
+			name:       "invalid distance",
+			url:        "/events?max_distance=-1",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status code = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Events []domain.Event `json:"events"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("error decoding response = %v", err)
+			}
+			var ids []string
+			for _, event := range response.Events {
+				ids = append(ids, event.ID)
+			}
+			sort.Strings(ids)
+			if fmt.Sprint(ids) != fmt.Sprint(tt.wantIDs) {
+				t.Errorf("events = %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestHandler_GetLocations(t *testing.T) {
+	home := domain.Observer{Name: "home", Latitude: 40.7128, Longitude: -74.0060}
+	handler := NewHandler(newMockService(), domain.Observer{}, map[string]domain.Observer{"home": home}, nil)
+
+	router := gin.Default()
+	handler.RegisterRoutes(router)
+
+	req := httptest.NewRequest(http.MethodGet, "/locations", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetLocations() status code = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	var response struct {
+		Locations []domain.Observer `json:"locations"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("GetLocations() error decoding response = %v", err)
+	}
+	if len(response.Locations) != 1 || response.Locations[0] != home {
+		t.Errorf("GetLocations() = %v, want [%v]", response.Locations, home)
+	}
+}
+
+func TestHandler_GetEphemeris(t *testing.T) {
+	ephemeris := &mockEphemeris{}
+	handler := NewHandler(newMockService(), domain.Observer{}, nil, ephemeris)
+
+	// As in cmd/api, so that escaped slashes stay within the body ID
+	router := gin.Default()
+	router.UseRawPath = true
+	handler.RegisterRoutes(router)
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+		wantRows   int
+		wantStep   time.Duration
+	}{
+		{
+			name:       "body with a slash",
+			url:        "/bodies/C%2F2023%20A3/ephemeris?start=2024-10-12T00:00:00Z&end=2024-10-13T00:00:00Z",
+			wantStatus: http.StatusOK,
+			wantRows:   25,
+			wantStep:   time.Hour,
+		},
+		{
+			name:       "custom step",
+			url:        "/bodies/C%2F2023%20A3/ephemeris?start=2024-10-12T00:00:00Z&end=2024-10-12T01:00:00Z&step=10m",
+			wantStatus: http.StatusOK,
+			wantRows:   7,
+			wantStep:   10 * time.Minute,
+		},
+		{
+			name:       "unknown body",
+			url:        "/bodies/vulcan/ephemeris",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "invalid step",
+			url:        "/bodies/C%2F2023%20A3/ephemeris?step=10s",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "too many steps",
+			url:        "/bodies/C%2F2023%20A3/ephemeris?start=2024-01-01T00:00:00Z&end=2025-01-01T00:00:00Z&step=1m",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "backwards range",
+			url:        "/bodies/C%2F2023%20A3/ephemeris?start=2024-10-12T00:00:00Z&end=2024-10-11T00:00:00Z",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GetEphemeris() status code = %v, want %v", w.Code, tt.wantStatus)
+			}
+
+			if tt.wantStatus == http.StatusOK {
+				var response struct {
+					Ephemeris domain.Ephemeris `json:"ephemeris"`
+				}
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Fatalf("GetEphemeris() error decoding response = %v", err)
+				}
+				if response.Ephemeris.Body != "C/2023 A3" || len(response.Ephemeris.Rows) != tt.wantRows {
+					t.Errorf("GetEphemeris() = %s with %d rows, want C/2023 A3 with %d", response.Ephemeris.Body, len(response.Ephemeris.Rows), tt.wantRows)
+				}
+				if ephemeris.step != tt.wantStep {
+					t.Errorf("GetEphemeris() step = %v, want %v", ephemeris.step, tt.wantStep)
+				}
+			}
+		})
+	}
+
+	// Without an ephemeris source the endpoint is unavailable
+	router = gin.Default()
+	NewHandler(newMockService(), domain.Observer{}, nil, nil).RegisterRoutes(router)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/bodies/499/ephemeris", nil))
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("GetEphemeris() without a source status code = %v, want %v", w.Code, http.StatusNotImplemented)
+	}
+}
@@ -0,0 +1,64 @@
+// Package apitest helps test the adapters of remote APIs against a stand-in
+// for the API that answers from responses recorded in testdata
+package apitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// Server stands in for a remote API, answering with a handler written for
+// that API and recording the requests it was sent
+type Server struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []*url.URL
+}
+
+// NewServer starts a server answering with handler, closed when the test
+// ends
+func NewServer(t testing.TB, handler http.HandlerFunc) *Server {
+	t.Helper()
+	s := &Server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.URL)
+		s.mu.Unlock()
+		handler(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Requests returns the URLs of the requests sent so far, in order
+func (s *Server) Requests() []*url.URL {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*url.URL(nil), s.requests...)
+}
+
+// ReadFile returns the content of a file in testdata, failing the test when
+// it cannot be read
+func ReadFile(t testing.TB, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// ReadJSON decodes a file in testdata into v, failing the test when it
+// cannot be read or decoded
+func ReadJSON(t testing.TB, name string, v any) {
+	t.Helper()
+	if err := json.Unmarshal(ReadFile(t, name), v); err != nil {
+		t.Fatalf("decoding %s: %v", name, err)
+	}
+}
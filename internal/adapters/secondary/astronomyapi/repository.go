@@ -5,24 +5,36 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
+	"astralis/internal/astro"
 	"astralis/internal/core/domain"
 )
 
 const (
-	baseURL = "https://api.visibleplanets.dev/v3"
+	visiblePlanetsURL = "https://api.visibleplanets.dev/v3"
 )
 
 type astronomyAPIRepository struct {
+	baseURL    string
 	httpClient *http.Client
 }
 
 type planetVisibility struct {
 	Name string `json:"name"`
-	Constellation string  `json:"constellation"`
-	Altitude      float64 `json:"altitude"`
-	Azimuth       float64 `json:"azimuth"`
+	// RightAscension is in hours and Declination in degrees, referred to
+	// the equinox of date
+	RightAscension angle   `json:"rightAscension"`
+	Declination    angle   `json:"declination"`
+	Altitude       float64 `json:"altitude"`
+	Azimuth        float64 `json:"azimuth"`
+}
+
+// angle is a coordinate the API writes in sexagesimal parts alongside its
+// decimal raw value
+type angle struct {
+	Raw float64 `json:"raw"`
 }
 
 type visibilityResponse struct {
@@ -32,14 +44,18 @@ type visibilityResponse struct {
 // NewAstronomyAPIRepository creates a new instance of the Astronomy API repository
 func NewAstronomyAPIRepository() *astronomyAPIRepository {
 	return &astronomyAPIRepository{
+		baseURL:    visiblePlanetsURL,
 		httpClient: &http.Client{Timeout: 10 * time.Second},
 	}
 }
 
-func (r *astronomyAPIRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange) ([]domain.Event, error) {
+func (r *astronomyAPIRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
 	// Get visible planets data
-	url := fmt.Sprintf("%s?latitude=32&longitude=-98&date=%s",
-		baseURL,
+	url := fmt.Sprintf("%s?latitude=%f&longitude=%f&elevation=%f&date=%s",
+		r.baseURL,
+		observer.Latitude,
+		observer.Longitude,
+		observer.Elevation,
 		timeRange.Start.Format("2006-01-02"),
 	)
 
@@ -63,17 +79,22 @@ func (r *astronomyAPIRepository) GetEvents(ctx context.Context, timeRange domain
 		return nil, fmt.Errorf("decoding visibility response: %w", err)
 	}
 
+	// The constellation is looked up from the position, as the local
+	// adapters do, rather than taken from the API
+	jde := astro.JDE(astro.JulianDay(timeRange.Start))
+
 	var events []domain.Event
 	for _, planet := range visResponse.Data {
+		constellation := domain.ConstellationAt(astro.Equatorial{RA: planet.RightAscension.Raw * 15, Dec: planet.Declination.Raw}, jde).Name
 		event := domain.Event{
 			ID:          fmt.Sprintf("planet-%s-%s", planet.Name, timeRange.Start.Format("2006-01-02")),
-			Title:       fmt.Sprintf("%s Visible in %s", planet.Name, planet.Constellation),
+			Title:       fmt.Sprintf("%s Visible in %s", planet.Name, constellation),
 			Description: fmt.Sprintf("%s is visible at altitude %.2f° and azimuth %.2f°",
 				planet.Name, planet.Altitude, planet.Azimuth),
 			StartTime:   timeRange.Start,
 			EndTime:     timeRange.Start.Add(24 * time.Hour),
 			Type:        domain.Transit,
-			Location:    planet.Constellation,
+			Location:    constellation,
 			Source:     "Visible Planets API",
 			Visibility: fmt.Sprintf("Altitude: %.2f°, Azimuth: %.2f°", 
 				planet.Altitude, planet.Azimuth),
@@ -83,7 +104,11 @@ func (r *astronomyAPIRepository) GetEvents(ctx context.Context, timeRange domain
 	return events, nil
 }
 
-func (r *astronomyAPIRepository) GetEventByID(ctx context.Context, id string) (*domain.Event, error) {
+func (r *astronomyAPIRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	if !strings.HasPrefix(id, "planet-") {
+		return nil, nil
+	}
+
 	// For this API, we'll need to fetch all events and filter by ID
 	now := time.Now()
 	timeRange := domain.TimeRange{
@@ -91,7 +116,7 @@ func (r *astronomyAPIRepository) GetEventByID(ctx context.Context, id string) (*
 		End:   now.AddDate(0, 0, 7), // Look forward 1 week
 	}
 
-	events, err := r.GetEvents(ctx, timeRange)
+	events, err := r.GetEvents(ctx, timeRange, observer)
 	if err != nil {
 		return nil, err
 	}
@@ -105,8 +130,8 @@ func (r *astronomyAPIRepository) GetEventByID(ctx context.Context, id string) (*
 	return nil, nil
 }
 
-func (r *astronomyAPIRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange) ([]domain.Event, error) {
-	events, err := r.GetEvents(ctx, timeRange)
+func (r *astronomyAPIRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	events, err := r.GetEvents(ctx, timeRange, observer)
 	if err != nil {
 		return nil, err
 	}
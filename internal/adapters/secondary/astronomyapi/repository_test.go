@@ -0,0 +1,49 @@
+package astronomyapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"astralis/internal/adapters/secondary/apitest"
+	"astralis/internal/core/domain"
+)
+
+var newYork = domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
+
+func TestAstronomyAPIRepository_GetEvents(t *testing.T) {
+	visible := apitest.ReadFile(t, "visible.json")
+	server := apitest.NewServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("date") != "2024-05-10" {
+			http.Error(w, "unexpected date", http.StatusBadRequest)
+			return
+		}
+		w.Write(visible)
+	})
+	repo := NewAstronomyAPIRepository()
+	repo.baseURL = server.URL
+
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 5, 10, 4, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 5, 11, 4, 0, 0, 0, time.UTC),
+	}
+	events, err := repo.GetEvents(context.Background(), timeRange, newYork)
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+
+	// The API places Mars in Aquarius, but its position is in Pisces
+	want := map[string]string{"planet-Mars-2024-05-10": "Pisces", "planet-Jupiter-2024-05-10": "Taurus"}
+	if len(events) != len(want) {
+		t.Fatalf("GetEvents() returned %d events, want %d", len(events), len(want))
+	}
+	for _, event := range events {
+		if event.Location != want[event.ID] {
+			t.Errorf("event %s Location = %q, want %q", event.ID, event.Location, want[event.ID])
+		}
+	}
+	if events[0].Title != "Mars Visible in Pisces" {
+		t.Errorf("Title = %q", events[0].Title)
+	}
+}
@@ -0,0 +1,288 @@
+package aurora
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+	"astralis/internal/core/ports"
+)
+
+const sourceName = "Aurora Forecast"
+
+// The equatorward edge of the auroral oval moves from about 66.5° to 48°
+// geomagnetic latitude as Kp rises from 0 to 9. Beyond the edge the aurora
+// can still be seen low over the poleward horizon up to horizonMargin
+// degrees away.
+const (
+	quietBoundary = 66.5
+	boundaryPerKp = 2.04
+	horizonMargin = 5.0
+)
+
+// A predicted coronal mass ejection impact is taken to keep Kp at its
+// predicted level for forecastHours after the arrival of the shock
+const forecastHours = 12
+
+// kpSpan is a stretch of time during which the planetary K index held, or is
+// predicted to hold, a value
+type kpSpan struct {
+	from, to   time.Time
+	kp         float64
+	forecast   bool
+	activityID string
+}
+
+type auroraRepository struct {
+	spaceWeather ports.EventRepository
+}
+
+// NewAuroraRepository creates a repository that derives the spells of
+// darkness during which the aurora may be seen by the requesting observer
+// from the geomagnetic storms and coronal mass ejection forecasts of a
+// space weather repository such as the NASA DONKI one
+func NewAuroraRepository(spaceWeather ports.EventRepository) *auroraRepository {
+	return &auroraRepository{spaceWeather: spaceWeather}
+}
+
+// boundary returns the geomagnetic latitude of the equatorward edge of the
+// auroral oval for a planetary K index
+func boundary(kp float64) float64 {
+	return quietBoundary - boundaryPerKp*kp
+}
+
+func (r *auroraRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	site := astro.Site{Latitude: observer.Latitude, Longitude: observer.Longitude, Elevation: observer.Elevation}
+	geomagnetic := astro.GeomagneticLatitude(site)
+	latitude := math.Abs(geomagnetic)
+
+	spans, err := r.kpSpans(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	// The dark parts of the spans during which the aurora may be seen are
+	// gathered into one spell a night. Spells are followed beyond the range
+	// to the end of the night, so that they do not depend on the range they
+	// were requested with.
+	var events []domain.Event
+	var current *spell
+	for _, s := range spans {
+		if latitude < boundary(s.kp)-horizonMargin {
+			continue
+		}
+		for _, dark := range darkness(site, s.from, s.to) {
+			if current != nil && observer.NightDate(dark[0]).Equal(observer.NightDate(current.from)) {
+				current.add(s, dark[1])
+				continue
+			}
+			if current != nil {
+				events = append(events, current.event(geomagnetic, observer, r.spaceWeather.Name()))
+			}
+			current = &spell{from: dark[0], to: dark[1]}
+			current.add(s, dark[1])
+		}
+	}
+	if current != nil {
+		events = append(events, current.event(geomagnetic, observer, r.spaceWeather.Name()))
+	}
+
+	var inRange []domain.Event
+	for _, event := range events {
+		if !event.StartTime.Before(timeRange.End) || event.EndTime.Before(timeRange.Start) {
+			continue
+		}
+		inRange = append(inRange, event)
+	}
+	return inRange, nil
+}
+
+// kpSpans collects the observed Kp of the storms around a time range, and
+// the predicted Kp of coronal mass ejections expected to arrive while no
+// observations are available, sorted by time
+func (r *auroraRepository) kpSpans(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]kpSpan, error) {
+	// Storms may have begun the day before, and ejections take up to about
+	// four days to reach the Earth
+	stormRange := domain.TimeRange{Start: timeRange.Start.AddDate(0, 0, -1), End: timeRange.End.AddDate(0, 0, 1)}
+	storms, err := r.spaceWeather.GetEventsByType(ctx, domain.GeomagneticStorm, stormRange, observer)
+	if err != nil {
+		return nil, fmt.Errorf("fetching geomagnetic storms: %w", err)
+	}
+	cmeRange := domain.TimeRange{Start: timeRange.Start.AddDate(0, 0, -5), End: timeRange.End}
+	cmes, err := r.spaceWeather.GetEventsByType(ctx, domain.CoronalMassEjection, cmeRange, observer)
+	if err != nil {
+		return nil, fmt.Errorf("fetching coronal mass ejections: %w", err)
+	}
+
+	var observed []kpSpan
+	for _, storm := range storms {
+		if storm.SpaceWeather == nil {
+			continue
+		}
+		// Each Kp closes a three-hour interval
+		for _, kp := range storm.SpaceWeather.KpIndices {
+			observed = append(observed, kpSpan{from: kp.Time.Add(-3 * time.Hour), to: kp.Time, kp: kp.Kp, activityID: storm.ID})
+		}
+	}
+
+	spans := observed
+	for _, cme := range cmes {
+		details := cme.SpaceWeather
+		if details == nil || details.PredictedArrival == nil {
+			continue
+		}
+		for h := 0; h < forecastHours; h += 3 {
+			s := kpSpan{
+				from:       details.PredictedArrival.Add(time.Duration(h) * time.Hour),
+				to:         details.PredictedArrival.Add(time.Duration(h+3) * time.Hour),
+				kp:         details.PredictedKpIndex,
+				forecast:   true,
+				activityID: cme.ID,
+			}
+			if !overlaps(s, observed) {
+				spans = append(spans, s)
+			}
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].from.Before(spans[j].from)
+	})
+	return spans, nil
+}
+
+func overlaps(s kpSpan, spans []kpSpan) bool {
+	for _, o := range spans {
+		if s.from.Before(o.to) && o.from.Before(s.to) {
+			return true
+		}
+	}
+	return false
+}
+
+// darkness returns the parts of a stretch of time during which the Sun is
+// below nautical twilight
+func darkness(site astro.Site, from, to time.Time) [][2]time.Time {
+	startJD, endJD := astro.JulianDay(from), astro.JulianDay(to)
+
+	var spans [][2]time.Time
+	start, dark := from, astro.Altitude(astro.SunApparent, startJD, site) < astro.NauticalTwilightAltitude
+	for _, he := range astro.HorizonEvents(astro.SunApparent, astro.NauticalTwilightAltitude, site, startJD, endJD) {
+		switch {
+		case he.Kind == astro.Setting && !dark:
+			start, dark = astro.EventTime(he.JD), true
+		case he.Kind == astro.Rising && dark:
+			spans = append(spans, [2]time.Time{start, astro.EventTime(he.JD)})
+			dark = false
+		}
+	}
+	if dark {
+		spans = append(spans, [2]time.Time{start, to})
+	}
+	return spans
+}
+
+// spell is the part of a night during which the aurora may be seen
+type spell struct {
+	from, to    time.Time
+	maxKp       float64
+	forecast    bool
+	activityIDs []string
+}
+
+func (s *spell) add(span kpSpan, to time.Time) {
+	if to.After(s.to) {
+		s.to = to
+	}
+	s.maxKp = math.Max(s.maxKp, span.kp)
+	s.forecast = s.forecast || span.forecast
+	if !slices.Contains(s.activityIDs, span.activityID) {
+		s.activityIDs = append(s.activityIDs, span.activityID)
+	}
+}
+
+// event describes the spell, linking it to the activities that caused it in
+// the space weather source
+func (s *spell) event(geomagnetic float64, observer domain.Observer, source string) domain.Event {
+	latitude := math.Abs(geomagnetic)
+	details := &domain.AuroraDetails{
+		MaxKpIndex:          s.maxKp,
+		Forecast:            s.forecast,
+		GeomagneticLatitude: geomagnetic,
+		BoundaryLatitude:    boundary(s.maxKp),
+		Overhead:            latitude >= boundary(s.maxKp),
+		ActivityIDs:         s.activityIDs,
+	}
+
+	title, visibility := "Aurora Possible", "Low over the northern horizon"
+	if geomagnetic < 0 {
+		visibility = "Low over the southern horizon"
+	}
+	if details.Overhead {
+		title, visibility = "Aurora Likely", "Overhead"
+	}
+	kind := "observed"
+	if details.Forecast {
+		kind = "forecast"
+	}
+	var related []domain.EventLink
+	for _, id := range s.activityIDs {
+		related = append(related, domain.EventLink{ID: id, Relation: domain.CausedBy, Source: source})
+	}
+
+	return domain.Event{
+		ID:    fmt.Sprintf("aurora-%s", observer.NightDate(s.from).Format("2006-01-02")),
+		Title: title,
+		Description: fmt.Sprintf("Geomagnetic activity up to Kp %.1f (%s) brings the auroral oval down to %.0f° geomagnetic latitude, against %.0f° for the observer, while the sky is dark from %s to %s",
+			s.maxKp, kind, details.BoundaryLatitude, latitude, observer.Clock(s.from), observer.Clock(s.to)),
+		StartTime:  s.from,
+		EndTime:    s.to,
+		Type:       domain.Aurora,
+		Visibility: visibility,
+		Source:     sourceName,
+		Related:    related,
+		Aurora:     details,
+	}
+}
+
+func (r *auroraRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs end with the date of the night
+	if !strings.HasPrefix(id, "aurora-") || len(id) < len("2006-01-02") {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", id[len(id)-len("2006-01-02"):])
+	if err != nil {
+		return nil, nil
+	}
+
+	timeRange := domain.TimeRange{Start: date.AddDate(0, 0, -1), End: date.AddDate(0, 0, 2)}
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.ID == id {
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *auroraRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	if eventType != domain.Aurora {
+		return nil, nil
+	}
+	return r.GetEvents(ctx, timeRange, observer)
+}
+
+func (r *auroraRepository) Name() string {
+	return sourceName
+}
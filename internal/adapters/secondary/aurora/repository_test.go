@@ -0,0 +1,235 @@
+package aurora
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+// stubSpaceWeather stands in for the DONKI repository
+type stubSpaceWeather struct {
+	storms, cmes []domain.Event
+	err          error
+}
+
+func (s stubSpaceWeather) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	return append(s.storms, s.cmes...), s.err
+}
+
+func (s stubSpaceWeather) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	return nil, s.err
+}
+
+func (s stubSpaceWeather) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	switch eventType {
+	case domain.GeomagneticStorm:
+		return s.storms, s.err
+	case domain.CoronalMassEjection:
+		return s.cmes, s.err
+	}
+	return nil, s.err
+}
+
+func (s stubSpaceWeather) Name() string {
+	return "stub"
+}
+
+var (
+	newYork = domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
+	miami   = domain.Observer{Latitude: 25.7617, Longitude: -80.1918, TimeZone: "America/New_York"}
+	london  = domain.Observer{Latitude: 51.5074, Longitude: -0.1278, TimeZone: "Europe/London"}
+	hobart  = domain.Observer{Latitude: -42.8821, Longitude: 147.3272, TimeZone: "Australia/Hobart"}
+)
+
+// storm returns a DONKI storm with consecutive three-hour Kp values, the
+// first closing at first
+func storm(id string, first time.Time, kps ...float64) domain.Event {
+	details := &domain.SpaceWeatherDetails{ActivityID: id}
+	for i, kp := range kps {
+		details.KpIndices = append(details.KpIndices, domain.KpIndex{Time: first.Add(time.Duration(3*i) * time.Hour), Kp: kp})
+	}
+	return domain.Event{ID: id, Type: domain.GeomagneticStorm, SpaceWeather: details}
+}
+
+func cme(id string, arrival time.Time, kp float64) domain.Event {
+	return domain.Event{
+		ID:           id,
+		Type:         domain.CoronalMassEjection,
+		SpaceWeather: &domain.SpaceWeatherDetails{ActivityID: id, PredictedArrival: &arrival, PredictedKpIndex: kp},
+	}
+}
+
+var may2024 = stubSpaceWeather{
+	storms: []domain.Event{
+		storm("2024-05-10T15:00:00-GST-001", time.Date(2024, 5, 10, 18, 0, 0, 0, time.UTC), 8.33, 9, 9, 8.67, 8),
+	},
+}
+
+var may2024Range = domain.TimeRange{
+	Start: time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC),
+	End:   time.Date(2024, 5, 12, 0, 0, 0, 0, time.UTC),
+}
+
+func TestAuroraRepository_GetEvents(t *testing.T) {
+	tests := []struct {
+		name         string
+		observer     domain.Observer
+		wantID       string
+		wantKp       float64
+		wantOverhead bool
+		visibility   string
+		// wantStart and wantEnd bound the dark part of the storm
+		wantStart, wantEnd time.Time
+	}{
+		{
+			// The Kp 9 intervals end before nautical dusk in New York
+			name:         "New York after dusk",
+			observer:     newYork,
+			wantID:       "aurora-2024-05-10",
+			wantKp:       8.67,
+			wantOverhead: true,
+			visibility:   "Overhead",
+			wantStart:    time.Date(2024, 5, 11, 1, 0, 0, 0, time.UTC),
+			wantEnd:      time.Date(2024, 5, 11, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "Hobart before dawn",
+			observer:     hobart,
+			wantID:       "aurora-2024-05-10",
+			wantKp:       9,
+			wantOverhead: true,
+			visibility:   "Overhead",
+			wantStart:    time.Date(2024, 5, 10, 15, 0, 0, 0, time.UTC),
+			wantEnd:      time.Date(2024, 5, 10, 20, 30, 0, 0, time.UTC),
+		},
+	}
+
+	repo := NewAuroraRepository(may2024)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := repo.GetEvents(context.Background(), may2024Range, tt.observer)
+			if err != nil {
+				t.Fatalf("GetEvents() error = %v", err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("GetEvents() returned %d events, want 1", len(events))
+			}
+			event := events[0]
+			if event.ID != tt.wantID || event.Type != domain.Aurora || event.Visibility != tt.visibility {
+				t.Errorf("event = %+v", event)
+			}
+			if event.StartTime.Before(tt.wantStart.Add(-30*time.Minute)) || event.StartTime.After(tt.wantStart.Add(30*time.Minute)) ||
+				event.EndTime.Before(tt.wantEnd.Add(-30*time.Minute)) || event.EndTime.After(tt.wantEnd.Add(30*time.Minute)) {
+				t.Errorf("spell from %v to %v, want about %v to %v", event.StartTime, event.EndTime, tt.wantStart, tt.wantEnd)
+			}
+			details := event.Aurora
+			if details.MaxKpIndex != tt.wantKp || details.Overhead != tt.wantOverhead || details.Forecast ||
+				len(details.ActivityIDs) != 1 || (details.GeomagneticLatitude < 0) != (tt.observer.Latitude < 0) {
+				t.Errorf("details = %+v", details)
+			}
+			if len(event.Related) != 1 || event.Related[0] != (domain.EventLink{ID: "2024-05-10T15:00:00-GST-001", Relation: domain.CausedBy, Source: "stub"}) {
+				t.Errorf("related = %v, want the storm", event.Related)
+			}
+		})
+	}
+
+	// Miami is too far from the auroral oval even at Kp 9
+	events, err := repo.GetEvents(context.Background(), may2024Range, miami)
+	if err != nil || len(events) != 0 {
+		t.Errorf("GetEvents() from Miami = %v, %v, want none", events, err)
+	}
+}
+
+func TestAuroraRepository_Possible(t *testing.T) {
+	// A moderate storm is only seen low over the horizon from London
+	repo := NewAuroraRepository(stubSpaceWeather{
+		storms: []domain.Event{storm("2024-10-10T21:00:00-GST-001", time.Date(2024, 10, 11, 0, 0, 0, 0, time.UTC), 6, 6.33)},
+	})
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 10, 10, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 10, 12, 0, 0, 0, 0, time.UTC),
+	}
+
+	events, err := repo.GetEvents(context.Background(), timeRange, london)
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("GetEvents() returned %d events, want 1", len(events))
+	}
+	event := events[0]
+	if event.Title != "Aurora Possible" || event.Visibility != "Low over the northern horizon" || event.Aurora.Overhead {
+		t.Errorf("event = %+v", event)
+	}
+	if !event.StartTime.Equal(time.Date(2024, 10, 10, 21, 0, 0, 0, time.UTC)) || !event.EndTime.Equal(time.Date(2024, 10, 11, 3, 0, 0, 0, time.UTC)) {
+		t.Errorf("spell from %v to %v, want the storm", event.StartTime, event.EndTime)
+	}
+}
+
+func TestAuroraRepository_Forecast(t *testing.T) {
+	arrival := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC),
+	}
+
+	// Before the storm is observed the predicted impact gives the spell,
+	// from the arrival in the dark until dawn
+	repo := NewAuroraRepository(stubSpaceWeather{cmes: []domain.Event{cme("2024-05-08T05:36:00-CME-001", arrival, 8)}})
+	events, err := repo.GetEvents(context.Background(), timeRange, hobart)
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if len(events) != 1 || !events[0].Aurora.Forecast || events[0].Aurora.MaxKpIndex != 8 || events[0].Title != "Aurora Possible" {
+		t.Fatalf("GetEvents() = %+v, want the forecast spell", events)
+	}
+	if !events[0].StartTime.Equal(arrival) || events[0].EndTime.Hour() != 20 {
+		t.Errorf("spell from %v to %v, want from the arrival until dawn", events[0].StartTime, events[0].EndTime)
+	}
+
+	// Observations replace the prediction where they overlap it, but the
+	// rest of the predicted impact is kept
+	repo = NewAuroraRepository(stubSpaceWeather{
+		storms: []domain.Event{storm("2024-05-10T15:00:00-GST-001", time.Date(2024, 5, 10, 18, 0, 0, 0, time.UTC), 4, 4)},
+		cmes:   []domain.Event{cme("2024-05-08T05:36:00-CME-001", arrival, 8)},
+	})
+	events, err = repo.GetEvents(context.Background(), timeRange, hobart)
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if len(events) != 1 || len(events[0].Aurora.ActivityIDs) != 1 || !events[0].EndTime.Equal(time.Date(2024, 5, 10, 15, 0, 0, 0, time.UTC)) {
+		t.Errorf("GetEvents() = %+v, want the forecast until the observed storm", events)
+	}
+}
+
+func TestAuroraRepository_GetEventByID(t *testing.T) {
+	repo := NewAuroraRepository(may2024)
+
+	event, err := repo.GetEventByID(context.Background(), "aurora-2024-05-10", newYork)
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if event == nil || event.Aurora.MaxKpIndex != 8.67 {
+		t.Fatalf("GetEventByID() = %+v, want the storm of May 10", event)
+	}
+
+	for _, id := range []string{"aurora-2024-05-11", "aurora"} {
+		event, err := repo.GetEventByID(context.Background(), id, newYork)
+		if err != nil || event != nil {
+			t.Errorf("GetEventByID(%q) = %v, %v, want nil", id, event, err)
+		}
+	}
+
+	failing := NewAuroraRepository(stubSpaceWeather{err: errors.New("rate limited")})
+	if _, err := failing.GetEvents(context.Background(), may2024Range, newYork); err == nil {
+		t.Error("GetEvents() error = nil, want the space weather failure")
+	}
+	// IDs of other sources are not looked up in the space weather source
+	event, err = failing.GetEventByID(context.Background(), "2024-05-10T15:00:00-GST-001", newYork)
+	if err != nil || event != nil {
+		t.Errorf("GetEventByID() of a storm = %v, %v, want nil", event, err)
+	}
+}
@@ -0,0 +1,266 @@
+package conjunctions
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+)
+
+const (
+	sourceName = "Local Conjunction Detector"
+
+	// scanStep is the sampling interval in days. The Moon moves about a
+	// degree every two hours, so minima closer than this are never skipped.
+	scanStep = 2.0 / 24
+
+	// maxSpanDays limits how far the search walks outwards from a minimum
+	// to find when the pair enters and leaves the separation threshold
+	maxSpanDays = 60.0
+)
+
+// body is anything with an apparent position that can take part in a conjunction
+type body struct {
+	name     string
+	star     bool
+	position astro.PositionFunc
+}
+
+// brightStars lists the first-magnitude stars close enough to the ecliptic
+// to be passed by the Moon and planets (J2000 positions)
+var brightStars = []struct {
+	name string
+	pos  astro.Equatorial
+}{
+	{"Aldebaran", astro.Equatorial{RA: 68.9802, Dec: 16.5093}},
+	{"Pollux", astro.Equatorial{RA: 116.3290, Dec: 28.0262}},
+	{"Regulus", astro.Equatorial{RA: 152.0930, Dec: 11.9672}},
+	{"Spica", astro.Equatorial{RA: 201.2983, Dec: -11.1613}},
+	{"Antares", astro.Equatorial{RA: 247.3519, Dec: -26.4320}},
+}
+
+func bodies() []body {
+	result := []body{{name: "Moon", position: astro.MoonApparent}}
+	for _, p := range astro.Planets {
+		p := p
+		result = append(result, body{
+			name:     p.String(),
+			position: func(jde float64) astro.Equatorial { return astro.PlanetApparent(p, jde) },
+		})
+	}
+	for _, s := range brightStars {
+		s := s
+		result = append(result, body{
+			name:     s.name,
+			star:     true,
+			position: func(jde float64) astro.Equatorial { return astro.ApparentPlace(s.pos, jde) },
+		})
+	}
+	return result
+}
+
+type conjunctionRepository struct {
+	maxSeparation float64
+	bodies        []body
+}
+
+// NewConjunctionRepository creates a repository that reports every pair of
+// planets, the Moon and bright ecliptic stars passing within maxSeparation
+// degrees of each other
+func NewConjunctionRepository(maxSeparation float64) *conjunctionRepository {
+	return &conjunctionRepository{
+		maxSeparation: maxSeparation,
+		bodies:        bodies(),
+	}
+}
+
+func (r *conjunctionRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	startJD := astro.JulianDay(timeRange.Start)
+	endJD := astro.JulianDay(timeRange.End)
+	site := astro.Site{Latitude: observer.Latitude, Longitude: observer.Longitude, Elevation: observer.Elevation}
+
+	// Sample every body once per step and keep the separations of each pair
+	type pair struct{ a, b int }
+	var pairs []pair
+	for i := range r.bodies {
+		for j := i + 1; j < len(r.bodies); j++ {
+			if !(r.bodies[i].star && r.bodies[j].star) {
+				pairs = append(pairs, pair{i, j})
+			}
+		}
+	}
+
+	var samples []float64
+	for jd := startJD - scanStep; jd <= endJD+scanStep; jd += scanStep {
+		samples = append(samples, jd)
+	}
+	separations := make([][]float64, len(pairs))
+	for s, jd := range samples {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		positions := make([]astro.Equatorial, len(r.bodies))
+		for i, b := range r.bodies {
+			positions[i] = b.position(astro.JDE(jd))
+		}
+		for p, pr := range pairs {
+			if s == 0 {
+				separations[p] = make([]float64, len(samples))
+			}
+			separations[p][s] = astro.AngularSeparation(positions[pr.a], positions[pr.b])
+		}
+	}
+
+	var events []domain.Event
+	for p, pr := range pairs {
+		sep := separations[p]
+		for s := 1; s < len(samples)-1; s++ {
+			if !(sep[s] <= sep[s-1] && sep[s] < sep[s+1]) || sep[s] > r.maxSeparation+1 {
+				continue
+			}
+			a, b := r.bodies[pr.a], r.bodies[pr.b]
+			minJD, minSep := r.minimum(a, b, samples[s-1], samples[s+1])
+			if minSep > r.maxSeparation || minJD < startJD || minJD >= endJD {
+				continue
+			}
+			events = append(events, r.event(a, b, minJD, minSep, site))
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	return events, nil
+}
+
+// separation returns the apparent angular distance between two bodies
+func separation(a, b body, jd float64) float64 {
+	jde := astro.JDE(jd)
+	return astro.AngularSeparation(a.position(jde), b.position(jde))
+}
+
+// minimum refines the instant of least separation between two samples that
+// bracket it
+func (r *conjunctionRepository) minimum(a, b body, fromJD, toJD float64) (float64, float64) {
+	slope := func(jd float64) float64 {
+		return separation(a, b, jd+1e-4) - separation(a, b, jd-1e-4)
+	}
+	for toJD-fromJD > 1e-5 {
+		mid := (fromJD + toJD) / 2
+		if slope(mid) < 0 {
+			fromJD = mid
+		} else {
+			toJD = mid
+		}
+	}
+	jd := (fromJD + toJD) / 2
+	return jd, separation(a, b, jd)
+}
+
+// crossing walks from the minimum in the given direction until the pair is
+// farther apart than the threshold, and returns the crossing instant
+func (r *conjunctionRepository) crossing(a, b body, minJD, direction float64) float64 {
+	inside := minJD
+	for step := scanStep; step <= maxSpanDays; step *= 2 {
+		outside := minJD + direction*step
+		if separation(a, b, outside) > r.maxSeparation {
+			for math.Abs(outside-inside) > 1e-5 {
+				mid := (inside + outside) / 2
+				if separation(a, b, mid) > r.maxSeparation {
+					outside = mid
+				} else {
+					inside = mid
+				}
+			}
+			return (inside + outside) / 2
+		}
+		inside = outside
+	}
+	return inside
+}
+
+func (r *conjunctionRepository) event(a, b body, minJD, minSep float64, site astro.Site) domain.Event {
+	minTime := astro.TimeFromJulianDay(minJD).Round(time.Second)
+	jde := astro.JDE(minJD)
+	elongation := astro.AngularSeparation(a.position(jde), astro.SunApparent(jde))
+
+	visibility := fmt.Sprintf("Elongation from the Sun: %.1f°", elongation)
+	if elongation < 15 {
+		visibility += " (too close to the Sun to observe)"
+	}
+	if altitude := astro.Altitude(a.position, minJD, site); altitude > 0 {
+		visibility += fmt.Sprintf(", %.1f° above the horizon at closest approach", altitude)
+	} else {
+		visibility += ", below the horizon at closest approach"
+	}
+
+	return domain.Event{
+		ID: fmt.Sprintf("conjunction-%s-%s-%s",
+			strings.ToLower(a.name), strings.ToLower(b.name), minTime.Format("2006-01-02")),
+		Title: fmt.Sprintf("Conjunction of %s and %s", a.name, b.name),
+		Description: fmt.Sprintf("%s and %s pass %.2f° apart at %s UTC",
+			a.name, b.name, minSep, minTime.Format("2006-01-02 15:04")),
+		StartTime:  astro.TimeFromJulianDay(r.crossing(a, b, minJD, -1)).Round(time.Second),
+		EndTime:    astro.TimeFromJulianDay(r.crossing(a, b, minJD, 1)).Round(time.Second),
+		Type:       domain.Conjunction,
+		Visibility: visibility,
+		Location:   domain.ConstellationAt(a.position(jde), jde).Name,
+		Source:     sourceName,
+		Conjunction: &domain.ConjunctionDetails{
+			Bodies:        []string{a.name, b.name},
+			SeparationDeg: minSep,
+			MinimumTime:   minTime,
+			ElongationDeg: elongation,
+		},
+	}
+}
+
+func (r *conjunctionRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs end with the date of minimum separation
+	if !strings.HasPrefix(id, "conjunction-") || len(id) < len("2006-01-02") {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", id[len(id)-len("2006-01-02"):])
+	if err != nil {
+		return nil, nil
+	}
+
+	timeRange := domain.TimeRange{Start: date, End: date.AddDate(0, 0, 1)}
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.ID == id {
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *conjunctionRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []domain.Event
+	for _, event := range events {
+		if event.Type == eventType {
+			filtered = append(filtered, event)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *conjunctionRepository) Name() string {
+	return sourceName
+}
@@ -0,0 +1,113 @@
+package conjunctions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+func TestConjunctionRepository_GetEvents(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxSeparation float64
+		timeRange     domain.TimeRange
+		wantBodies    [2]string
+		wantTime      time.Time
+		timeTolerance time.Duration
+		wantSep       float64
+		sepTolerance  float64
+	}{
+		{
+			name:          "great conjunction of Jupiter and Saturn",
+			maxSeparation: 1,
+			timeRange: domain.TimeRange{
+				Start: time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantBodies:    [2]string{"Jupiter", "Saturn"},
+			wantTime:      time.Date(2020, 12, 21, 18, 20, 0, 0, time.UTC),
+			timeTolerance: 24 * time.Hour,
+			wantSep:       0.102,
+			sepTolerance:  0.02,
+		},
+		{
+			name:          "Venus and Jupiter in the evening sky",
+			maxSeparation: 2,
+			timeRange: domain.TimeRange{
+				Start: time.Date(2023, 2, 25, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2023, 3, 10, 0, 0, 0, 0, time.UTC),
+			},
+			wantBodies:    [2]string{"Venus", "Jupiter"},
+			wantTime:      time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC),
+			timeTolerance: 12 * time.Hour,
+			wantSep:       0.5,
+			sepTolerance:  0.05,
+		},
+		{
+			name:          "Venus passing Neptune",
+			maxSeparation: 0.2,
+			timeRange: domain.TimeRange{
+				Start: time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantBodies:    [2]string{"Venus", "Neptune"},
+			wantTime:      time.Date(2023, 2, 15, 12, 0, 0, 0, time.UTC),
+			timeTolerance: 6 * time.Hour,
+			wantSep:       0.01,
+			sepTolerance:  0.02,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewConjunctionRepository(tt.maxSeparation)
+			events, err := repo.GetEventsByType(context.Background(), domain.Conjunction, tt.timeRange, domain.Observer{})
+			if err != nil {
+				t.Fatalf("GetEventsByType() error = %v", err)
+			}
+
+			var found *domain.ConjunctionDetails
+			for _, event := range events {
+				c := event.Conjunction
+				if c.SeparationDeg > tt.maxSeparation {
+					t.Errorf("%v separation %v exceeds %v", event.ID, c.SeparationDeg, tt.maxSeparation)
+				}
+				if c.MinimumTime.Before(event.StartTime) || c.MinimumTime.After(event.EndTime) {
+					t.Errorf("%v minimum %v outside %v - %v", event.ID, c.MinimumTime, event.StartTime, event.EndTime)
+				}
+				if c.Bodies[0] == tt.wantBodies[0] && c.Bodies[1] == tt.wantBodies[1] {
+					found = c
+				}
+			}
+			if found == nil {
+				t.Fatalf("GetEventsByType() missing conjunction of %v", tt.wantBodies)
+			}
+
+			if diff := found.MinimumTime.Sub(tt.wantTime).Abs(); diff > tt.timeTolerance {
+				t.Errorf("minimum at %v, want %v", found.MinimumTime, tt.wantTime)
+			}
+			if diff := found.SeparationDeg - tt.wantSep; diff > tt.sepTolerance || diff < -tt.sepTolerance {
+				t.Errorf("separation = %v, want %v", found.SeparationDeg, tt.wantSep)
+			}
+		})
+	}
+}
+
+func TestConjunctionRepository_GetEventByID(t *testing.T) {
+	repo := NewConjunctionRepository(1)
+
+	event, err := repo.GetEventByID(context.Background(), "conjunction-venus-jupiter-2023-03-02", domain.Observer{})
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if event == nil || event.Conjunction.Bodies[0] != "Venus" {
+		t.Errorf("GetEventByID() = %v, want the Venus-Jupiter conjunction", event)
+	}
+
+	event, err = repo.GetEventByID(context.Background(), "conjunction-mars-jupiter-2023-03-02", domain.Observer{})
+	if err != nil || event != nil {
+		t.Errorf("GetEventByID() = %v, %v, want nil", event, err)
+	}
+}
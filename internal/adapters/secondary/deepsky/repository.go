@@ -0,0 +1,349 @@
+package deepsky
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+)
+
+const sourceName = "Local Deep-Sky Catalog"
+
+//go:embed objects.csv
+var objectsCSV []byte
+
+// siderealRate is the rate of the sidereal time in degrees a day
+const siderealRate = 360.98564736629
+
+// maxNights bounds the search for the ends of a season, which never lasts
+// longer than a year
+const maxNights = 366
+
+// object is one row of the embedded catalog
+type object struct {
+	designation   string
+	name          string
+	kind          domain.DeepSkyKind
+	position      astro.Equatorial
+	magnitude     float64
+	major, minor  float64
+	constellation string
+}
+
+// night is the astronomical darkness of one night, as Julian days
+type night struct {
+	from, to float64
+	dark     bool
+}
+
+type deepSkyRepository struct {
+	objects     []object
+	minAltitude float64
+}
+
+// NewDeepSkyRepository creates a repository that reports the seasons during
+// which the objects of the embedded Messier, NGC and IC catalog culminate in
+// astronomical darkness at least minAltitude degrees above the horizon
+func NewDeepSkyRepository(minAltitude float64) (*deepSkyRepository, error) {
+	objects, err := parseObjects(objectsCSV)
+	if err != nil {
+		return nil, fmt.Errorf("loading deep-sky catalog: %w", err)
+	}
+	return &deepSkyRepository{objects: objects, minAltitude: minAltitude}, nil
+}
+
+func parseObjects(data []byte) ([]object, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 8
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]object, 0, len(records))
+	for _, record := range records {
+		var values [5]float64
+		for i := range values {
+			if values[i], err = strconv.ParseFloat(record[i+3], 64); err != nil {
+				return nil, fmt.Errorf("%s column %d: %w", record[0], i+3, err)
+			}
+		}
+		position := astro.Equatorial{RA: values[0], Dec: values[1]}
+		objects = append(objects, object{
+			designation:   record[0],
+			name:          record[1],
+			kind:          domain.DeepSkyKind(record[2]),
+			position:      position,
+			magnitude:     values[2],
+			major:         values[3],
+			minor:         values[4],
+			constellation: domain.ConstellationAt(position, astro.J2000).Name,
+		})
+	}
+	return objects, nil
+}
+
+// catalog returns the catalog an object belongs to from its designation
+func (o object) catalog() string {
+	switch {
+	case strings.HasPrefix(o.designation, "NGC"):
+		return "NGC"
+	case strings.HasPrefix(o.designation, "IC"):
+		return "IC"
+	default:
+		return "Messier"
+	}
+}
+
+// nights computes and remembers the darkness of nights counted from a base
+// date, so that the objects of a request share them
+type nights struct {
+	base  time.Time
+	site  astro.Site
+	cache map[int]night
+}
+
+func (n *nights) get(i int) night {
+	if dark, ok := n.cache[i]; ok {
+		return dark
+	}
+	dark := darkness(n.base.AddDate(0, 0, i), n.site)
+	n.cache[i] = dark
+	return dark
+}
+
+// date returns the date of the evening of night i
+func (n *nights) date(i int) time.Time {
+	return n.base.AddDate(0, 0, i)
+}
+
+// darkness finds the astronomical darkness of the night that follows local
+// mean noon on date
+func darkness(date time.Time, site astro.Site) night {
+	startJD := astro.JulianDay(date) + 0.5 - site.Longitude/360
+	endJD := startJD + 1
+
+	from, down := startJD, astro.Altitude(astro.SunApparent, startJD, site) < astro.AstronomicalTwilightAltitude
+	for _, he := range astro.HorizonEvents(astro.SunApparent, astro.AstronomicalTwilightAltitude, site, startJD, endJD) {
+		switch {
+		case he.Kind == astro.Setting && !down:
+			from, down = he.JD, true
+		case he.Kind == astro.Rising && down:
+			return night{from: from, to: he.JD, dark: true}
+		}
+	}
+	if down {
+		return night{from: from, to: endJD, dark: true}
+	}
+	return night{}
+}
+
+// culmination returns the upper transit of an object during the darkness of
+// a night and its altitude, if it culminates in darkness at least
+// minAltitude degrees above the horizon
+func culmination(o object, dark night, site astro.Site, minAltitude float64) (float64, float64, bool) {
+	if !dark.dark {
+		return 0, 0, false
+	}
+	pos := astro.ApparentPlace(o.position, astro.JDE(dark.from))
+	altitude := 90 - math.Abs(site.Latitude-pos.Dec)
+	hourAngle := astro.NormalizeDegrees(astro.LocalSiderealTime(dark.from, site.Longitude) - pos.RA)
+	jd := dark.from + astro.NormalizeDegrees(-hourAngle)/siderealRate
+	return jd, altitude, jd <= dark.to && altitude >= minAltitude
+}
+
+// season is a run of consecutive nights during which an object culminates in
+// darkness
+type season struct {
+	first, last int
+}
+
+func (r *deepSkyRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	site := astro.Site{Latitude: observer.Latitude, Longitude: observer.Longitude, Elevation: observer.Elevation}
+	first := observer.NightDate(timeRange.Start)
+	count := int(observer.NightDate(timeRange.End).Sub(first).Hours()/24) + 1
+	n := &nights{base: first, site: site, cache: make(map[int]night)}
+
+	var events []domain.Event
+	for _, o := range r.objects {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// Skip objects that never climb high enough without searching for
+		// their seasons
+		if 90-math.Abs(observer.Latitude-o.position.Dec) < r.minAltitude-1 {
+			continue
+		}
+
+		for _, s := range r.seasons(o, n, count) {
+			event := r.deepSkyEvent(o, s, n, observer)
+			if event.EndTime.Before(timeRange.Start) || !event.StartTime.Before(timeRange.End) {
+				continue
+			}
+			events = append(events, event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].StartTime.Equal(events[j].StartTime) {
+			return events[i].StartTime.Before(events[j].StartTime)
+		}
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
+
+// seasons finds the seasons of an object that include any of the first
+// count nights, following them beyond those nights to their ends
+func (r *deepSkyRepository) seasons(o object, n *nights, count int) []season {
+	qualifies := func(i int) bool {
+		_, _, ok := culmination(o, n.get(i), n.site, r.minAltitude)
+		return ok
+	}
+
+	var found []season
+	for i := 0; i < count; i++ {
+		if !qualifies(i) {
+			continue
+		}
+		s := season{first: i, last: i}
+		for s.first > -maxNights && qualifies(s.first-1) {
+			s.first--
+		}
+		for s.last-s.first < maxNights-1 && qualifies(s.last+1) {
+			s.last++
+		}
+		found = append(found, s)
+		i = s.last
+	}
+	return found
+}
+
+func (r *deepSkyRepository) deepSkyEvent(o object, s season, n *nights, observer domain.Observer) domain.Event {
+	start, _, _ := culmination(o, n.get(s.first), n.site, r.minAltitude)
+	end, _, _ := culmination(o, n.get(s.last), n.site, r.minAltitude)
+
+	// The best night is the one on which the object culminates closest to
+	// the middle of the darkness
+	best, altitude, bestOffset := start, 0.0, math.Inf(1)
+	for i := s.first; i <= s.last; i++ {
+		dark := n.get(i)
+		jd, h, _ := culmination(o, dark, n.site, r.minAltitude)
+		if offset := math.Abs(jd - (dark.from+dark.to)/2); offset < bestOffset {
+			best, altitude, bestOffset = jd, h, offset
+		}
+	}
+
+	details := &domain.DeepSkyDetails{
+		Designation:            o.designation,
+		Name:                   o.name,
+		Catalog:                o.catalog(),
+		Kind:                   o.kind,
+		Magnitude:              o.magnitude,
+		MajorAxisArcmin:        o.major,
+		MinorAxisArcmin:        o.minor,
+		Constellation:          o.constellation,
+		RightAscension:         o.position.RA,
+		Declination:            o.position.Dec,
+		CulminationAltitudeDeg: altitude,
+		Nights:                 s.last - s.first + 1,
+		BestCulmination:        astro.EventTime(best),
+	}
+
+	title := o.designation
+	if o.name != "" {
+		title = fmt.Sprintf("%s (%s)", o.designation, o.name)
+	}
+	direction := "south"
+	if o.position.Dec > observer.Latitude {
+		direction = "north"
+	}
+
+	return domain.Event{
+		ID:    fmt.Sprintf("deep-sky-%s-%s", slug(o.designation), n.date(s.first).Format("2006-01-02")),
+		Title: fmt.Sprintf("%s Best Viewing", title),
+		Description: fmt.Sprintf("%s, a magnitude %.1f %s in %s, culminates during astronomical darkness on %d nights from %s to %s. It is best placed on %s, culminating at %s",
+			title, o.magnitude, kindName(o.kind), o.constellation, details.Nights,
+			day(astro.EventTime(start), observer), day(astro.EventTime(end), observer),
+			day(details.BestCulmination, observer), observer.Clock(details.BestCulmination)),
+		StartTime:  astro.EventTime(start),
+		EndTime:    astro.EventTime(end),
+		Type:       domain.DeepSky,
+		Visibility: fmt.Sprintf("Culminates %.0f° above the %sern horizon", altitude, direction),
+		Location:   o.constellation,
+		Source:     sourceName,
+		DeepSky:    details,
+	}
+}
+
+// kindName turns a kind such as GLOBULAR_CLUSTER into "globular cluster"
+func kindName(kind domain.DeepSkyKind) string {
+	return strings.ToLower(strings.ReplaceAll(string(kind), "_", " "))
+}
+
+// day formats the date of an instant in the observer's time zone
+func day(t time.Time, observer domain.Observer) string {
+	return t.In(observer.Location()).Format("Jan 2")
+}
+
+// slug turns a designation such as "NGC 869" into "ngc-869"
+func slug(designation string) string {
+	return strings.ToLower(strings.Join(strings.Fields(designation), "-"))
+}
+
+func (r *deepSkyRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs end with the date of the first night of the season
+	if !strings.HasPrefix(id, "deep-sky-") || len(id) < len("2006-01-02") {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", id[len(id)-len("2006-01-02"):])
+	if err != nil {
+		return nil, nil
+	}
+
+	timeRange := domain.TimeRange{Start: date, End: date.AddDate(0, 0, 2)}
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.ID == id {
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *deepSkyRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []domain.Event
+	for _, event := range events {
+		if event.Type == eventType {
+			filtered = append(filtered, event)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *deepSkyRepository) Name() string {
+	return sourceName
+}
@@ -0,0 +1,155 @@
+package deepsky
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+var (
+	newYork  = domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
+	santiago = domain.Observer{Latitude: -33.4489, Longitude: -70.6693, TimeZone: "America/Santiago"}
+)
+
+var october2026 = domain.TimeRange{
+	Start: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+	End:   time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func find(events []domain.Event, designation string) *domain.Event {
+	for i := range events {
+		if events[i].DeepSky.Designation == designation {
+			return &events[i]
+		}
+	}
+	return nil
+}
+
+func TestDeepSkyRepository_GetEvents(t *testing.T) {
+	tests := []struct {
+		name        string
+		observer    domain.Observer
+		minAltitude float64
+		want        []string
+		wantMissing []string
+	}{
+		{
+			name:        "autumn sky from New York",
+			observer:    newYork,
+			minAltitude: 30,
+			want:        []string{"M31", "M45", "NGC 869"},
+			// Omega Centauri never rises high enough, and Orion only
+			// culminates before dawn late in the month
+			wantMissing: []string{"NGC 5139", "M13"},
+		},
+		{
+			name:        "southern objects from Santiago",
+			observer:    santiago,
+			minAltitude: 30,
+			want:        []string{"NGC 104", "NGC 292", "NGC 253"},
+			wantMissing: []string{"M31", "M52"},
+		},
+		{
+			name:        "high altitude limit",
+			observer:    newYork,
+			minAltitude: 80,
+			want:        []string{"M31", "M34"},
+			wantMissing: []string{"M45", "M2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewDeepSkyRepository(tt.minAltitude)
+			if err != nil {
+				t.Fatalf("NewDeepSkyRepository() error = %v", err)
+			}
+			events, err := repo.GetEvents(context.Background(), october2026, tt.observer)
+			if err != nil {
+				t.Fatalf("GetEvents() error = %v", err)
+			}
+			for _, designation := range tt.want {
+				if find(events, designation) == nil {
+					t.Errorf("GetEvents() is missing %s", designation)
+				}
+			}
+			for _, designation := range tt.wantMissing {
+				if find(events, designation) != nil {
+					t.Errorf("GetEvents() returned %s", designation)
+				}
+			}
+			for _, event := range events {
+				if event.Type != domain.DeepSky || event.DeepSky.CulminationAltitudeDeg < tt.minAltitude ||
+					event.EndTime.Before(october2026.Start) || !event.StartTime.Before(october2026.End) {
+					t.Errorf("event = %+v", event)
+				}
+			}
+		})
+	}
+}
+
+func TestDeepSkyRepository_Season(t *testing.T) {
+	repo, err := NewDeepSkyRepository(30)
+	if err != nil {
+		t.Fatalf("NewDeepSkyRepository() error = %v", err)
+	}
+	events, err := repo.GetEvents(context.Background(), october2026, newYork)
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	event := find(events, "M31")
+	if event == nil {
+		t.Fatal("GetEvents() is missing M31")
+	}
+
+	// M31 is on the meridian at local midnight in early October, and its
+	// season runs from mid-August until the end of December
+	details := event.DeepSky
+	wantBest := time.Date(2026, 10, 5, 4, 44, 0, 0, time.UTC)
+	if d := details.BestCulmination.Sub(wantBest).Abs(); d > 24*time.Hour {
+		t.Errorf("best culmination = %v, want %v", details.BestCulmination, wantBest)
+	}
+	if details.Nights < 120 || details.Nights > 160 {
+		t.Errorf("nights = %d, want about 140", details.Nights)
+	}
+	if details.Catalog != "Messier" || details.Kind != domain.Galaxy || details.Constellation != "Andromeda" ||
+		details.Magnitude != 3.4 || details.MajorAxisArcmin != 178 {
+		t.Errorf("details = %+v", details)
+	}
+
+	// Later ranges within the same season return the same event
+	december := domain.TimeRange{Start: time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2026, 12, 2, 0, 0, 0, 0, time.UTC)}
+	later, err := repo.GetEvents(context.Background(), december, newYork)
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if same := find(later, "M31"); same == nil || same.ID != event.ID || !same.StartTime.Equal(event.StartTime) {
+		t.Errorf("December season = %+v, want %v", same, event.ID)
+	}
+
+	found, err := repo.GetEventByID(context.Background(), event.ID, newYork)
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if found == nil || found.ID != event.ID || found.DeepSky.Designation != "M31" {
+		t.Errorf("GetEventByID() = %+v, want %v", found, event.ID)
+	}
+}
+
+func TestParseObjects(t *testing.T) {
+	objects, err := parseObjects(objectsCSV)
+	if err != nil {
+		t.Fatalf("parseObjects() error = %v", err)
+	}
+	messier := 0
+	for _, o := range objects {
+		if o.catalog() == "Messier" {
+			messier++
+		}
+	}
+	if messier != 110 || len(objects) <= messier || objects[0].designation != "M1" {
+		t.Errorf("parseObjects() = %d objects, %d Messier, starting with %v", len(objects), messier, objects[0].designation)
+	}
+}
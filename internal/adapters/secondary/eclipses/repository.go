@@ -0,0 +1,225 @@
+package eclipses
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+)
+
+const (
+	sourceName = "Local Eclipse Predictor"
+
+	// visibilityStep is the sampling interval used to decide whether the
+	// eclipsed body is above the horizon at some point of the eclipse
+	visibilityStep = 5.0 / (24 * 60)
+)
+
+var kinds = map[astro.EclipseKind]domain.EclipseKind{
+	astro.TotalEclipse:     domain.TotalEclipse,
+	astro.AnnularEclipse:   domain.AnnularEclipse,
+	astro.HybridEclipse:    domain.HybridEclipse,
+	astro.PartialEclipse:   domain.PartialEclipse,
+	astro.PenumbralEclipse: domain.PenumbralEclipse,
+}
+
+type eclipseRepository struct{}
+
+// NewEclipseRepository creates a repository that predicts solar and lunar
+// eclipses and their circumstances for the requesting observer
+func NewEclipseRepository() *eclipseRepository {
+	return &eclipseRepository{}
+}
+
+func (r *eclipseRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	startJD := astro.JulianDay(timeRange.Start)
+	endJD := astro.JulianDay(timeRange.End)
+
+	site := astro.Site{Latitude: observer.Latitude, Longitude: observer.Longitude, Elevation: observer.Elevation}
+
+	var events []domain.Event
+	for _, eclipse := range astro.SolarEclipses(startJD, endJD) {
+		events = append(events, solarEvent(eclipse, site))
+	}
+	for _, eclipse := range astro.LunarEclipses(startJD, endJD) {
+		events = append(events, lunarEvent(eclipse, site))
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	return events, nil
+}
+
+func solarEvent(eclipse astro.SolarEclipse, site astro.Site) domain.Event {
+	greatest := astro.EventTime(eclipse.JD)
+	kind := kinds[eclipse.Kind]
+	title := fmt.Sprintf("%s Solar Eclipse", domain.TitleCase(string(kind)))
+
+	details := &domain.EclipseDetails{
+		Body:     domain.SolarEclipse,
+		Kind:     kind,
+		Greatest: greatest,
+		Gamma:    eclipse.Gamma,
+	}
+	event := domain.Event{
+		ID:          fmt.Sprintf("eclipse-solar-%s", greatest.Format("2006-01-02")),
+		Title:       title,
+		Description: fmt.Sprintf("%s with greatest eclipse at %s UTC", title, greatest.Format("15:04")),
+		StartTime:   greatest,
+		EndTime:     greatest,
+		Type:        domain.Eclipse,
+		Visibility:  "Not visible from this location",
+		Location:    constellation(astro.SunApparent, eclipse.JD),
+		Source:      sourceName,
+		Eclipse:     details,
+	}
+
+	local, ok := eclipse.LocalCircumstances(site)
+	if !ok {
+		return event
+	}
+
+	details.LocalKind = kinds[local.Kind]
+	details.Magnitude = local.Magnitude
+	details.Obscuration = local.Obscuration
+	details.Contacts = contacts([]string{"C1", "C2", "MAX", "C3", "C4"},
+		[]float64{local.C1, local.C2, local.Max, local.C3, local.C4})
+	details.Visible = aboveHorizon(site, astro.SunApparent, astro.SunStandardAltitude, local.C1, local.C4)
+
+	event.StartTime, event.EndTime = astro.EventTime(local.C1), astro.EventTime(local.C4)
+	event.Description += fmt.Sprintf(". From this location the eclipse is %s, with magnitude %.3f and %.0f%% of the Sun covered at %s UTC",
+		strings.ToLower(string(details.LocalKind)), local.Magnitude, local.Obscuration*100, astro.EventTime(local.Max).Format("15:04"))
+	if details.Visible {
+		event.Visibility = fmt.Sprintf("Visible: Sun altitude %.1f° at maximum", local.SunAltitude)
+	} else {
+		event.Visibility = "Not visible: the Sun is below the horizon"
+	}
+	return event
+}
+
+func lunarEvent(eclipse astro.LunarEclipse, site astro.Site) domain.Event {
+	greatest := astro.EventTime(eclipse.JD)
+	kind := kinds[eclipse.Kind]
+	title := fmt.Sprintf("%s Lunar Eclipse", domain.TitleCase(string(kind)))
+
+	magnitude := eclipse.UmbralMagnitude
+	if eclipse.Kind == astro.PenumbralEclipse {
+		magnitude = eclipse.PenumbralMagnitude
+	}
+
+	visible := aboveHorizon(site, astro.MoonApparent, 0, eclipse.P1, eclipse.P4)
+	visibility := "Not visible: the Moon is below the horizon"
+	if visible {
+		visibility = fmt.Sprintf("Visible: Moon altitude %.1f° at greatest eclipse",
+			astro.Altitude(astro.MoonApparent, eclipse.JD, site))
+	}
+
+	return domain.Event{
+		ID:    fmt.Sprintf("eclipse-lunar-%s", greatest.Format("2006-01-02")),
+		Title: title,
+		Description: fmt.Sprintf("%s with greatest eclipse at %s UTC, umbral magnitude %.3f and penumbral magnitude %.3f",
+			title, greatest.Format("15:04"), eclipse.UmbralMagnitude, eclipse.PenumbralMagnitude),
+		StartTime:  astro.EventTime(eclipse.P1),
+		EndTime:    astro.EventTime(eclipse.P4),
+		Type:       domain.Eclipse,
+		Visibility: visibility,
+		Location:   constellation(astro.MoonApparent, eclipse.JD),
+		Source:     sourceName,
+		Eclipse: &domain.EclipseDetails{
+			Body:      domain.LunarEclipse,
+			Kind:      kind,
+			Greatest:  greatest,
+			Magnitude: magnitude,
+			Gamma:     eclipse.Gamma,
+			Contacts: contacts([]string{"P1", "U1", "U2", "MAX", "U3", "U4", "P4"},
+				[]float64{eclipse.P1, eclipse.U1, eclipse.U2, eclipse.JD, eclipse.U3, eclipse.U4, eclipse.P4}),
+			Visible: visible,
+		},
+	}
+}
+
+// aboveHorizon reports whether a body rises above altitude h0 at any time
+// between two Julian days
+func aboveHorizon(site astro.Site, pos astro.PositionFunc, h0, fromJD, toJD float64) bool {
+	for jd := fromJD; jd <= toJD+visibilityStep/2; jd += visibilityStep {
+		if astro.Altitude(pos, min(jd, toJD), site) > h0 {
+			return true
+		}
+	}
+	return false
+}
+
+// contacts pairs contact names with their instants, skipping contacts that
+// do not occur
+func contacts(names []string, jds []float64) []domain.EclipseContact {
+	var result []domain.EclipseContact
+	for i, jd := range jds {
+		if jd == 0 {
+			continue
+		}
+		result = append(result, domain.EclipseContact{Name: names[i], Time: astro.EventTime(jd)})
+	}
+	return result
+}
+
+// constellation names the constellation a body is in at a Julian day
+func constellation(pos astro.PositionFunc, jd float64) string {
+	jde := astro.JDE(jd)
+	return domain.ConstellationAt(pos(jde), jde).Name
+}
+
+func (r *eclipseRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs end with the date of greatest eclipse, so only that day needs
+	// to be searched
+	parts := strings.SplitN(id, "-", 3)
+	if len(parts) != 3 || parts[0] != "eclipse" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", parts[2])
+	if err != nil {
+		return nil, nil
+	}
+
+	timeRange := domain.TimeRange{Start: date, End: date.AddDate(0, 0, 1)}
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.ID == id {
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *eclipseRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []domain.Event
+	for _, event := range events {
+		if event.Type == eventType {
+			filtered = append(filtered, event)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *eclipseRepository) Name() string {
+	return sourceName
+}
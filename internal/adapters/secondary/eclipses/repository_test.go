@@ -0,0 +1,119 @@
+package eclipses
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+func TestEclipseRepository_SolarEclipse(t *testing.T) {
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name          string
+		observer      domain.Observer
+		wantLocalKind domain.EclipseKind
+		wantVisible   bool
+		// Published magnitude at maximum for the site
+		wantMagnitude float64
+	}{
+		{name: "Dallas in the path of totality", observer: domain.Observer{Latitude: 32.7767, Longitude: -96.7970},
+			wantLocalKind: domain.TotalEclipse, wantVisible: true, wantMagnitude: 1.015},
+		{name: "New York outside the path", observer: domain.Observer{Latitude: 40.7128, Longitude: -74.0060},
+			wantLocalKind: domain.PartialEclipse, wantVisible: true, wantMagnitude: 0.910},
+		{name: "Tokyo on the night side", observer: domain.Observer{Latitude: 35.6762, Longitude: 139.6503}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewEclipseRepository()
+			events, err := repo.GetEventsByType(context.Background(), domain.Eclipse, timeRange, tt.observer)
+			if err != nil {
+				t.Fatalf("GetEventsByType() error = %v", err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("GetEventsByType() got %v events, want 1", len(events))
+			}
+
+			event := events[0]
+			if event.ID != "eclipse-solar-2024-04-08" || event.Eclipse.Kind != domain.TotalEclipse {
+				t.Errorf("GetEventsByType() = %v %v, want the total eclipse of 2024-04-08", event.ID, event.Eclipse.Kind)
+			}
+			if event.Eclipse.Visible != tt.wantVisible {
+				t.Errorf("visible = %v, want %v", event.Eclipse.Visible, tt.wantVisible)
+			}
+			if !tt.wantVisible {
+				return
+			}
+			if event.Eclipse.LocalKind != tt.wantLocalKind {
+				t.Errorf("local kind = %v, want %v", event.Eclipse.LocalKind, tt.wantLocalKind)
+			}
+			if diff := event.Eclipse.Magnitude - tt.wantMagnitude; diff > 0.005 || diff < -0.005 {
+				t.Errorf("magnitude = %v, want %v", event.Eclipse.Magnitude, tt.wantMagnitude)
+			}
+		})
+	}
+}
+
+func TestEclipseRepository_LunarEclipse(t *testing.T) {
+	timeRange := domain.TimeRange{
+		Start: time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2022, 11, 30, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name        string
+		observer    domain.Observer
+		wantVisible bool
+	}{
+		{name: "Honolulu at local midnight", observer: domain.Observer{Latitude: 21.3069, Longitude: -157.8583}, wantVisible: true},
+		{name: "Johannesburg at local midday", observer: domain.Observer{Latitude: -26.2041, Longitude: 28.0473}, wantVisible: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewEclipseRepository()
+			events, err := repo.GetEvents(context.Background(), timeRange, tt.observer)
+			if err != nil {
+				t.Fatalf("GetEvents() error = %v", err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("GetEvents() got %v events, want 1", len(events))
+			}
+
+			event := events[0]
+			if event.Eclipse.Body != domain.LunarEclipse || event.Eclipse.Kind != domain.TotalEclipse {
+				t.Errorf("GetEvents() = %v %v, want a total lunar eclipse", event.Eclipse.Body, event.Eclipse.Kind)
+			}
+			if len(event.Eclipse.Contacts) != 7 {
+				t.Errorf("GetEvents() got %v contacts, want 7", len(event.Eclipse.Contacts))
+			}
+			if event.Eclipse.Visible != tt.wantVisible {
+				t.Errorf("visible = %v, want %v", event.Eclipse.Visible, tt.wantVisible)
+			}
+		})
+	}
+}
+
+func TestEclipseRepository_GetEventByID(t *testing.T) {
+	repo := NewEclipseRepository()
+	dallas := domain.Observer{Latitude: 32.7767, Longitude: -96.7970}
+
+	event, err := repo.GetEventByID(context.Background(), "eclipse-solar-2024-04-08", dallas)
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if event == nil || event.Eclipse.LocalKind != domain.TotalEclipse {
+		t.Errorf("GetEventByID() = %v, want the total eclipse", event)
+	}
+
+	event, err = repo.GetEventByID(context.Background(), "eclipse-solar-2024-04-09", dallas)
+	if err != nil || event != nil {
+		t.Errorf("GetEventByID() = %v, %v, want nil", event, err)
+	}
+}
@@ -0,0 +1,166 @@
+package ephemeris
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+)
+
+const sourceName = "Local Ephemeris"
+
+type ephemerisRepository struct{}
+
+// body describes how to locate a Sun or Moon for horizon searches
+type body struct {
+	name     string
+	position astro.PositionFunc
+	// standardAltitude returns h0 for the day starting at jde
+	standardAltitude func(jde float64) float64
+}
+
+var bodies = []body{
+	{
+		name:     "Sun",
+		position: astro.SunApparent,
+		standardAltitude: func(float64) float64 {
+			return astro.SunStandardAltitude
+		},
+	},
+	{
+		name:     "Moon",
+		position: astro.MoonApparent,
+		standardAltitude: func(jde float64) float64 {
+			_, dist := astro.MoonGeocentric(jde + 0.5)
+			return astro.MoonStandardAltitude(dist)
+		},
+	},
+}
+
+// NewEphemerisRepository creates a repository that computes Sun and Moon
+// rise, set and transit times locally for the requesting observer
+func NewEphemerisRepository() *ephemerisRepository {
+	return &ephemerisRepository{}
+}
+
+func (r *ephemerisRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	// Days run from local midnight to local midnight for the observer
+	loc := observer.Location()
+	local := timeRange.Start.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	site := astro.Site{Latitude: observer.Latitude, Longitude: observer.Longitude, Elevation: observer.Elevation}
+
+	var events []domain.Event
+	for day := start; day.Before(timeRange.End); day = day.AddDate(0, 0, 1) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		startJD, endJD := astro.JulianDay(day), astro.JulianDay(day.AddDate(0, 0, 1))
+		for _, b := range bodies {
+			h0 := b.standardAltitude(astro.JDE(startJD))
+			for _, he := range astro.HorizonEvents(b.position, h0, site, startJD, endJD) {
+				event := horizonEvent(b, he, observer)
+				if event.StartTime.Before(timeRange.Start) || !event.StartTime.Before(timeRange.End) {
+					continue
+				}
+				events = append(events, event)
+			}
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	return events, nil
+}
+
+// horizonEvent maps a rise, set or transit onto a domain event, dated and
+// described in the observer's time zone
+func horizonEvent(b body, he astro.HorizonEvent, observer domain.Observer) domain.Event {
+	name := b.name
+	jde := astro.JDE(he.JD)
+	t := astro.TimeFromJulianDay(he.JD).Round(time.Second)
+	local := t.In(observer.Location())
+
+	var kind, title string
+	var eventType domain.EventType
+	var visibility string
+	switch he.Kind {
+	case astro.Rising:
+		kind, title, eventType = "rise", fmt.Sprintf("%srise", name), domain.Rise
+		visibility = fmt.Sprintf("Azimuth: %.2f°", he.Position.Azimuth)
+	case astro.Setting:
+		kind, title, eventType = "set", fmt.Sprintf("%sset", name), domain.Set
+		visibility = fmt.Sprintf("Azimuth: %.2f°", he.Position.Azimuth)
+	default:
+		kind, title, eventType = "transit", fmt.Sprintf("%s Meridian Transit", name), domain.Transit
+		visibility = fmt.Sprintf("Altitude: %.2f°", he.Position.Altitude)
+	}
+
+	return domain.Event{
+		ID:    fmt.Sprintf("%s-%s-%s", strings.ToLower(name), kind, local.Format("2006-01-02")),
+		Title: title,
+		Description: fmt.Sprintf("%s at %s for latitude %.4f°, longitude %.4f°",
+			title, local.Format("15:04:05 MST"), observer.Latitude, observer.Longitude),
+		StartTime:  t,
+		EndTime:    t,
+		Type:       eventType,
+		Visibility: visibility,
+		Location:   domain.ConstellationAt(b.position(jde), jde).Name,
+		Source:     sourceName,
+	}
+}
+
+func (r *ephemerisRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs start with the name of the body and end with the local date, so
+	// only that day needs to be searched
+	name, _, _ := strings.Cut(id, "-")
+	if !slices.ContainsFunc(bodies, func(b body) bool { return strings.ToLower(b.name) == name }) || len(id) < len("2006-01-02") {
+		return nil, nil
+	}
+	loc := observer.Location()
+	date, err := time.ParseInLocation("2006-01-02", id[len(id)-len("2006-01-02"):], loc)
+	if err != nil {
+		return nil, nil
+	}
+
+	timeRange := domain.TimeRange{Start: date, End: date.AddDate(0, 0, 1)}
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.ID == id {
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *ephemerisRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []domain.Event
+	for _, event := range events {
+		if event.Type == eventType {
+			filtered = append(filtered, event)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *ephemerisRepository) Name() string {
+	return sourceName
+}
@@ -0,0 +1,175 @@
+package ephemeris
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+// Sunrise and sunset times from the U.S. Naval Observatory almanac, rounded
+// to the minute
+func TestEphemerisRepository_SunriseSunset(t *testing.T) {
+	tests := []struct {
+		name     string
+		observer domain.Observer
+		date     time.Time
+		wantRise time.Time
+		wantSet  time.Time
+		// wantSetID carries the local date of the sunset
+		wantSetID string
+	}{
+		{
+			name:      "New York at the June solstice",
+			observer:  domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"},
+			date:      time.Date(2024, 6, 20, 4, 0, 0, 0, time.UTC),
+			wantRise:  time.Date(2024, 6, 20, 9, 25, 0, 0, time.UTC),
+			wantSet:   time.Date(2024, 6, 21, 0, 31, 0, 0, time.UTC),
+			wantSetID: "sun-set-2024-06-20",
+		},
+		{
+			name:      "London at the December solstice",
+			observer:  domain.Observer{Latitude: 51.5074, Longitude: -0.1278, TimeZone: "Europe/London"},
+			date:      time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC),
+			wantRise:  time.Date(2024, 12, 21, 8, 4, 0, 0, time.UTC),
+			wantSet:   time.Date(2024, 12, 21, 15, 54, 0, 0, time.UTC),
+			wantSetID: "sun-set-2024-12-21",
+		},
+		{
+			name:      "Greenwich at the March equinox",
+			observer:  domain.Observer{Latitude: 51.4769},
+			date:      time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC),
+			wantRise:  time.Date(2024, 3, 20, 6, 2, 0, 0, time.UTC),
+			wantSet:   time.Date(2024, 3, 20, 18, 14, 0, 0, time.UTC),
+			wantSetID: "sun-set-2024-03-20",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewEphemerisRepository()
+			timeRange := domain.TimeRange{Start: tt.date, End: tt.date.Add(24 * time.Hour)}
+
+			rises, err := repo.GetEventsByType(context.Background(), domain.Rise, timeRange, tt.observer)
+			if err != nil {
+				t.Fatalf("GetEventsByType() error = %v", err)
+			}
+			sets, err := repo.GetEventsByType(context.Background(), domain.Set, timeRange, tt.observer)
+			if err != nil {
+				t.Fatalf("GetEventsByType() error = %v", err)
+			}
+
+			assertNear(t, rises, "Sunrise", tt.wantRise)
+			assertNear(t, sets, "Sunset", tt.wantSet)
+			for _, event := range sets {
+				if event.Title == "Sunset" && event.ID != tt.wantSetID {
+					t.Errorf("sunset ID = %v, want %v", event.ID, tt.wantSetID)
+				}
+			}
+		})
+	}
+}
+
+// The Sun crosses the Greenwich meridian at 12:00 UT less the equation of
+// time, whose extremes and stationary values are tabulated in the
+// Astronomical Almanac to the second
+func TestEphemerisRepository_SunTransit(t *testing.T) {
+	greenwich := domain.Observer{Latitude: 51.4769}
+	tests := []struct {
+		name string
+		want time.Time
+	}{
+		{"February minimum of -14m12s", time.Date(2024, 2, 11, 12, 14, 12, 0, time.UTC)},
+		{"May maximum of +3m39s", time.Date(2024, 5, 14, 11, 56, 21, 0, time.UTC)},
+		{"July minimum of -6m32s", time.Date(2024, 7, 26, 12, 6, 32, 0, time.UTC)},
+		{"November maximum of +16m25s", time.Date(2024, 11, 3, 11, 43, 35, 0, time.UTC)},
+	}
+
+	repo := NewEphemerisRepository()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day := time.Date(tt.want.Year(), tt.want.Month(), tt.want.Day(), 0, 0, 0, 0, time.UTC)
+			transits, err := repo.GetEventsByType(context.Background(), domain.Transit, domain.TimeRange{Start: day, End: day.AddDate(0, 0, 1)}, greenwich)
+			if err != nil {
+				t.Fatalf("GetEventsByType() error = %v", err)
+			}
+			for _, event := range transits {
+				if event.Title == "Sun Meridian Transit" && event.StartTime.Sub(tt.want).Abs() > 5*time.Second {
+					t.Errorf("Sun transit at %v, want %v within 5s", event.StartTime, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestEphemerisRepository_GetEventByID(t *testing.T) {
+	repo := NewEphemerisRepository()
+	newYork := domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
+
+	// The day named by the ID is searched, however far it is from now
+	event, err := repo.GetEventByID(context.Background(), "sun-set-2024-06-20", newYork)
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if event == nil || event.StartTime.Sub(time.Date(2024, 6, 21, 0, 31, 0, 0, time.UTC)).Abs() > time.Minute {
+		t.Errorf("GetEventByID() = %+v, want the sunset of June 20", event)
+	}
+
+	for _, id := range []string{"sun-set-2024-13-01", "sun-set", "moon-rise-2024-06-20x", "eclipse-total-2024-04-08"} {
+		event, err := repo.GetEventByID(context.Background(), id, newYork)
+		if err != nil || event != nil {
+			t.Errorf("GetEventByID(%q) = %v, %v, want nil", id, event, err)
+		}
+	}
+}
+
+func TestEphemerisRepository_GetEvents(t *testing.T) {
+	repo := NewEphemerisRepository()
+	greenwich := domain.Observer{Latitude: 51.4769}
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	timeRange := domain.TimeRange{Start: start, End: start.AddDate(0, 0, 30)}
+
+	events, err := repo.GetEvents(context.Background(), timeRange, greenwich)
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+
+	counts := map[string]int{}
+	for i, event := range events {
+		if !event.IsValid() {
+			t.Errorf("GetEvents() returned invalid event %v", event)
+		}
+		if i > 0 && event.StartTime.Before(events[i-1].StartTime) {
+			t.Errorf("GetEvents() events not sorted at %v", event.ID)
+		}
+		counts[event.Title]++
+	}
+
+	// The Sun rises, transits and sets daily, while the Moon skips one of
+	// each roughly once a month
+	for _, title := range []string{"Sunrise", "Sunset", "Sun Meridian Transit"} {
+		if counts[title] != 30 {
+			t.Errorf("GetEvents() got %v %s events, want 30", counts[title], title)
+		}
+	}
+	for _, title := range []string{"Moonrise", "Moonset", "Moon Meridian Transit"} {
+		if counts[title] < 28 || counts[title] > 30 {
+			t.Errorf("GetEvents() got %v %s events, want 28 to 30", counts[title], title)
+		}
+	}
+}
+
+func assertNear(t *testing.T, events []domain.Event, title string, want time.Time) {
+	t.Helper()
+	for _, event := range events {
+		if event.Title != title {
+			continue
+		}
+		if diff := event.StartTime.Sub(want).Abs(); diff > time.Minute {
+			t.Errorf("%s at %v, want %v", title, event.StartTime, want)
+		}
+		return
+	}
+	t.Errorf("no %s event found, want one at %v", title, want)
+}
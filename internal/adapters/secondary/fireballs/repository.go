@@ -0,0 +1,239 @@
+package fireballs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+const (
+	fireballURL = "https://ssd-api.jpl.nasa.gov/fireball.api"
+	sourceName  = "CNEOS Fireball API"
+)
+
+// dateFormat is how the API writes and accepts the times of fireballs, in UTC
+const dateFormat = "2006-01-02T15:04:05"
+
+type fireballRepository struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewFireballRepository creates a repository that reports the fireballs and
+// bolides detected by US Government sensors from the CNEOS fireball dataset
+func NewFireballRepository() *fireballRepository {
+	return &fireballRepository{
+		baseURL:    fireballURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// response is the table the API returns, with a row of strings for each
+// fireball in the order of fields. Unknown values are null.
+type response struct {
+	Count  string      `json:"count"`
+	Fields []string    `json:"fields"`
+	Data   [][]*string `json:"data"`
+}
+
+func (r *fireballRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	events, err := r.fetch(ctx, timeRange.Start, timeRange.End, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []domain.Event
+	for _, event := range events {
+		if event.StartTime.Before(timeRange.Start) || !event.StartTime.Before(timeRange.End) {
+			continue
+		}
+		result = append(result, event)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].StartTime.Before(result[j].StartTime)
+	})
+	return result, nil
+}
+
+// fetch returns the located fireballs from start to end included
+func (r *fireballRepository) fetch(ctx context.Context, start, end time.Time, observer domain.Observer) ([]domain.Event, error) {
+	url := fmt.Sprintf("%s?date-min=%s&date-max=%s&req-loc=true",
+		r.baseURL,
+		start.UTC().Format(dateFormat),
+		end.UTC().Format(dateFormat),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching fireballs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fireball API returned status: %s", resp.Status)
+	}
+
+	var table response
+	if err := json.NewDecoder(resp.Body).Decode(&table); err != nil {
+		return nil, fmt.Errorf("decoding fireballs: %w", err)
+	}
+
+	column := make(map[string]int, len(table.Fields))
+	for i, field := range table.Fields {
+		column[field] = i
+	}
+
+	var events []domain.Event
+	for _, row := range table.Data {
+		event, err := decode(column, row, observer)
+		if err != nil {
+			return nil, fmt.Errorf("decoding fireballs: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// decode builds the event of a row of the table
+func decode(column map[string]int, row []*string, observer domain.Observer) (domain.Event, error) {
+	value := func(field string) string {
+		i, ok := column[field]
+		if !ok || i >= len(row) || row[i] == nil {
+			return ""
+		}
+		return *row[i]
+	}
+	number := func(field string) (*float64, error) {
+		s := value(field)
+		if s == "" {
+			return nil, nil
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field, err)
+		}
+		return &v, nil
+	}
+
+	at, err := time.Parse("2006-01-02 15:04:05", value("date"))
+	if err != nil {
+		return domain.Event{}, err
+	}
+
+	var values [6]*float64
+	for i, field := range []string{"energy", "impact-e", "lat", "lon", "alt", "vel"} {
+		if values[i], err = number(field); err != nil {
+			return domain.Event{}, err
+		}
+	}
+	energy, impact, lat, lon := values[0], values[1], values[2], values[3]
+	if lat == nil || lon == nil {
+		return domain.Event{}, fmt.Errorf("fireball of %s has no location", at.Format(dateFormat))
+	}
+
+	details := &domain.FireballDetails{
+		Latitude:    *lat,
+		Longitude:   *lon,
+		AltitudeKm:  values[4],
+		VelocityKmS: values[5],
+	}
+	if value("lat-dir") == "S" {
+		details.Latitude = -details.Latitude
+	}
+	if value("lon-dir") == "W" {
+		details.Longitude = -details.Longitude
+	}
+	// The radiated energy is given in units of 10^10 joules
+	if energy != nil {
+		details.RadiatedEnergyJ = *energy * 1e10
+	}
+	if impact != nil {
+		details.ImpactEnergyKt = *impact
+	}
+	details.DistanceKm = observer.DistanceKm(details.Latitude, details.Longitude)
+
+	place := position(details.Latitude, details.Longitude)
+	description := fmt.Sprintf("A fireball was detected over %s", place)
+	if details.AltitudeKm != nil {
+		description += fmt.Sprintf(" at an altitude of %.1f km", *details.AltitudeKm)
+	}
+	if details.VelocityKmS != nil {
+		description += fmt.Sprintf(", moving at %.1f km/s", *details.VelocityKmS)
+	}
+	description += fmt.Sprintf(". It radiated %.3g J, from an estimated impact energy of %.3g kt of TNT, %.0f km from the observer",
+		details.RadiatedEnergyJ, details.ImpactEnergyKt, details.DistanceKm)
+
+	return domain.Event{
+		ID:          "fireball-" + at.Format(dateFormat),
+		Title:       fmt.Sprintf("Fireball over %s", place),
+		Description: description,
+		StartTime:   at,
+		EndTime:     at,
+		Type:        domain.Fireball,
+		Visibility:  fmt.Sprintf("Distance: %.0f km", details.DistanceKm),
+		Location:    place,
+		Source:      sourceName,
+		Fireball:    details,
+	}, nil
+}
+
+// position writes a latitude and longitude as "24.3°S 147.1°E"
+func position(lat, lon float64) string {
+	ns, ew := "N", "E"
+	if lat < 0 {
+		ns, lat = "S", -lat
+	}
+	if lon < 0 {
+		ew, lon = "W", -lon
+	}
+	return fmt.Sprintf("%.1f°%s %.1f°%s", lat, ns, lon, ew)
+}
+
+func (r *fireballRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs end with the time of the fireball
+	if !strings.HasPrefix(id, "fireball-") {
+		return nil, nil
+	}
+	at, err := time.Parse(dateFormat, strings.TrimPrefix(id, "fireball-"))
+	if err != nil {
+		return nil, nil
+	}
+
+	timeRange := domain.TimeRange{Start: at, End: at.Add(time.Second)}
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.ID == id {
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *fireballRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	if eventType != domain.Fireball {
+		return nil, nil
+	}
+	return r.GetEvents(ctx, timeRange, observer)
+}
+
+func (r *fireballRepository) Name() string {
+	return sourceName
+}
@@ -0,0 +1,157 @@
+package fireballs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"astralis/internal/adapters/secondary/apitest"
+	"astralis/internal/core/domain"
+)
+
+// newFireballServer stands in for the fireball API, serving the rows of the
+// table recorded in testdata between the requested dates
+func newFireballServer(t *testing.T) *apitest.Server {
+	var recorded response
+	apitest.ReadJSON(t, "fireballs.json", &recorded)
+	times := make([]time.Time, len(recorded.Data))
+	for i, row := range recorded.Data {
+		at, err := time.Parse("2006-01-02 15:04:05", *row[0])
+		if err != nil {
+			t.Fatalf("row %d of fireballs.json: %v", i, err)
+		}
+		times[i] = at
+	}
+
+	return apitest.NewServer(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		min, err1 := time.Parse(dateFormat, query.Get("date-min"))
+		max, err2 := time.Parse(dateFormat, query.Get("date-max"))
+		if query.Get("req-loc") != "true" || err1 != nil || err2 != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"message": "invalid parameters"})
+			return
+		}
+
+		table := response{Fields: recorded.Fields}
+		for i, row := range recorded.Data {
+			if !times[i].Before(min) && !times[i].After(max) {
+				table.Data = append(table.Data, row)
+			}
+		}
+		table.Count = strconv.Itoa(len(table.Data))
+		json.NewEncoder(w).Encode(table)
+	})
+}
+
+func newTestRepository(t *testing.T) (*fireballRepository, *apitest.Server) {
+	server := newFireballServer(t)
+	repo := NewFireballRepository()
+	repo.baseURL = server.URL
+	return repo, server
+}
+
+var newYork = domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
+
+func TestFireballRepository_GetEvents(t *testing.T) {
+	repo, server := newTestRepository(t)
+
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC),
+	}
+	events, err := repo.GetEvents(context.Background(), timeRange, newYork)
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	requests := server.Requests()
+	if len(requests) != 1 || requests[0].Query().Get("date-min") != "2024-05-08T00:00:00" || requests[0].Query().Get("date-max") != "2024-05-11T00:00:00" {
+		t.Errorf("requested %v, want the range", requests)
+	}
+
+	wantIDs := []string{"fireball-2024-05-08T21:40:12", "fireball-2024-05-10T05:14:23", "fireball-2024-05-10T13:02:51"}
+	if len(events) != len(wantIDs) {
+		t.Fatalf("GetEvents() returned %d events, want %d", len(events), len(wantIDs))
+	}
+	for i, id := range wantIDs {
+		event := events[i]
+		if event.ID != id || event.Type != domain.Fireball || event.Source != sourceName || event.Fireball == nil || !event.IsValid() {
+			t.Errorf("event %d = %+v, want %s", i, event, id)
+		}
+	}
+
+	// Southern and western coordinates are negative
+	near := events[0].Fireball
+	if near.Latitude != 41.2 || near.Longitude != -72.9 || near.AltitudeKm == nil || *near.AltitudeKm != 35 ||
+		near.VelocityKmS == nil || *near.VelocityKmS != 17.8 || near.RadiatedEnergyJ != 4.6e10 || near.ImpactEnergyKt != 0.15 {
+		t.Errorf("fireball = %+v", near)
+	}
+	if near.DistanceKm < 105 || near.DistanceKm > 110 {
+		t.Errorf("DistanceKm = %.1f, want about 107", near.DistanceKm)
+	}
+	if events[0].Title != "Fireball over 41.2°N 72.9°W" {
+		t.Errorf("Title = %q", events[0].Title)
+	}
+
+	// Unknown heights and speeds are left out
+	far := events[1].Fireball
+	if far.Latitude != -24.3 || far.Longitude != 147.1 || far.AltitudeKm != nil || far.VelocityKmS != nil {
+		t.Errorf("fireball = %+v", far)
+	}
+}
+
+func TestFireballRepository_GetEventByID(t *testing.T) {
+	repo, _ := newTestRepository(t)
+
+	event, err := repo.GetEventByID(context.Background(), "fireball-2024-05-10T13:02:51", newYork)
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if event == nil || event.Fireball.Latitude != 53 || event.Fireball.Longitude != -1.5 {
+		t.Fatalf("GetEventByID() = %+v, want the fireball over England", event)
+	}
+
+	for _, id := range []string{"fireball-2024-05-10T13:02:52", "neo-3542519-2024-05-10", "fireball-yesterday"} {
+		event, err := repo.GetEventByID(context.Background(), id, newYork)
+		if err != nil || event != nil {
+			t.Errorf("GetEventByID(%q) = %v, %v, want nil", id, event, err)
+		}
+	}
+}
+
+func TestFireballRepository_GetEventsByType(t *testing.T) {
+	repo, server := newTestRepository(t)
+
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+	events, err := repo.GetEventsByType(context.Background(), domain.Fireball, timeRange, newYork)
+	if err != nil || len(events) != 4 {
+		t.Errorf("GetEventsByType() = %d events, %v, want 4", len(events), err)
+	}
+
+	events, err = repo.GetEventsByType(context.Background(), domain.CloseApproach, timeRange, newYork)
+	if err != nil || events != nil {
+		t.Errorf("GetEventsByType(CLOSE_APPROACH) = %v, %v, want nil", events, err)
+	}
+	if got := len(server.Requests()); got != 1 {
+		t.Errorf("sent %d requests, want 1", got)
+	}
+}
+
+func TestFireballRepository_Error(t *testing.T) {
+	server := apitest.NewServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	repo := NewFireballRepository()
+	repo.baseURL = server.URL
+
+	timeRange := domain.TimeRange{Start: time.Now().Add(-24 * time.Hour), End: time.Now()}
+	if _, err := repo.GetEvents(context.Background(), timeRange, newYork); err == nil {
+		t.Error("GetEvents() error = nil, want an error")
+	}
+}
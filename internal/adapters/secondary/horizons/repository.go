@@ -0,0 +1,421 @@
+package horizons
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+)
+
+const (
+	horizonsURL = "https://ssd.jpl.nasa.gov/api/horizons.api"
+	sourceName  = "JPL Horizons"
+)
+
+// Rise, transit and set times are interpolated from a table with eventStep
+// between rows
+const eventStep = 10 * time.Minute
+
+// standardAltitude is the airless altitude of the centre of a point-like
+// body when it rises or sets, allowing for refraction at the horizon
+const standardAltitude = -0.5667
+
+type horizonsRepository struct {
+	baseURL    string
+	bodies     []string
+	httpClient *http.Client
+}
+
+// NewHorizonsRepository creates a repository that tabulates the ephemerides
+// of bodies with the JPL Horizons API at baseURL, and reports the rise,
+// meridian transit and set of the bodies in a comma-separated list of
+// Horizons target IDs, such as "499,599,DES=2000433;" for Mars, Jupiter and
+// Eros. An empty baseURL stands for the JPL server.
+func NewHorizonsRepository(baseURL, bodies string) *horizonsRepository {
+	if baseURL == "" {
+		baseURL = horizonsURL
+	}
+	r := &horizonsRepository{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, body := range strings.Split(bodies, ",") {
+		if body = strings.TrimSpace(body); body != "" {
+			r.bodies = append(r.bodies, body)
+		}
+	}
+	return r
+}
+
+func (r *horizonsRepository) GetEphemeris(ctx context.Context, body string, timeRange domain.TimeRange, step time.Duration, observer domain.Observer) (*domain.Ephemeris, error) {
+	name, rows, err := r.query(ctx, body, timeRange.Start, timeRange.End, step, observer)
+	if err != nil || rows == nil {
+		return nil, err
+	}
+	return &domain.Ephemeris{
+		Body:     body,
+		Name:     name,
+		Observer: observer,
+		Source:   sourceName,
+		Rows:     rows,
+	}, nil
+}
+
+// query asks Horizons for an observer table of a body from start to stop
+// included. It returns no rows when Horizons does not know the body or
+// cannot tell which body is meant.
+func (r *horizonsRepository) query(ctx context.Context, body string, start, stop time.Time, step time.Duration, observer domain.Observer) (string, []domain.EphemerisRow, error) {
+	if step < time.Minute {
+		return "", nil, errors.New("Horizons steps must be at least a minute")
+	}
+
+	// Parameter values are quoted the way the Horizons documentation writes
+	// them. Quantities 1, 4, 9 and 20 are the astrometric RA and Dec, the
+	// apparent azimuth and elevation, the visual magnitude and the range and
+	// range rate.
+	params := url.Values{}
+	params.Set("format", "json")
+	params.Set("COMMAND", quote(body))
+	params.Set("OBJ_DATA", "'NO'")
+	params.Set("MAKE_EPHEM", "'YES'")
+	params.Set("EPHEM_TYPE", "'OBSERVER'")
+	params.Set("CENTER", "'coord@399'")
+	params.Set("COORD_TYPE", "'GEODETIC'")
+	params.Set("SITE_COORD", fmt.Sprintf("'%.6f,%.6f,%.4f'", observer.Longitude, observer.Latitude, observer.Elevation/1000))
+	params.Set("START_TIME", quote(start.UTC().Format("2006-01-02 15:04")))
+	params.Set("STOP_TIME", quote(stop.UTC().Format("2006-01-02 15:04")))
+	params.Set("STEP_SIZE", fmt.Sprintf("'%d m'", int(step/time.Minute)))
+	params.Set("QUANTITIES", "'1,4,9,20'")
+	params.Set("ANG_FORMAT", "'DEG'")
+	params.Set("TIME_DIGITS", "'MINUTES'")
+	params.Set("CSV_FORMAT", "'YES'")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("fetching ephemeris of %s: %w", body, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("Horizons API returned status: %s", resp.Status)
+	}
+
+	var response struct {
+		Result string `json:"result"`
+		Error  string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", nil, fmt.Errorf("decoding ephemeris of %s: %w", body, err)
+	}
+	if response.Error != "" {
+		return "", nil, fmt.Errorf("Horizons API: %s", strings.TrimSpace(response.Error))
+	}
+	return parseResult(response.Result)
+}
+
+func quote(s string) string {
+	return "'" + s + "'"
+}
+
+var (
+	targetName = regexp.MustCompile(`(?m)^Target body name: (.+?)\s*(?:\{.*)?$`)
+	// Major bodies are followed by their ID, such as "Mars (499)"
+	majorBodyID = regexp.MustCompile(`\s*\(\d+\)$`)
+)
+
+// parseResult reads the target name and the rows of the CSV table between
+// the $$SOE and $$EOE markers of a Horizons result
+func parseResult(result string) (string, []domain.EphemerisRow, error) {
+	soe := strings.Index(result, "$$SOE")
+	eoe := strings.Index(result, "$$EOE")
+	if soe < 0 || eoe < soe {
+		// Unknown and ambiguous targets are answered with a message, or with
+		// a list of the matching bodies to choose from
+		if strings.Contains(result, "No matches found") || strings.Contains(result, "Multiple major-bodies match") ||
+			strings.Contains(result, "Matching small-bodies") {
+			return "", nil, nil
+		}
+		return "", nil, fmt.Errorf("Horizons API returned no ephemeris: %s", firstLine(result))
+	}
+
+	name := ""
+	if m := targetName.FindStringSubmatch(result); m != nil {
+		name = majorBodyID.ReplaceAllString(m[1], "")
+	}
+
+	// The column header is the last line naming the date column before the
+	// table
+	var header []string
+	for _, line := range strings.Split(result[:soe], "\n") {
+		if strings.Contains(line, "Date__(UT)") {
+			header = strings.Split(line, ",")
+		}
+	}
+	columns := map[string]int{}
+	for i, h := range header {
+		h = strings.TrimSpace(h)
+		for _, prefix := range []string{"Date", "R.A.", "DEC", "Azi", "Elev", "APmag", "T-mag", "deldot", "delta"} {
+			if _, ok := columns[prefix]; !ok && strings.HasPrefix(h, prefix) {
+				columns[prefix] = i
+				break
+			}
+		}
+	}
+	for _, required := range []string{"Date", "R.A.", "DEC", "Azi", "Elev", "delta", "deldot"} {
+		if _, ok := columns[required]; !ok {
+			return "", nil, fmt.Errorf("Horizons table lacks the %s column", required)
+		}
+	}
+
+	rows := []domain.EphemerisRow{}
+	for _, line := range strings.Split(result[soe+len("$$SOE"):eoe], "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		row, err := parseRow(strings.Split(line, ","), columns)
+		if err != nil {
+			return "", nil, fmt.Errorf("parsing Horizons row %q: %w", strings.TrimSpace(line), err)
+		}
+		rows = append(rows, row)
+	}
+	return name, rows, nil
+}
+
+func parseRow(fields []string, columns map[string]int) (domain.EphemerisRow, error) {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+		return strings.TrimSpace(fields[i])
+	}
+
+	var row domain.EphemerisRow
+	t, err := time.Parse("2006-Jan-02 15:04", field("Date"))
+	if err != nil {
+		return row, err
+	}
+	row.Time = t
+
+	for _, f := range []struct {
+		column string
+		value  *float64
+	}{
+		{"R.A.", &row.RA},
+		{"DEC", &row.Dec},
+		{"Azi", &row.Azimuth},
+		{"Elev", &row.Altitude},
+		{"delta", &row.DistanceAU},
+		{"deldot", &row.RangeRateKmS},
+	} {
+		if *f.value, err = strconv.ParseFloat(field(f.column), 64); err != nil {
+			return row, err
+		}
+	}
+
+	// Magnitudes are "n.a." for bodies Horizons has no photometry for;
+	// comets give their total magnitude as T-mag
+	for _, column := range []string{"APmag", "T-mag"} {
+		if magnitude, err := strconv.ParseFloat(field(column), 64); err == nil {
+			row.Magnitude = &magnitude
+			break
+		}
+	}
+	return row, nil
+}
+
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" && strings.Trim(line, "*") != "" {
+			return line
+		}
+	}
+	return "empty result"
+}
+
+func (r *horizonsRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	var events []domain.Event
+	for _, body := range r.bodies {
+		// One row either side of the range catches crossings at its ends
+		name, rows, err := r.query(ctx, body, timeRange.Start.Add(-eventStep), timeRange.End.Add(eventStep), eventStep, observer)
+		if err != nil {
+			return nil, err
+		}
+		// An unknown body is left out rather than hiding the others
+		if rows == nil {
+			log.Printf("%s: skipping unknown body %q", sourceName, body)
+			continue
+		}
+		if name == "" {
+			name = body
+		}
+		for _, event := range horizonEvents(name, rows, observer) {
+			if event.StartTime.Before(timeRange.Start) || !event.StartTime.Before(timeRange.End) {
+				continue
+			}
+			events = append(events, event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	return events, nil
+}
+
+// horizonEvents interpolates the rises and sets of a body where its altitude
+// crosses the standard altitude between rows, and its meridian transits at
+// the maxima of a parabola through three rows around the highest one
+func horizonEvents(name string, rows []domain.EphemerisRow, observer domain.Observer) []domain.Event {
+	var events []domain.Event
+	for i := 1; i < len(rows); i++ {
+		a, b := rows[i-1], rows[i]
+		step := b.Time.Sub(a.Time)
+
+		if (a.Altitude < standardAltitude) != (b.Altitude < standardAltitude) {
+			f := (standardAltitude - a.Altitude) / (b.Altitude - a.Altitude)
+			t := a.Time.Add(time.Duration(f * float64(step))).Round(time.Second)
+			azimuth := astro.NormalizeDegrees(a.Azimuth + f*angleDiff(b.Azimuth, a.Azimuth))
+			kind := "rise"
+			if b.Altitude < a.Altitude {
+				kind = "set"
+			}
+			events = append(events, horizonEvent(name, kind, t, azimuth, standardAltitude, b, observer))
+		}
+
+		if i+1 < len(rows) {
+			c := rows[i+1]
+			if b.Altitude > a.Altitude && b.Altitude >= c.Altitude {
+				curvature := a.Altitude - 2*b.Altitude + c.Altitude
+				offset, altitude := 0.0, b.Altitude
+				if curvature < 0 {
+					offset = (a.Altitude - c.Altitude) / (2 * curvature)
+					altitude = b.Altitude - (a.Altitude-c.Altitude)*(a.Altitude-c.Altitude)/(8*curvature)
+				}
+				if altitude > standardAltitude {
+					t := b.Time.Add(time.Duration(offset * float64(step))).Round(time.Second)
+					events = append(events, horizonEvent(name, "transit", t, b.Azimuth, altitude, b, observer))
+				}
+			}
+		}
+	}
+	return events
+}
+
+// angleDiff returns a - b in degrees, between -180 and 180
+func angleDiff(a, b float64) float64 {
+	return math.Mod(a-b+540, 360) - 180
+}
+
+// horizonEvent maps a rise, set or transit onto a domain event, dated and
+// described in the observer's time zone
+func horizonEvent(name, kind string, t time.Time, azimuth, altitude float64, row domain.EphemerisRow, observer domain.Observer) domain.Event {
+	local := t.In(observer.Location())
+
+	var title, visibility string
+	var eventType domain.EventType
+	switch kind {
+	case "rise":
+		title, eventType = fmt.Sprintf("%s Rises", name), domain.Rise
+		visibility = fmt.Sprintf("Azimuth: %.2f°", azimuth)
+	case "set":
+		title, eventType = fmt.Sprintf("%s Sets", name), domain.Set
+		visibility = fmt.Sprintf("Azimuth: %.2f°", azimuth)
+	default:
+		title, eventType = fmt.Sprintf("%s Meridian Transit", name), domain.Transit
+		visibility = fmt.Sprintf("Altitude: %.2f°", altitude)
+	}
+
+	description := fmt.Sprintf("%s at %s for latitude %.4f°, longitude %.4f°, %.3f AU away",
+		title, local.Format("15:04:05 MST"), observer.Latitude, observer.Longitude, row.DistanceAU)
+	if row.Magnitude != nil {
+		description += fmt.Sprintf(" at magnitude %.1f", *row.Magnitude)
+	}
+
+	return domain.Event{
+		ID:          fmt.Sprintf("horizons-%s-%s-%s", slug(name), kind, local.Format("2006-01-02")),
+		Title:       title,
+		Description: description,
+		StartTime:   t,
+		EndTime:     t,
+		Type:        eventType,
+		Visibility:  visibility,
+		Location:    domain.ConstellationAt(astro.Equatorial{RA: row.RA, Dec: row.Dec}, astro.J2000).Name,
+		Source:      sourceName,
+	}
+}
+
+// slug keeps the letters and digits of a name, such as "c-2023-a3" for
+// "C/2023 A3"
+func slug(name string) string {
+	return strings.ToLower(strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), "-"))
+}
+
+func (r *horizonsRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs end with the local date of the event
+	if !strings.HasPrefix(id, "horizons-") || len(id) < len("2006-01-02") {
+		return nil, nil
+	}
+	date, err := time.ParseInLocation("2006-01-02", id[len(id)-len("2006-01-02"):], observer.Location())
+	if err != nil {
+		return nil, nil
+	}
+
+	timeRange := domain.TimeRange{Start: date, End: date.AddDate(0, 0, 1)}
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.ID == id {
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *horizonsRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	if eventType != domain.Rise && eventType != domain.Set && eventType != domain.Transit {
+		return nil, nil
+	}
+
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []domain.Event
+	for _, event := range events {
+		if event.Type == eventType {
+			filtered = append(filtered, event)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *horizonsRepository) Name() string {
+	return sourceName
+}
@@ -0,0 +1,210 @@
+package horizons
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"astralis/internal/adapters/secondary/apitest"
+	"astralis/internal/core/domain"
+)
+
+// newHorizonsServer stands in for the Horizons API, answering for Mars with
+// the table recorded in testdata
+func newHorizonsServer(t *testing.T) *apitest.Server {
+	mars := apitest.ReadFile(t, "mars.json")
+
+	return apitest.NewServer(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("format") != "json" || query.Get("CENTER") != "'coord@399'" || query.Get("CSV_FORMAT") != "'YES'" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "unexpected parameters"})
+			return
+		}
+		switch query.Get("COMMAND") {
+		case "'499'":
+			w.Write(mars)
+		case "'Mars'":
+			json.NewEncoder(w).Encode(map[string]string{"result": "\n Multiple major-bodies match string \"MARS*\"\n\n  ID#      Name\n  ----     ----\n     4     Mars Barycenter\n   499     Mars\n"})
+		case "'Vulcan'":
+			json.NewEncoder(w).Encode(map[string]string{"result": "\n*******************************************************************************\n No matches found.\n"})
+		default:
+			json.NewEncoder(w).Encode(map[string]string{"error": "Cannot interpret date. Type \"?!\" or try YYYY-MMM-DD {HH:MN} format."})
+		}
+	})
+}
+
+var newYork = domain.Observer{Latitude: 40.7128, Longitude: -74.0060, Elevation: 10, TimeZone: "America/New_York"}
+
+// may10 is the local day in New York the recorded table covers
+var may10 = domain.TimeRange{
+	Start: time.Date(2024, 5, 10, 4, 0, 0, 0, time.UTC),
+	End:   time.Date(2024, 5, 11, 4, 0, 0, 0, time.UTC),
+}
+
+func TestHorizonsRepository_GetEphemeris(t *testing.T) {
+	server := newHorizonsServer(t)
+	repo := NewHorizonsRepository(server.URL, "")
+
+	ephemeris, err := repo.GetEphemeris(context.Background(), "499", may10, 10*time.Minute, newYork)
+	if err != nil {
+		t.Fatalf("GetEphemeris() error = %v", err)
+	}
+	if ephemeris == nil || ephemeris.Name != "Mars" || ephemeris.Body != "499" || ephemeris.Source != sourceName {
+		t.Fatalf("GetEphemeris() = %+v, want Mars", ephemeris)
+	}
+
+	query := server.Requests()[0].Query()
+	for param, want := range map[string]string{
+		"SITE_COORD": "'-74.006000,40.712800,0.0100'",
+		"START_TIME": "'2024-05-10 04:00'",
+		"STOP_TIME":  "'2024-05-11 04:00'",
+		"STEP_SIZE":  "'10 m'",
+	} {
+		if got := query.Get(param); got != want {
+			t.Errorf("%s = %s, want %s", param, got, want)
+		}
+	}
+
+	if len(ephemeris.Rows) != 147 {
+		t.Fatalf("GetEphemeris() returned %d rows, want 147", len(ephemeris.Rows))
+	}
+	row := ephemeris.Rows[1]
+	if !row.Time.Equal(may10.Start) || row.RA != 6.90629 || row.Dec != 1.62204 || row.Azimuth != 37.177076 || row.Altitude != -40.821214 ||
+		row.Magnitude == nil || *row.Magnitude != 1.098 || row.DistanceAU != 1.94194599517215 || row.RangeRateKmS != -6.5332817 {
+		t.Errorf("row = %+v", row)
+	}
+}
+
+func TestHorizonsRepository_Unknown(t *testing.T) {
+	server := newHorizonsServer(t)
+	repo := NewHorizonsRepository(server.URL, "")
+
+	// Unknown and ambiguous bodies are not found
+	for _, body := range []string{"Vulcan", "Mars"} {
+		ephemeris, err := repo.GetEphemeris(context.Background(), body, may10, time.Hour, newYork)
+		if err != nil || ephemeris != nil {
+			t.Errorf("GetEphemeris(%q) = %v, %v, want nil", body, ephemeris, err)
+		}
+	}
+
+	// Other failures are errors
+	if _, err := repo.GetEphemeris(context.Background(), "2024-99", may10, time.Hour, newYork); err == nil || !strings.Contains(err.Error(), "Cannot interpret date") {
+		t.Errorf("GetEphemeris() error = %v, want the Horizons message", err)
+	}
+	if _, err := repo.GetEphemeris(context.Background(), "499", may10, time.Second, newYork); err == nil {
+		t.Error("GetEphemeris() error = nil, want a step of at least a minute")
+	}
+}
+
+func TestHorizonsRepository_GetEvents(t *testing.T) {
+	server := newHorizonsServer(t)
+	repo := NewHorizonsRepository(server.URL, " 499 ,Vulcan,")
+
+	// Vulcan is unknown to Horizons and left out
+	events, err := repo.GetEvents(context.Background(), may10, newYork)
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if got := len(server.Requests()); got != 2 {
+		t.Errorf("sent %d queries, want one for each body", got)
+	}
+
+	// The table is padded by a step either side of the range
+	if query := server.Requests()[0].Query(); query.Get("START_TIME") != "'2024-05-10 03:50'" || query.Get("STOP_TIME") != "'2024-05-11 04:10'" {
+		t.Errorf("queried %s to %s, want the range with a step either side", query.Get("START_TIME"), query.Get("STOP_TIME"))
+	}
+
+	// The interpolated times agree with the local ephemeris to within a
+	// minute
+	want := []struct {
+		id        string
+		eventType domain.EventType
+		at        time.Time
+	}{
+		{"horizons-mars-rise-2024-05-10", domain.Rise, time.Date(2024, 5, 10, 8, 1, 41, 0, time.UTC)},
+		{"horizons-mars-transit-2024-05-10", domain.Transit, time.Date(2024, 5, 10, 14, 10, 37, 0, time.UTC)},
+		{"horizons-mars-set-2024-05-10", domain.Set, time.Date(2024, 5, 10, 20, 20, 5, 0, time.UTC)},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("GetEvents() returned %d events, want %d", len(events), len(want))
+	}
+	for i, tt := range want {
+		event := events[i]
+		if event.ID != tt.id || event.Type != tt.eventType || event.Source != sourceName || event.Location == "" || !event.IsValid() {
+			t.Errorf("event %d = %+v, want %s", i, event, tt.id)
+		}
+		if diff := event.StartTime.Sub(tt.at); diff < -time.Minute || diff > time.Minute {
+			t.Errorf("%s at %v, want %v", event.ID, event.StartTime, tt.at)
+		}
+	}
+	if events[0].Title != "Mars Rises" || events[0].Visibility != "Azimuth: 87.13°" {
+		t.Errorf("rise = %+v", events[0])
+	}
+	if events[1].Visibility != "Altitude: 51.17°" {
+		t.Errorf("transit = %+v", events[1])
+	}
+}
+
+func TestHorizonsRepository_GetEventByID(t *testing.T) {
+	server := newHorizonsServer(t)
+	repo := NewHorizonsRepository(server.URL, "499")
+
+	event, err := repo.GetEventByID(context.Background(), "horizons-mars-set-2024-05-10", newYork)
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if event == nil || event.Type != domain.Set {
+		t.Fatalf("GetEventByID() = %+v, want the set of Mars", event)
+	}
+	if query := server.Requests()[0].Query(); query.Get("START_TIME") != "'2024-05-10 03:50'" {
+		t.Errorf("queried from %s, want the local day", query.Get("START_TIME"))
+	}
+
+	for _, id := range []string{"horizons-mars-set-2024-05-11", "mars-set-2024-05-10", "horizons-mars"} {
+		event, err := repo.GetEventByID(context.Background(), id, newYork)
+		if err != nil || event != nil {
+			t.Errorf("GetEventByID(%q) = %v, %v, want nil", id, event, err)
+		}
+	}
+}
+
+func TestHorizonsRepository_GetEventsByType(t *testing.T) {
+	server := newHorizonsServer(t)
+	repo := NewHorizonsRepository(server.URL, "499")
+
+	events, err := repo.GetEventsByType(context.Background(), domain.Transit, may10, newYork)
+	if err != nil || len(events) != 1 || events[0].ID != "horizons-mars-transit-2024-05-10" {
+		t.Errorf("GetEventsByType() = %v, %v, want the transit", events, err)
+	}
+
+	events, err = repo.GetEventsByType(context.Background(), domain.Eclipse, may10, newYork)
+	if err != nil || events != nil {
+		t.Errorf("GetEventsByType(ECLIPSE) = %v, %v, want nil", events, err)
+	}
+	if got := len(server.Requests()); got != 1 {
+		t.Errorf("sent %d queries, want 1", got)
+	}
+
+	// Without bodies there is nothing to report
+	events, err = NewHorizonsRepository(server.URL, "").GetEvents(context.Background(), may10, newYork)
+	if err != nil || events != nil {
+		t.Errorf("GetEvents() without bodies = %v, %v, want nil", events, err)
+	}
+}
+
+func TestSlug(t *testing.T) {
+	tests := map[string]string{
+		"Mars":                          "mars",
+		"C/2023 A3 (Tsuchinshan-ATLAS)": "c-2023-a3-tsuchinshan-atlas",
+		"433 Eros (A898 PA)":            "433-eros-a898-pa",
+	}
+	for name, want := range tests {
+		if got := slug(name); got != want {
+			t.Errorf("slug(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
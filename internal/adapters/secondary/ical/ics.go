@@ -0,0 +1,331 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// property is a content line of an iCalendar stream, such as
+// "DTSTART;TZID=Europe/London:20240510T193000"
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// component is a BEGIN and END block, such as a VEVENT, with its properties
+// and the components nested in it
+type component struct {
+	name       string
+	properties []property
+	components []*component
+}
+
+// get returns the first property called name
+func (c *component) get(name string) (property, bool) {
+	for _, p := range c.properties {
+		if p.name == name {
+			return p, true
+		}
+	}
+	return property{}, false
+}
+
+// text returns the unescaped value of the first property called name, or ""
+func (c *component) text(name string) string {
+	p, ok := c.get(name)
+	if !ok {
+		return ""
+	}
+	return unescape(p.value)
+}
+
+// all returns the properties called name, which may repeat
+func (c *component) all(name string) []property {
+	var properties []property
+	for _, p := range c.properties {
+		if p.name == name {
+			properties = append(properties, p)
+		}
+	}
+	return properties
+}
+
+// parse reads the components of an iCalendar stream (RFC 5545)
+func parse(r io.Reader) ([]*component, error) {
+	var (
+		roots []*component
+		stack []*component
+	)
+	handle := func(line string) error {
+		if line == "" {
+			return nil
+		}
+		p, err := parseProperty(line)
+		if err != nil {
+			return err
+		}
+		switch p.name {
+		case "BEGIN":
+			c := &component{name: strings.ToUpper(p.value)}
+			if len(stack) == 0 {
+				roots = append(roots, c)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.components = append(parent.components, c)
+			}
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].name != strings.ToUpper(p.value) {
+				return fmt.Errorf("unexpected END:%s", p.value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return fmt.Errorf("property %s outside a component", p.name)
+			}
+			c := stack[len(stack)-1]
+			c.properties = append(c.properties, p)
+		}
+		return nil
+	}
+
+	// Long lines are folded onto continuation lines that start with a space
+	// or a tab
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	var line strings.Builder
+	number, start := 0, 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t") {
+			line.WriteString(text[1:])
+			continue
+		}
+		if err := handle(line.String()); err != nil {
+			return nil, fmt.Errorf("line %d: %w", start, err)
+		}
+		line.Reset()
+		line.WriteString(text)
+		start = number
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := handle(line.String()); err != nil {
+		return nil, fmt.Errorf("line %d: %w", start, err)
+	}
+	if len(stack) != 0 {
+		return nil, fmt.Errorf("%s is not ended", stack[len(stack)-1].name)
+	}
+	return roots, nil
+}
+
+// parseProperty splits a content line into its name, parameters and value.
+// Parameter values may be quoted to hold colons and semicolons.
+func parseProperty(line string) (property, error) {
+	p := property{params: map[string]string{}}
+
+	end := strings.IndexAny(line, ";:")
+	if end <= 0 {
+		return property{}, fmt.Errorf("malformed content line %q", line)
+	}
+	p.name = strings.ToUpper(line[:end])
+	rest := line[end:]
+
+	for strings.HasPrefix(rest, ";") {
+		rest = rest[1:]
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return property{}, fmt.Errorf("malformed parameter in %q", line)
+		}
+		name := strings.ToUpper(rest[:eq])
+		rest = rest[eq+1:]
+
+		var value strings.Builder
+		quoted := false
+		i := 0
+	scan:
+		for ; i < len(rest); i++ {
+			switch c := rest[i]; {
+			case c == '"':
+				quoted = !quoted
+			case !quoted && (c == ';' || c == ':'):
+				break scan
+			default:
+				value.WriteByte(c)
+			}
+		}
+		p.params[name] = value.String()
+		rest = rest[i:]
+	}
+
+	if !strings.HasPrefix(rest, ":") {
+		return property{}, fmt.Errorf("missing value in %q", line)
+	}
+	p.value = rest[1:]
+	return p, nil
+}
+
+// unescape decodes a TEXT value
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// splitText splits a list of TEXT values, such as CATEGORIES, on the commas
+// that are not escaped, and unescapes each value
+func splitText(s string) []string {
+	var values []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			values = append(values, unescape(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(values, unescape(s[start:]))
+}
+
+// dateTime is a DATE or DATE-TIME value as written, before it is placed in
+// time. Wall holds the date and time of day in UTC fields.
+type dateTime struct {
+	wall time.Time
+	// date is true for DATE values, which have no time of day
+	date bool
+	// utc is true for DATE-TIME values ending in Z
+	utc bool
+	// tzid names the time zone of DATE-TIME values that have one; values
+	// with neither tzid nor utc are floating
+	tzid string
+}
+
+// parseDateTimes parses the comma-separated DATE or DATE-TIME values of a
+// property such as DTSTART or EXDATE
+func parseDateTimes(p property) ([]dateTime, error) {
+	var values []dateTime
+	for _, s := range strings.Split(p.value, ",") {
+		v, err := parseDateTime(s, p.params["VALUE"] == "DATE" || len(s) == len("20060102"))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p.name, err)
+		}
+		if !v.date && !v.utc {
+			v.tzid = p.params["TZID"]
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func parseDateTime(s string, date bool) (dateTime, error) {
+	if date {
+		t, err := time.Parse("20060102", s)
+		if err != nil {
+			return dateTime{}, err
+		}
+		return dateTime{wall: t, date: true}, nil
+	}
+	utc := strings.HasSuffix(s, "Z")
+	t, err := time.Parse("20060102T150405", strings.TrimSuffix(s, "Z"))
+	if err != nil {
+		return dateTime{}, err
+	}
+	return dateTime{wall: t, utc: utc}, nil
+}
+
+// parseDuration parses a DURATION value such as "PT1H30M" or "-P1W". Days
+// and weeks are returned apart from the hours, minutes and seconds, as they
+// are nominal and follow the wall clock across daylight saving changes.
+func parseDuration(s string) (days int, d time.Duration, err error) {
+	sign := 1
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, 0, fmt.Errorf("malformed duration %q", s)
+	}
+
+	inTime := false
+	number := 0
+	digits := false
+	for _, c := range s[1:] {
+		switch {
+		case c >= '0' && c <= '9':
+			number = number*10 + int(c-'0')
+			digits = true
+			continue
+		case c == 'T' && !inTime:
+			inTime = true
+			continue
+		}
+		if !digits {
+			return 0, 0, fmt.Errorf("malformed duration %q", s)
+		}
+		switch {
+		case c == 'W' && !inTime:
+			days += 7 * number
+		case c == 'D' && !inTime:
+			days += number
+		case c == 'H' && inTime:
+			d += time.Duration(number) * time.Hour
+		case c == 'M' && inTime:
+			d += time.Duration(number) * time.Minute
+		case c == 'S' && inTime:
+			d += time.Duration(number) * time.Second
+		default:
+			return 0, 0, fmt.Errorf("malformed duration %q", s)
+		}
+		number, digits = 0, false
+	}
+	if digits {
+		return 0, 0, fmt.Errorf("malformed duration %q", s)
+	}
+	return sign * days, time.Duration(sign) * d, nil
+}
+
+// parseOffset parses a UTC offset such as "-0500" or "+053000" into seconds
+func parseOffset(s string) (int, error) {
+	if len(s) != 5 && len(s) != 7 || s[0] != '+' && s[0] != '-' {
+		return 0, fmt.Errorf("malformed UTC offset %q", s)
+	}
+	var fields [3]int
+	for i := 0; 1+2*i < len(s); i++ {
+		v, err := strconv.Atoi(s[1+2*i : 3+2*i])
+		if err != nil {
+			return 0, fmt.Errorf("malformed UTC offset %q", s)
+		}
+		fields[i] = v
+	}
+	offset := fields[0]*3600 + fields[1]*60 + fields[2]
+	if s[0] == '-' {
+		offset = -offset
+	}
+	return offset, nil
+}
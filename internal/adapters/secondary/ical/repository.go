@@ -0,0 +1,520 @@
+package ical
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"astralis/internal/adapters/secondary/ttlcache"
+	"astralis/internal/core/domain"
+)
+
+const sourceName = "iCalendar Feed"
+
+// idTimeFormat is how event IDs end, with the UTC start of the occurrence
+const idTimeFormat = "2006-01-02T15:04:05"
+
+// calendarTTL is how long a parsed feed is reused before it is read again,
+// so that looking up an event does not fetch the feed anew
+const calendarTTL = 5 * time.Minute
+
+// maxOffset is the largest UTC offset of any time zone, by which wall-clock
+// times may run ahead of or behind UTC
+const maxOffset = 14 * time.Hour
+
+type calendarRepository struct {
+	// source is the URL or file the feed is read from
+	source string
+	// categories maps upper-case categories to event types
+	categories map[string]domain.EventType
+	httpClient *http.Client
+	calendars  *ttlcache.Cache[*calendar]
+}
+
+// NewCalendarRepository creates a repository that reports the events of an
+// iCalendar feed, such as a planetarium or club calendar, read from an
+// http, https or webcal URL or from a file, and read again once it is
+// calendarTTL old. An event takes the
+// type of the first of its categories found in categories, keyed in upper
+// case, and is OTHER otherwise.
+func NewCalendarRepository(source string, categories map[string]domain.EventType) *calendarRepository {
+	return &calendarRepository{
+		source:     source,
+		categories: categories,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		calendars:  ttlcache.New[*calendar](calendarTTL),
+	}
+}
+
+// ParseCategories parses a table of the event types of categories written
+// as "category=TYPE;category=...", such as
+// "Eclipse=ECLIPSE;Star Party=DARK_WINDOW"
+func ParseCategories(s string) (map[string]domain.EventType, error) {
+	categories := make(map[string]domain.EventType)
+	for _, entry := range strings.Split(s, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		category, eventType, ok := strings.Cut(entry, "=")
+		category, eventType = strings.TrimSpace(category), strings.TrimSpace(eventType)
+		if !ok || category == "" || eventType == "" {
+			return nil, fmt.Errorf("category %q: want category=TYPE", entry)
+		}
+		t := domain.EventType(strings.ToUpper(eventType))
+		if !t.IsValid() {
+			return nil, fmt.Errorf("category %q: unknown event type %s", category, eventType)
+		}
+		categories[strings.ToUpper(category)] = t
+	}
+	return categories, nil
+}
+
+// calendar is the content of a feed
+type calendar struct {
+	// name is the X-WR-CALNAME most calendar applications give feeds
+	name   string
+	zones  map[string]*definedZone
+	events []*component
+}
+
+func readCalendar(r io.Reader) (*calendar, error) {
+	components, err := parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &calendar{zones: make(map[string]*definedZone)}
+	for _, c := range components {
+		if c.name != "VCALENDAR" {
+			continue
+		}
+		if cal.name == "" {
+			cal.name = c.text("X-WR-CALNAME")
+		}
+		for _, sub := range c.components {
+			switch sub.name {
+			case "VEVENT":
+				cal.events = append(cal.events, sub)
+			case "VTIMEZONE":
+				tzid := sub.text("TZID")
+				z, err := parseTimeZone(sub)
+				if err != nil {
+					return nil, fmt.Errorf("time zone %s: %w", tzid, err)
+				}
+				cal.zones[tzid] = z
+			}
+		}
+	}
+	return cal, nil
+}
+
+// zone returns the zone of a value. Floating times and dates are the
+// observer's.
+func (c *calendar) zone(v dateTime, floating *time.Location) (zone, error) {
+	switch {
+	case v.utc:
+		return locationZone{time.UTC}, nil
+	case v.date || v.tzid == "":
+		return locationZone{floating}, nil
+	}
+	// Most feeds name IANA zones, whose rules are more complete than the
+	// VTIMEZONE written alongside
+	if loc, err := time.LoadLocation(v.tzid); err == nil {
+		return locationZone{loc}, nil
+	}
+	if z, ok := c.zones[v.tzid]; ok {
+		return z, nil
+	}
+	return nil, fmt.Errorf("unknown time zone %q", v.tzid)
+}
+
+// timing is the start of an event and how long its occurrences last
+type timing struct {
+	start dateTime
+	zone  zone
+	// days and length give the end of an occurrence, days on the wall clock
+	// and length in elapsed time after its start
+	days   int
+	length time.Duration
+}
+
+func (c *calendar) timing(e *component, floating *time.Location) (timing, error) {
+	p, ok := e.get("DTSTART")
+	if !ok {
+		return timing{}, fmt.Errorf("event without DTSTART")
+	}
+	values, err := parseDateTimes(p)
+	if err != nil {
+		return timing{}, err
+	}
+	t := timing{start: values[0]}
+	if t.zone, err = c.zone(t.start, floating); err != nil {
+		return timing{}, err
+	}
+
+	if p, ok := e.get("DTEND"); ok {
+		values, err := parseDateTimes(p)
+		if err != nil {
+			return timing{}, err
+		}
+		end := values[0]
+		if t.start.date {
+			t.days = int(end.wall.Sub(t.start.wall).Hours() / 24)
+			return t, nil
+		}
+		endZone, err := c.zone(end, floating)
+		if err != nil {
+			return timing{}, err
+		}
+		t.length = endZone.at(end.wall).Sub(t.zone.at(t.start.wall))
+		return t, nil
+	}
+	if p, ok := e.get("DURATION"); ok {
+		if t.days, t.length, err = parseDuration(p.value); err != nil {
+			return timing{}, err
+		}
+		return t, nil
+	}
+	// Without an end, dates last the day and times are instants
+	if t.start.date {
+		t.days = 1
+	}
+	return t, nil
+}
+
+// occurrence is an instance of an event
+type occurrence struct {
+	event      *component
+	start, end time.Time
+	allDay     bool
+	recurring  bool
+}
+
+// overlaps checks whether the occurrence takes place during the range;
+// instants count at the start of the range but not at its end
+func (o occurrence) overlaps(timeRange domain.TimeRange) bool {
+	if !o.start.Before(timeRange.End) {
+		return false
+	}
+	return o.end.After(timeRange.Start) || !o.start.Before(timeRange.Start)
+}
+
+// occurrences expands the events of the calendar during the range,
+// including the recurrences of RRULE and RDATE less those of EXDATE.
+// Occurrences moved or changed by an event with a RECURRENCE-ID are taken
+// from it instead. Events whose dates cannot be read are skipped.
+func (c *calendar) occurrences(timeRange domain.TimeRange, floating *time.Location) []occurrence {
+	// Overrides, including cancellations of single occurrences, are keyed
+	// by UID and the instant of the occurrence they replace
+	overridden := make(map[string]bool)
+	var masters, overrides []*component
+	for _, e := range c.events {
+		p, ok := e.get("RECURRENCE-ID")
+		if !ok {
+			masters = append(masters, e)
+			continue
+		}
+		values, err := parseDateTimes(p)
+		if err != nil {
+			continue
+		}
+		z, err := c.zone(values[0], floating)
+		if err != nil {
+			continue
+		}
+		overridden[e.text("UID")+"|"+z.at(values[0].wall).UTC().Format(idTimeFormat)] = true
+		overrides = append(overrides, e)
+	}
+
+	// Recurrences are expanded in wall-clock time, over the range widened by
+	// the largest offset from UTC either way
+	startWall, endWall := timeRange.Start.UTC().Add(-maxOffset), timeRange.End.UTC().Add(maxOffset)
+	var result []occurrence
+	emit := func(e *component, t timing, wall, at time.Time, recurring bool) {
+		o := occurrence{
+			event:     e,
+			start:     at.UTC(),
+			end:       t.zone.at(wall.AddDate(0, 0, t.days)).Add(t.length).UTC(),
+			allDay:    t.start.date,
+			recurring: recurring,
+		}
+		if !strings.EqualFold(e.text("STATUS"), "CANCELLED") && o.overlaps(timeRange) {
+			result = append(result, o)
+		}
+	}
+
+	for _, e := range masters {
+		t, err := c.timing(e, floating)
+		if err != nil {
+			continue
+		}
+
+		walls := []time.Time{t.start.wall}
+		p, recurring := e.get("RRULE")
+		if recurring {
+			rule, err := parseRRule(p.value)
+			if err != nil {
+				continue
+			}
+			// Occurrences that began before the range may last into it
+			from := startWall.AddDate(0, 0, -t.days).Add(-t.length)
+			walls = rule.instances(t.start.wall, from, endWall, t.zone)
+		}
+
+		// Occurrences are matched by their instant, as RDATE and EXDATE
+		// may be written in other zones than DTSTART
+		type instance struct{ wall, at time.Time }
+		var instances []instance
+		for _, wall := range walls {
+			instances = append(instances, instance{wall, t.zone.at(wall)})
+		}
+		for _, p := range e.all("RDATE") {
+			values, err := parseDateTimes(p)
+			if err != nil {
+				continue
+			}
+			for _, v := range values {
+				if z, err := c.zone(v, floating); err == nil {
+					instances = append(instances, instance{v.wall, z.at(v.wall)})
+					recurring = true
+				}
+			}
+		}
+		excluded := make(map[int64]bool)
+		for _, p := range e.all("EXDATE") {
+			values, err := parseDateTimes(p)
+			if err != nil {
+				continue
+			}
+			for _, v := range values {
+				if z, err := c.zone(v, floating); err == nil {
+					excluded[z.at(v.wall).Unix()] = true
+				}
+			}
+		}
+
+		uid := e.text("UID")
+		for _, i := range instances {
+			if excluded[i.at.Unix()] || overridden[uid+"|"+i.at.UTC().Format(idTimeFormat)] {
+				continue
+			}
+			emit(e, t, i.wall, i.at, recurring)
+		}
+	}
+
+	for _, e := range overrides {
+		t, err := c.timing(e, floating)
+		if err != nil {
+			continue
+		}
+		emit(e, t, t.start.wall, t.zone.at(t.start.wall), true)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].start.Before(result[j].start)
+	})
+	return result
+}
+
+// hash shortens a feed source or event UID for use in event IDs
+func hash(s string) string {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// idPrefix starts the IDs of the events of the feed
+func (r *calendarRepository) idPrefix() string {
+	return "ical-" + hash(r.source) + "-"
+}
+
+func (r *calendarRepository) event(cal *calendar, o occurrence) domain.Event {
+	e := o.event
+	details := &domain.CalendarDetails{
+		UID:       e.text("UID"),
+		Calendar:  cal.name,
+		URL:       e.text("URL"),
+		AllDay:    o.allDay,
+		Recurring: o.recurring,
+	}
+	eventType := domain.Other
+	for _, p := range e.all("CATEGORIES") {
+		for _, category := range splitText(p.value) {
+			category = strings.TrimSpace(category)
+			if category == "" {
+				continue
+			}
+			if t, ok := r.categories[strings.ToUpper(category)]; ok && eventType == domain.Other {
+				eventType = t
+			}
+			details.Categories = append(details.Categories, category)
+		}
+	}
+
+	title := e.text("SUMMARY")
+	if title == "" {
+		title = "Untitled event"
+	}
+	description := e.text("DESCRIPTION")
+	if description == "" {
+		description = title
+	}
+	source := sourceName
+	if cal.name != "" {
+		source = cal.name
+	}
+
+	return domain.Event{
+		ID:          r.idPrefix() + hash(details.UID) + "-" + o.start.UTC().Format(idTimeFormat),
+		Title:       title,
+		Description: description,
+		StartTime:   o.start,
+		EndTime:     o.end,
+		Type:        eventType,
+		Location:    e.text("LOCATION"),
+		Source:      source,
+		Calendar:    details,
+	}
+}
+
+// feedURL returns the http or https URL of the feed, and false when it is
+// read from a file
+func (r *calendarRepository) feedURL() (string, bool) {
+	source := r.source
+	if strings.HasPrefix(source, "webcal://") {
+		source = "https://" + strings.TrimPrefix(source, "webcal://")
+	}
+	return source, strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// label names the feed in logs and errors. Feeds read from a URL are named
+// by its host alone, as their path or query may hold an access token.
+func (r *calendarRepository) label() string {
+	source, ok := r.feedURL()
+	if !ok {
+		return source
+	}
+	if u, err := url.Parse(source); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return "feed " + hash(r.source)
+}
+
+// open returns the content of the feed
+func (r *calendarRepository) open(ctx context.Context) (io.ReadCloser, error) {
+	source, ok := r.feedURL()
+	if !ok {
+		return os.Open(source)
+	}
+
+	// The errors of the client quote the URL, so they are reported without it
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request for calendar %s: %w", r.label(), withoutURL(err))
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching calendar %s: %w", r.label(), withoutURL(err))
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("calendar feed returned status: %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// withoutURL returns the error a *url.Error wraps, and other errors as they are
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// load returns the parsed feed, reading it unless it was read less than
+// calendarTTL ago
+func (r *calendarRepository) load(ctx context.Context) (*calendar, error) {
+	if cal, ok := r.calendars.Get(r.source); ok {
+		return cal, nil
+	}
+
+	body, err := r.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	cal, err := readCalendar(body)
+	if err != nil {
+		return nil, fmt.Errorf("parsing calendar %s: %w", r.label(), err)
+	}
+	r.calendars.Put(r.source, cal)
+	return cal, nil
+}
+
+func (r *calendarRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	cal, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []domain.Event
+	for _, o := range cal.occurrences(timeRange, observer.Location()) {
+		events = append(events, r.event(cal, o))
+	}
+	return events, nil
+}
+
+func (r *calendarRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs end with the start of the occurrence
+	if !strings.HasPrefix(id, r.idPrefix()) || len(id) < len(idTimeFormat) {
+		return nil, nil
+	}
+	start, err := time.Parse(idTimeFormat, id[len(id)-len(idTimeFormat):])
+	if err != nil {
+		return nil, nil
+	}
+
+	timeRange := domain.TimeRange{Start: start, End: start.Add(time.Second)}
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.ID == id {
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *calendarRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []domain.Event
+	for _, event := range events {
+		if event.Type == eventType {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+func (r *calendarRepository) Name() string {
+	return sourceName + " " + r.label()
+}
@@ -0,0 +1,490 @@
+package ical
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+var newYork = domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
+
+var clubCategories = map[string]domain.EventType{
+	"STAR PARTY": domain.DarkWindow,
+	"ECLIPSE":    domain.Eclipse,
+	"ISS":        domain.Satellite,
+}
+
+func newClubRepository() *calendarRepository {
+	return NewCalendarRepository(filepath.Join("testdata", "club.ics"), clubCategories)
+}
+
+func TestCalendarRepository_GetEvents(t *testing.T) {
+	repo := newClubRepository()
+
+	may := domain.TimeRange{
+		Start: time.Date(2024, 5, 1, 4, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 6, 1, 4, 0, 0, 0, time.UTC),
+	}
+	events, err := repo.GetEvents(context.Background(), may, newYork)
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+
+	want := []struct {
+		title     string
+		eventType domain.EventType
+		start     time.Time
+		end       time.Time
+	}{
+		// The second Friday is moved to the Saturday by its override
+		{"Monthly Star Party (rain date)", domain.DarkWindow, time.Date(2024, 5, 12, 0, 30, 0, 0, time.UTC), time.Date(2024, 5, 12, 3, 30, 0, 0, time.UTC)},
+		// The Windows zone of the lecture is read from its VTIMEZONE
+		{"Lecture Series: Variable Stars", domain.Other, time.Date(2024, 5, 15, 23, 0, 0, 0, time.UTC), time.Date(2024, 5, 16, 0, 30, 0, 0, time.UTC)},
+		{"ISS Flyover Watch", domain.Satellite, time.Date(2024, 5, 20, 2, 15, 0, 0, time.UTC), time.Date(2024, 5, 20, 2, 15, 0, 0, time.UTC)},
+		{"Lecture Series: Variable Stars", domain.Other, time.Date(2024, 5, 29, 23, 0, 0, 0, time.UTC), time.Date(2024, 5, 30, 0, 30, 0, 0, time.UTC)},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("GetEvents() returned %d events, want %d: %v", len(events), len(want), events)
+	}
+	for i, tt := range want {
+		event := events[i]
+		if event.Title != tt.title || event.Type != tt.eventType || !event.StartTime.Equal(tt.start) || !event.EndTime.Equal(tt.end) {
+			t.Errorf("event %d = %s %s %v to %v, want %s %s %v to %v", i,
+				event.Title, event.Type, event.StartTime, event.EndTime, tt.title, tt.eventType, tt.start, tt.end)
+		}
+		if event.Source != "Riverside Astronomy Club" || event.Calendar == nil || !event.IsValid() ||
+			!strings.HasPrefix(event.ID, repo.idPrefix()) {
+			t.Errorf("event %d = %+v", i, event)
+		}
+	}
+
+	lecture := events[1]
+	if lecture.Location != "Library Hall" || !lecture.Calendar.Recurring || lecture.Calendar.AllDay ||
+		lecture.Calendar.Categories[0] != "Lecture" || lecture.Description != lecture.Title {
+		t.Errorf("lecture = %+v, %+v", lecture, lecture.Calendar)
+	}
+}
+
+func TestCalendarRepository_Recurrence(t *testing.T) {
+	repo := newClubRepository()
+
+	tests := []struct {
+		name   string
+		start  time.Time
+		end    time.Time
+		titles []string
+		starts []time.Time
+	}{
+		{
+			// February is excluded, and the evening stays at 20:00 local
+			// time across the change to daylight saving time
+			name:   "winter and spring",
+			start:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			end:    time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC),
+			titles: []string{"Monthly Star Party", "Monthly Star Party", "Total Solar Eclipse Trip", "Monthly Star Party"},
+			starts: []time.Time{
+				time.Date(2024, 1, 13, 1, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 9, 1, 0, 0, 0, time.UTC),
+				time.Date(2024, 4, 8, 4, 0, 0, 0, time.UTC),
+				time.Date(2024, 4, 13, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// The last lecture falls on UNTIL and July's star party is
+			// cancelled
+			name:   "summer",
+			start:  time.Date(2024, 6, 1, 4, 0, 0, 0, time.UTC),
+			end:    time.Date(2024, 8, 1, 4, 0, 0, 0, time.UTC),
+			titles: []string{"Lecture Series: Variable Stars", "Monthly Star Party"},
+			starts: []time.Time{
+				time.Date(2024, 6, 12, 23, 0, 0, 0, time.UTC),
+				time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// COUNT ends the star parties in December
+			name:   "new year",
+			start:  time.Date(2024, 12, 1, 5, 0, 0, 0, time.UTC),
+			end:    time.Date(2025, 2, 1, 5, 0, 0, 0, time.UTC),
+			titles: []string{"Monthly Star Party"},
+			starts: []time.Time{time.Date(2024, 12, 14, 1, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := repo.GetEvents(context.Background(), domain.TimeRange{Start: tt.start, End: tt.end}, newYork)
+			if err != nil {
+				t.Fatalf("GetEvents() error = %v", err)
+			}
+			if len(events) != len(tt.titles) {
+				t.Fatalf("GetEvents() returned %d events, want %d: %v", len(events), len(tt.titles), events)
+			}
+			for i, event := range events {
+				if event.Title != tt.titles[i] || !event.StartTime.Equal(tt.starts[i]) {
+					t.Errorf("event %d = %s at %v, want %s at %v", i, event.Title, event.StartTime, tt.titles[i], tt.starts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCalendarRepository_AllDay(t *testing.T) {
+	repo := newClubRepository()
+	april8 := domain.TimeRange{
+		Start: time.Date(2024, 4, 8, 12, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 4, 8, 13, 0, 0, 0, time.UTC),
+	}
+
+	// Dates follow the observer's time zone
+	tokyo := domain.Observer{Latitude: 35.68, Longitude: 139.69, TimeZone: "Asia/Tokyo"}
+	for _, observer := range []domain.Observer{newYork, tokyo} {
+		events, err := repo.GetEventsByType(context.Background(), domain.Eclipse, april8, observer)
+		if err != nil {
+			t.Fatalf("GetEventsByType() error = %v", err)
+		}
+		if len(events) != 1 {
+			t.Fatalf("GetEventsByType() returned %d events for %s, want 1", len(events), observer.TimeZone)
+		}
+		event := events[0]
+		local := event.StartTime.In(observer.Location())
+		if local.Hour() != 0 || local.Day() != 8 || event.EndTime.Sub(event.StartTime) != 24*time.Hour || !event.Calendar.AllDay {
+			t.Errorf("eclipse for %s = %v to %v", observer.TimeZone, event.StartTime, event.EndTime)
+		}
+		if event.Description != "Bus to Niagara Falls for totality." || event.Calendar.Recurring {
+			t.Errorf("eclipse = %+v", event)
+		}
+	}
+}
+
+func TestCalendarRepository_GetEventByID(t *testing.T) {
+	repo := newClubRepository()
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+	events, err := repo.GetEvents(context.Background(), timeRange, newYork)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("GetEvents() = %v, %v, want the January star party", events, err)
+	}
+
+	event, err := repo.GetEventByID(context.Background(), events[0].ID, newYork)
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if event == nil || !event.StartTime.Equal(events[0].StartTime) {
+		t.Fatalf("GetEventByID() = %+v, want %+v", event, events[0])
+	}
+	want := "Bring your telescope, binoculars or just yourself; hot cocoa provided.\nParking at the north lot."
+	if event.Description != want || event.Calendar.URL != "https://riverside-astro.example/star-party" ||
+		len(event.Calendar.Categories) != 2 || event.Calendar.Categories[1] != "Observing" {
+		t.Errorf("GetEventByID() = %+v, %+v", event, event.Calendar)
+	}
+
+	// The February occurrence is excluded, and other feeds' IDs are not
+	// looked up
+	other := NewCalendarRepository(filepath.Join("testdata", "other.ics"), nil)
+	for _, id := range []string{
+		strings.Replace(events[0].ID, "2024-01-13T01", "2024-02-10T01", 1),
+		strings.Replace(events[0].ID, repo.idPrefix(), other.idPrefix(), 1),
+		repo.idPrefix() + "nonsense",
+	} {
+		event, err := repo.GetEventByID(context.Background(), id, newYork)
+		if err != nil || event != nil {
+			t.Errorf("GetEventByID(%q) = %v, %v, want nil", id, event, err)
+		}
+	}
+}
+
+func TestCalendarRepository_URL(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "club.ics"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/club.ics" {
+			http.NotFound(w, r)
+			return
+		}
+		fetches++
+		w.Header().Set("Content-Type", "text/calendar")
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 5, 21, 0, 0, 0, 0, time.UTC),
+	}
+	repo := NewCalendarRepository(server.URL+"/club.ics", clubCategories)
+	events, err := repo.GetEvents(context.Background(), timeRange, newYork)
+	if err != nil || len(events) != 1 || events[0].Title != "ISS Flyover Watch" {
+		t.Fatalf("GetEvents() = %v, %v, want the ISS watch", events, err)
+	}
+
+	// The feed read a moment ago is reused to look up its events
+	event, err := repo.GetEventByID(context.Background(), events[0].ID, newYork)
+	if err != nil || event == nil || event.Title != "ISS Flyover Watch" {
+		t.Errorf("GetEventByID() = %v, %v, want the ISS watch", event, err)
+	}
+	if fetches != 1 {
+		t.Errorf("fetched the feed %d times, want once", fetches)
+	}
+
+	if _, err := NewCalendarRepository(server.URL+"/missing.ics", nil).GetEvents(context.Background(), timeRange, newYork); err == nil {
+		t.Error("GetEvents() error = nil, want an error for a missing feed")
+	}
+	if _, err := NewCalendarRepository(filepath.Join("testdata", "missing.ics"), nil).GetEvents(context.Background(), timeRange, newYork); err == nil {
+		t.Error("GetEvents() error = nil, want an error for a missing file")
+	}
+
+	// The access token in the URL of a feed is kept out of its name and
+	// errors
+	private := NewCalendarRepository("webcal://"+strings.TrimPrefix(server.URL, "http://")+"/private/s3cret/club.ics?token=s3cret", nil)
+	if name := private.Name(); strings.Contains(name, "s3cret") || !strings.Contains(name, strings.TrimPrefix(server.URL, "http://")) {
+		t.Errorf("Name() = %q, want the host alone", name)
+	}
+	server.Close()
+	if _, err := private.GetEvents(context.Background(), timeRange, newYork); err == nil || strings.Contains(err.Error(), "s3cret") {
+		t.Errorf("GetEvents() error = %v, want an error without the token", err)
+	}
+}
+
+func TestParseCategories(t *testing.T) {
+	categories, err := ParseCategories("Star Party=dark_window; Eclipse = ECLIPSE;")
+	if err != nil {
+		t.Fatalf("ParseCategories() error = %v", err)
+	}
+	if len(categories) != 2 || categories["STAR PARTY"] != domain.DarkWindow || categories["ECLIPSE"] != domain.Eclipse {
+		t.Errorf("ParseCategories() = %v", categories)
+	}
+
+	for _, s := range []string{"Star Party", "=ECLIPSE", "Eclipse=", "Star Party=STAR_PARTY"} {
+		if _, err := ParseCategories(s); err == nil {
+			t.Errorf("ParseCategories(%q) error = nil, want an error", s)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	ics := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY;LANGUAGE=en:Talk: \"Comets\r\n  and Meteors\"\r\n" +
+		"ATTENDEE;CN=\"Doe; Jane\";ROLE=CHAIR:mailto:jane@example.com\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	components, err := parse(strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("parse() error = %v", err)
+	}
+	if len(components) != 1 || len(components[0].components) != 1 {
+		t.Fatalf("parse() = %+v", components)
+	}
+	event := components[0].components[0]
+	if got := event.text("SUMMARY"); got != `Talk: "Comets and Meteors"` {
+		t.Errorf("SUMMARY = %q", got)
+	}
+	attendee, _ := event.get("ATTENDEE")
+	if attendee.params["CN"] != "Doe; Jane" || attendee.params["ROLE"] != "CHAIR" || attendee.value != "mailto:jane@example.com" {
+		t.Errorf("ATTENDEE = %+v", attendee)
+	}
+
+	for _, bad := range []string{
+		"BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VCALENDAR\n",
+		"BEGIN:VCALENDAR\nSUMMARY\nEND:VCALENDAR\n",
+		"SUMMARY:Orphan\n",
+		"BEGIN:VCALENDAR\n",
+	} {
+		if _, err := parse(strings.NewReader(bad)); err == nil {
+			t.Errorf("parse(%q) error = nil, want an error", bad)
+		}
+	}
+
+	if got := splitText(`Star Party,Meetings\, Talks,Outreach`); len(got) != 3 || got[1] != "Meetings, Talks" {
+		t.Errorf("splitText() = %q", got)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		s       string
+		days    int
+		d       time.Duration
+		wantErr bool
+	}{
+		{s: "PT1H30M", d: 90 * time.Minute},
+		{s: "P1W", days: 7},
+		{s: "P1DT12H", days: 1, d: 12 * time.Hour},
+		{s: "-PT15M", d: -15 * time.Minute},
+		{s: "PT", wantErr: true},
+		{s: "P1H", wantErr: true},
+		{s: "1H", wantErr: true},
+		{s: "PT5", wantErr: true},
+	}
+	for _, tt := range tests {
+		days, d, err := parseDuration(tt.s)
+		if (err != nil) != tt.wantErr || days != tt.days || d != tt.d {
+			t.Errorf("parseDuration(%q) = %v, %v, %v", tt.s, days, d, err)
+		}
+	}
+}
+
+func TestRRule(t *testing.T) {
+	date := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		rule  string
+		start time.Time
+		end   time.Time
+		want  []time.Time
+	}{
+		{
+			rule:  "FREQ=YEARLY;BYMONTH=3;BYDAY=2SU",
+			start: date(2007, 3, 11, 2),
+			end:   date(2010, 1, 1, 0),
+			want:  []time.Time{date(2007, 3, 11, 2), date(2008, 3, 9, 2), date(2009, 3, 8, 2)},
+		},
+		{
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+			start: date(2024, 1, 26, 20),
+			end:   date(2025, 1, 1, 0),
+			want:  []time.Time{date(2024, 1, 26, 20), date(2024, 2, 23, 20), date(2024, 3, 29, 20)},
+		},
+		{
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			start: date(2024, 1, 31, 0),
+			end:   date(2024, 6, 1, 0),
+			want:  []time.Time{date(2024, 1, 31, 0), date(2024, 3, 31, 0), date(2024, 5, 31, 0)},
+		},
+		{
+			rule:  "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+			start: date(2024, 5, 31, 18),
+			end:   date(2024, 8, 1, 0),
+			want:  []time.Time{date(2024, 5, 31, 18), date(2024, 6, 28, 18), date(2024, 7, 31, 18)},
+		},
+		{
+			rule:  "FREQ=DAILY;INTERVAL=10;COUNT=3",
+			start: date(2024, 5, 1, 9),
+			end:   date(2024, 12, 1, 0),
+			want:  []time.Time{date(2024, 5, 1, 9), date(2024, 5, 11, 9), date(2024, 5, 21, 9)},
+		},
+		{
+			rule:  "FREQ=WEEKLY;BYDAY=TU,TH;UNTIL=20240514",
+			start: date(2024, 5, 7, 19),
+			end:   date(2024, 12, 1, 0),
+			want:  []time.Time{date(2024, 5, 7, 19), date(2024, 5, 9, 19), date(2024, 5, 14, 19)},
+		},
+		{
+			rule:  "FREQ=YEARLY",
+			start: date(2024, 2, 29, 0),
+			end:   date(2029, 1, 1, 0),
+			want:  []time.Time{date(2024, 2, 29, 0), date(2028, 2, 29, 0)},
+		},
+		{
+			rule:  "FREQ=YEARLY;BYYEARDAY=-1",
+			start: date(2024, 12, 31, 23),
+			end:   date(2026, 1, 1, 0),
+			want:  []time.Time{date(2024, 12, 31, 23), date(2025, 12, 31, 23)},
+		},
+		{
+			rule:  "FREQ=HOURLY;INTERVAL=6;COUNT=4",
+			start: date(2024, 5, 1, 0),
+			end:   date(2024, 6, 1, 0),
+			want:  []time.Time{date(2024, 5, 1, 0), date(2024, 5, 1, 6), date(2024, 5, 1, 12), date(2024, 5, 1, 18)},
+		},
+		{
+			rule:  "FREQ=DAILY;BYHOUR=21,23",
+			start: date(2024, 5, 1, 21),
+			end:   date(2024, 5, 2, 22),
+			want:  []time.Time{date(2024, 5, 1, 21), date(2024, 5, 1, 23), date(2024, 5, 2, 21)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := parseRRule(tt.rule)
+			if err != nil {
+				t.Fatalf("parseRRule() error = %v", err)
+			}
+			got := rule.instances(tt.start, tt.start, tt.end, locationZone{time.UTC})
+			if len(got) != len(tt.want) {
+				t.Fatalf("instances() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("instances()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	for _, bad := range []string{"FREQ=WEEKLY;BYWEEKNO=20", "FREQ=FORTNIGHTLY", "FREQ=DAILY;COUNT=2;UNTIL=20240101", "FREQ=MONTHLY;BYDAY=0MO", "FREQ=DAILY;INTERVAL=0"} {
+		if _, err := parseRRule(bad); err == nil {
+			t.Errorf("parseRRule(%q) error = nil, want an error", bad)
+		}
+	}
+}
+
+func TestCalendar_LongRunningRule(t *testing.T) {
+	// Readings every 7 minutes since 2010 number far more than maxInstances
+	// by 2024, yet those of the range are found, from the one under way at
+	// its start
+	ics := "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:sqm@example.com\r\nSUMMARY:Sky Quality Reading\r\n" +
+		"DTSTART:20100101T000000Z\r\nDURATION:PT10M\r\nRRULE:FREQ=MINUTELY;INTERVAL=7\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"
+	cal, err := readCalendar(strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("readCalendar() error = %v", err)
+	}
+
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 5, 1, 0, 5, 0, 0, time.UTC),
+		End:   time.Date(2024, 5, 1, 1, 0, 0, 0, time.UTC),
+	}
+	occurrences := cal.occurrences(timeRange, time.UTC)
+	if len(occurrences) != 10 {
+		t.Fatalf("occurrences() returned %d occurrences, want 10", len(occurrences))
+	}
+	for i, o := range occurrences {
+		want := time.Date(2024, 4, 30, 23, 56, 0, 0, time.UTC).Add(time.Duration(i) * 7 * time.Minute)
+		if !o.start.Equal(want) {
+			t.Errorf("occurrence %d at %v, want %v", i, o.start, want)
+		}
+	}
+}
+
+func TestDefinedZone(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "club.ics"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	cal, err := readCalendar(f)
+	if err != nil {
+		t.Fatalf("readCalendar() error = %v", err)
+	}
+	z := cal.zones["Eastern Standard Time"]
+	if z == nil {
+		t.Fatal("zone Eastern Standard Time not defined")
+	}
+
+	// The zone agrees with the IANA rules for New York
+	loc, _ := time.LoadLocation("America/New_York")
+	for _, wall := range []time.Time{
+		time.Date(1999, 1, 15, 12, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 10, 1, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 10, 3, 0, 0, 0, time.UTC),
+		time.Date(2024, 7, 4, 21, 0, 0, 0, time.UTC),
+		time.Date(2024, 11, 3, 3, 0, 0, 0, time.UTC),
+		time.Date(2025, 12, 31, 23, 0, 0, 0, time.UTC),
+	} {
+		want := locationZone{loc}.at(wall)
+		if got := z.at(wall); !got.Equal(want) {
+			t.Errorf("at(%v) = %v, want %v", wall, got, want)
+		}
+	}
+}
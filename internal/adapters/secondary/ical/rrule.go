@@ -0,0 +1,405 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxInstances bounds the occurrences expanded from a single rule within a
+// range, such as a SECONDLY rule over a long range
+const maxInstances = 100000
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// weekdayNum is a BYDAY entry such as "MO" or "-1FR", the last Friday of
+// the month or year. N is zero for every such weekday.
+type weekdayNum struct {
+	n   int
+	day time.Weekday
+}
+
+// rrule is a recurrence rule, such as "FREQ=MONTHLY;BYDAY=2SA;COUNT=10".
+// Occurrences are expanded in wall-clock time, so that an evening event
+// stays in the evening across daylight saving changes.
+type rrule struct {
+	freq     string
+	interval int
+	count    int
+	until    *dateTime
+	wkst     time.Weekday
+
+	byMonth    []int
+	byYearDay  []int
+	byMonthDay []int
+	byDay      []weekdayNum
+	byHour     []int
+	byMinute   []int
+	bySecond   []int
+	bySetPos   []int
+}
+
+func parseRRule(value string) (*rrule, error) {
+	r := &rrule{interval: 1, wkst: time.Monday}
+	for _, part := range strings.Split(value, ";") {
+		name, v, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("malformed rule part %q", part)
+		}
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			r.freq = strings.ToUpper(v)
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(v)
+			if err == nil && r.interval < 1 {
+				err = fmt.Errorf("INTERVAL %d is not positive", r.interval)
+			}
+		case "COUNT":
+			r.count, err = strconv.Atoi(v)
+		case "UNTIL":
+			var until dateTime
+			until, err = parseDateTime(v, len(v) == len("20060102"))
+			r.until = &until
+		case "WKST":
+			day, ok := weekdays[strings.ToUpper(v)]
+			if !ok {
+				err = fmt.Errorf("unknown weekday %q", v)
+			}
+			r.wkst = day
+		case "BYMONTH":
+			r.byMonth, err = parseInts(v, 1, 12, false)
+		case "BYYEARDAY":
+			r.byYearDay, err = parseInts(v, 1, 366, true)
+		case "BYMONTHDAY":
+			r.byMonthDay, err = parseInts(v, 1, 31, true)
+		case "BYHOUR":
+			r.byHour, err = parseInts(v, 0, 23, false)
+		case "BYMINUTE":
+			r.byMinute, err = parseInts(v, 0, 59, false)
+		case "BYSECOND":
+			r.bySecond, err = parseInts(v, 0, 60, false)
+		case "BYSETPOS":
+			r.bySetPos, err = parseInts(v, 1, 366, true)
+		case "BYDAY":
+			for _, s := range strings.Split(strings.ToUpper(v), ",") {
+				if len(s) < 2 {
+					return nil, fmt.Errorf("malformed BYDAY %q", v)
+				}
+				day, ok := weekdays[s[len(s)-2:]]
+				if !ok {
+					return nil, fmt.Errorf("malformed BYDAY %q", v)
+				}
+				n := 0
+				if len(s) > 2 {
+					n, err = strconv.Atoi(s[:len(s)-2])
+					if err != nil || n == 0 || n < -53 || n > 53 {
+						return nil, fmt.Errorf("malformed BYDAY %q", v)
+					}
+				}
+				r.byDay = append(r.byDay, weekdayNum{n: n, day: day})
+			}
+		default:
+			// BYWEEKNO and extensions would change the occurrences, so
+			// they are refused rather than ignored
+			return nil, fmt.Errorf("unsupported rule part %s", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("rule part %s: %w", name, err)
+		}
+	}
+
+	switch r.freq {
+	case "YEARLY", "MONTHLY", "WEEKLY", "DAILY", "HOURLY", "MINUTELY", "SECONDLY":
+	default:
+		return nil, fmt.Errorf("unknown FREQ %q", r.freq)
+	}
+	if r.count > 0 && r.until != nil {
+		return nil, fmt.Errorf("rule has both COUNT and UNTIL")
+	}
+	return r, nil
+}
+
+// parseInts parses a list of integers from min to max, or down to -max when
+// negative counts from the end are allowed
+func parseInts(s string, min, max int, negative bool) ([]int, error) {
+	var values []int
+	for _, field := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+		if negative && v < 0 {
+			v = -v
+			if v < min || v > max {
+				return nil, fmt.Errorf("%d out of range", -v)
+			}
+			values = append(values, -v)
+			continue
+		}
+		if v < min || v > max {
+			return nil, fmt.Errorf("%d out of range", v)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// instances returns the wall-clock times of the occurrences from from to
+// end, in order. Start is the wall-clock DTSTART, which is always the first
+// occurrence; those before from still count towards COUNT. Zone places the
+// times in time to compare them with an UNTIL given in UTC.
+func (r *rrule) instances(start, from, end time.Time, z zone) []time.Time {
+	rule := r.withDefaults(start)
+
+	var until func(wall time.Time) bool
+	switch {
+	case r.until == nil:
+		until = func(time.Time) bool { return false }
+	case r.until.utc:
+		limit := r.until.wall
+		until = func(wall time.Time) bool { return z.at(wall).After(limit) }
+	case r.until.date:
+		// A date includes the whole day
+		limit := r.until.wall.AddDate(0, 0, 1)
+		until = func(wall time.Time) bool { return !wall.Before(limit) }
+	default:
+		limit := r.until.wall
+		until = func(wall time.Time) bool { return wall.After(limit) }
+	}
+
+	var times []time.Time
+	if !start.Before(from) {
+		times = append(times, start)
+	}
+	count := 1
+	for period := rule.skipTo(start, from); !period.After(end); period = rule.next(period) {
+		for _, t := range rule.period(period) {
+			if !t.After(start) {
+				continue
+			}
+			if t.After(end) || until(t) || r.count > 0 && count >= r.count || len(times) >= maxInstances {
+				return times
+			}
+			count++
+			if !t.Before(from) {
+				times = append(times, t)
+			}
+		}
+	}
+	return times
+}
+
+// skipTo returns the start of the first period to expand for the occurrences
+// from from. Rules shorter than a day without a COUNT skip the periods
+// before it, as a MINUTELY rule begun years ago would otherwise be expanded
+// from its start; the others are expanded from DTSTART.
+func (r *rrule) skipTo(start, from time.Time) time.Time {
+	var unit time.Duration
+	switch r.freq {
+	case "HOURLY":
+		unit = time.Hour
+	case "MINUTELY":
+		unit = time.Minute
+	case "SECONDLY":
+		unit = time.Second
+	}
+	if unit == 0 || r.count > 0 || !from.After(start) {
+		return r.periodStart(start)
+	}
+	step := time.Duration(r.interval) * unit
+	return start.Add(from.Sub(start) / step * step)
+}
+
+// withDefaults returns a copy of the rule in which the parts the rule leaves
+// out are taken from DTSTART, such as the weekday of a WEEKLY rule
+func (r *rrule) withDefaults(start time.Time) *rrule {
+	rule := *r
+	noDay := len(r.byYearDay) == 0 && len(r.byMonthDay) == 0 && len(r.byDay) == 0
+	switch r.freq {
+	case "WEEKLY":
+		if len(r.byDay) == 0 {
+			rule.byDay = []weekdayNum{{day: start.Weekday()}}
+		}
+	case "MONTHLY":
+		if noDay {
+			rule.byMonthDay = []int{start.Day()}
+		}
+	case "YEARLY":
+		if noDay {
+			rule.byMonthDay = []int{start.Day()}
+			if len(r.byMonth) == 0 {
+				rule.byMonth = []int{int(start.Month())}
+			}
+		}
+	}
+	if r.freq != "HOURLY" && r.freq != "MINUTELY" && r.freq != "SECONDLY" {
+		if len(r.byHour) == 0 {
+			rule.byHour = []int{start.Hour()}
+		}
+		if len(r.byMinute) == 0 {
+			rule.byMinute = []int{start.Minute()}
+		}
+		if len(r.bySecond) == 0 {
+			rule.bySecond = []int{start.Second()}
+		}
+	}
+	return &rule
+}
+
+// periodStart returns the start of the period of FREQ holding t
+func (r *rrule) periodStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch r.freq {
+	case "YEARLY":
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	case "MONTHLY":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case "WEEKLY":
+		return day.AddDate(0, 0, -((int(t.Weekday()) - int(r.wkst) + 7) % 7))
+	case "DAILY":
+		return day
+	}
+	return t
+}
+
+// next returns the start of the period INTERVAL periods after period
+func (r *rrule) next(period time.Time) time.Time {
+	switch r.freq {
+	case "YEARLY":
+		return period.AddDate(r.interval, 0, 0)
+	case "MONTHLY":
+		return period.AddDate(0, r.interval, 0)
+	case "WEEKLY":
+		return period.AddDate(0, 0, 7*r.interval)
+	case "DAILY":
+		return period.AddDate(0, 0, r.interval)
+	case "HOURLY":
+		return period.Add(time.Duration(r.interval) * time.Hour)
+	case "MINUTELY":
+		return period.Add(time.Duration(r.interval) * time.Minute)
+	}
+	return period.Add(time.Duration(r.interval) * time.Second)
+}
+
+// period returns the occurrences of the rule in the period starting at
+// period, in order. The BY parts expand the days of YEARLY, MONTHLY and
+// WEEKLY periods and the times of day of daily and longer periods; for
+// shorter periods they only limit the occurrences.
+func (r *rrule) period(period time.Time) []time.Time {
+	var days []time.Time
+	switch r.freq {
+	case "YEARLY":
+		// Only the days of the months of BYMONTH are looked at
+		for m := time.January; m <= time.December; m++ {
+			if !contains(r.byMonth, int(m)) {
+				continue
+			}
+			for d := time.Date(period.Year(), m, 1, 0, 0, 0, 0, time.UTC); d.Month() == m; d = d.AddDate(0, 0, 1) {
+				days = append(days, d)
+			}
+		}
+	case "MONTHLY":
+		for d := period; d.Month() == period.Month(); d = d.AddDate(0, 0, 1) {
+			days = append(days, d)
+		}
+	case "WEEKLY":
+		for i := 0; i < 7; i++ {
+			days = append(days, period.AddDate(0, 0, i))
+		}
+	default:
+		days = []time.Time{period}
+	}
+
+	var times []time.Time
+	for _, d := range days {
+		if !r.matchesDay(d) {
+			continue
+		}
+		if r.freq == "HOURLY" || r.freq == "MINUTELY" || r.freq == "SECONDLY" {
+			if contains(r.byHour, d.Hour()) && contains(r.byMinute, d.Minute()) && contains(r.bySecond, d.Second()) {
+				times = append(times, d)
+			}
+			continue
+		}
+		for _, h := range r.byHour {
+			for _, m := range r.byMinute {
+				for _, s := range r.bySecond {
+					times = append(times, time.Date(d.Year(), d.Month(), d.Day(), h, m, s, 0, time.UTC))
+				}
+			}
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	if len(r.bySetPos) == 0 {
+		return times
+	}
+	var selected []time.Time
+	for i, t := range times {
+		for _, pos := range r.bySetPos {
+			if pos == i+1 || pos == i-len(times) {
+				selected = append(selected, t)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// matchesDay checks a day against BYMONTH, BYYEARDAY, BYMONTHDAY and BYDAY
+func (r *rrule) matchesDay(d time.Time) bool {
+	if len(r.byMonth) > 0 && !contains(r.byMonth, int(d.Month())) {
+		return false
+	}
+
+	yearDays := time.Date(d.Year(), 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	if len(r.byYearDay) > 0 && !contains(r.byYearDay, d.YearDay()) && !contains(r.byYearDay, d.YearDay()-yearDays-1) {
+		return false
+	}
+
+	monthDays := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if len(r.byMonthDay) > 0 && !contains(r.byMonthDay, d.Day()) && !contains(r.byMonthDay, d.Day()-monthDays-1) {
+		return false
+	}
+
+	if len(r.byDay) == 0 {
+		return true
+	}
+	for _, wd := range r.byDay {
+		if wd.day != d.Weekday() {
+			continue
+		}
+		if wd.n == 0 {
+			return true
+		}
+		// Numbered weekdays count within the month of MONTHLY rules and of
+		// YEARLY rules limited by BYMONTH, and otherwise within the year
+		index, days := d.Day(), monthDays
+		if r.freq == "YEARLY" && len(r.byMonth) == 0 {
+			index, days = d.YearDay(), yearDays
+		}
+		if wd.n > 0 && (index-1)/7+1 == wd.n || wd.n < 0 && -((days-index)/7+1) == wd.n {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether values holds v; an empty list holds every value
+func contains(values []int, v int) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package ical
+
+import (
+	"fmt"
+	"time"
+)
+
+// zone places wall-clock times, given in UTC fields, in time
+type zone interface {
+	at(wall time.Time) time.Time
+}
+
+// locationZone is a zone of the IANA database, UTC, or the observer's zone
+// for floating times
+type locationZone struct {
+	loc *time.Location
+}
+
+func (z locationZone) at(wall time.Time) time.Time {
+	return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), 0, z.loc)
+}
+
+// definedZone is a VTIMEZONE whose TZID is not in the IANA database, such as
+// the Windows names Outlook writes. Its STANDARD and DAYLIGHT observances
+// give the UTC offset in force from each of their onsets.
+type definedZone struct {
+	observances []observance
+}
+
+type observance struct {
+	// start is the wall-clock time of the first onset and rule and rdates
+	// those of the later ones, in the offset in force before them
+	start      time.Time
+	rule       *rrule
+	rdates     []time.Time
+	offsetFrom int
+	offsetTo   int
+}
+
+func parseTimeZone(c *component) (*definedZone, error) {
+	z := &definedZone{}
+	for _, sub := range c.components {
+		if sub.name != "STANDARD" && sub.name != "DAYLIGHT" {
+			continue
+		}
+		var o observance
+		p, ok := sub.get("DTSTART")
+		if !ok {
+			return nil, fmt.Errorf("%s without DTSTART", sub.name)
+		}
+		start, err := parseDateTime(p.value, false)
+		if err != nil {
+			return nil, fmt.Errorf("DTSTART: %w", err)
+		}
+		o.start = start.wall
+		for _, name := range []string{"TZOFFSETFROM", "TZOFFSETTO"} {
+			p, ok := sub.get(name)
+			if !ok {
+				return nil, fmt.Errorf("%s without %s", sub.name, name)
+			}
+			offset, err := parseOffset(p.value)
+			if err != nil {
+				return nil, err
+			}
+			if name == "TZOFFSETFROM" {
+				o.offsetFrom = offset
+			} else {
+				o.offsetTo = offset
+			}
+		}
+		if p, ok := sub.get("RRULE"); ok {
+			if o.rule, err = parseRRule(p.value); err != nil {
+				return nil, err
+			}
+		}
+		for _, p := range sub.all("RDATE") {
+			values, err := parseDateTimes(p)
+			if err != nil {
+				return nil, err
+			}
+			for _, v := range values {
+				o.rdates = append(o.rdates, v.wall)
+			}
+		}
+		z.observances = append(z.observances, o)
+	}
+	if len(z.observances) == 0 {
+		return nil, fmt.Errorf("time zone without observances")
+	}
+	return z, nil
+}
+
+// at applies the offset of the observance with the latest onset at or
+// before wall. Onsets are compared in their own wall-clock time, so times
+// within the hour of a change may take either offset.
+func (z *definedZone) at(wall time.Time) time.Time {
+	var latest time.Time
+	var offset int
+	for _, o := range z.observances {
+		onsets := []time.Time{o.start}
+		if o.rule != nil && !o.start.After(wall) {
+			// Yearly rules without a count, such as those starting in 1601
+			// that Outlook writes, are expanded from the year before wall
+			// rather than from their start
+			start, shifted := o.start, false
+			if o.rule.freq == "YEARLY" && o.rule.count == 0 {
+				if years := (wall.Year() - 1 - start.Year()) / o.rule.interval * o.rule.interval; years > 0 {
+					start, shifted = start.AddDate(years, 0, 0), true
+				}
+			}
+			onsets = o.rule.instances(start, start, wall, locationZone{time.FixedZone("", o.offsetFrom)})
+			if shifted {
+				onsets = onsets[1:]
+			}
+		}
+		onsets = append(onsets, o.rdates...)
+		for _, onset := range onsets {
+			if !onset.After(wall) && !onset.Before(latest) {
+				latest, offset = onset, o.offsetTo
+			}
+		}
+	}
+	// Before every onset, the offset the earliest observance changes from
+	// is in force
+	if latest.IsZero() {
+		earliest := z.observances[0]
+		for _, o := range z.observances {
+			if o.start.Before(earliest.start) {
+				earliest = o
+			}
+		}
+		offset = earliest.offsetFrom
+	}
+	return wall.Add(-time.Duration(offset) * time.Second).UTC()
+}
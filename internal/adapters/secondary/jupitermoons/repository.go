@@ -0,0 +1,192 @@
+package jupitermoons
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+)
+
+const sourceName = "Local Galilean Moon Predictor"
+
+// phenomena maps the astro phenomenon kinds to the domain
+var phenomena = map[astro.JovianPhenomenonKind]struct {
+	phenomenon domain.JovianPhenomenon
+	eventType  domain.EventType
+	title      string
+	action     string
+}{
+	astro.MoonTransit:     {domain.MoonTransit, domain.Transit, "%s Transits Jupiter", "%s crosses the face of Jupiter"},
+	astro.ShadowTransit:   {domain.ShadowTransit, domain.Transit, "Shadow of %s Transits Jupiter", "The shadow of %s crosses the face of Jupiter"},
+	astro.MoonEclipse:     {domain.MoonEclipse, domain.Transit, "%s Eclipsed by Jupiter", "%s passes through the shadow of Jupiter"},
+	astro.MoonOccultation: {domain.MoonOccultation, domain.Transit, "%s Occulted by Jupiter", "%s passes behind Jupiter"},
+}
+
+type jupiterMoonsRepository struct{}
+
+// NewJupiterMoonsRepository creates a repository that predicts the transits,
+// shadow transits, eclipses and occultations of the Galilean moons of
+// Jupiter. All four are TRANSIT events, the one type the service has for
+// the phenomena of the Galilean moons: ECLIPSE and OCCULTATION stand for
+// eclipses of the Sun and Moon and occultations by the Moon, whose details a
+// moon passing into Jupiter's shadow or behind its disk does not have. They
+// are told apart by their phenomenon. Like the published tables they are
+// checked against, they are reported for every site, with the visibility
+// telling whether they can be observed from the observer's.
+func NewJupiterMoonsRepository() *jupiterMoonsRepository {
+	return &jupiterMoonsRepository{}
+}
+
+func (r *jupiterMoonsRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	startJD := astro.JulianDay(timeRange.Start)
+	endJD := astro.JulianDay(timeRange.End)
+	site := astro.Site{Latitude: observer.Latitude, Longitude: observer.Longitude, Elevation: observer.Elevation}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var events []domain.Event
+	for _, p := range astro.JovianPhenomena(startJD, endJD) {
+		// Jupiter's place in the sky midway tells whether a phenomenon whose
+		// contacts cannot be watched can be seen while in progress
+		midway := contact((p.Start+p.End)/2, false, site)
+		events = append(events, phenomenonEvent(p, newDetails(p, site), midway, observer))
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	return events, nil
+}
+
+func newDetails(p astro.JovianPhenomenon, site astro.Site) *domain.GalileanMoonDetails {
+	return &domain.GalileanMoonDetails{
+		Moon:       p.Moon.String(),
+		Phenomenon: phenomena[p.Kind].phenomenon,
+		Start:      contact(p.Start, p.StartHidden, site),
+		End:        contact(p.End, p.EndHidden, site),
+	}
+}
+
+func contact(jd float64, hidden bool, site astro.Site) domain.JovianContact {
+	return domain.JovianContact{
+		Hidden:             hidden,
+		JupiterAltitudeDeg: astro.Altitude(jupiter, jd, site),
+		SunAltitudeDeg:     astro.Altitude(astro.SunApparent, jd, site),
+	}
+}
+
+func jupiter(jde float64) astro.Equatorial {
+	return astro.PlanetApparent(astro.Jupiter, jde)
+}
+
+// observable reports whether a contact can be watched: the moon is in view,
+// Jupiter is above the horizon and the Sun is below civil twilight
+func observable(c domain.JovianContact) bool {
+	return !c.Hidden && c.JupiterAltitudeDeg > 0 && c.SunAltitudeDeg < astro.CivilTwilightAltitude
+}
+
+func phenomenonEvent(p astro.JovianPhenomenon, details *domain.GalileanMoonDetails, midway domain.JovianContact, observer domain.Observer) domain.Event {
+	kind := phenomena[p.Kind]
+	start, end := astro.EventTime(p.Start), astro.EventTime(p.End)
+	jde := astro.JDE(p.Start)
+
+	return domain.Event{
+		ID:    fmt.Sprintf("jovian-%s-%s-%s", slug(string(kind.phenomenon)), slug(details.Moon), start.Format("2006-01-02")),
+		Title: fmt.Sprintf(kind.title, details.Moon),
+		Description: fmt.Sprintf(kind.action+" from %s to %s",
+			details.Moon, observer.Clock(start), observer.Clock(end)),
+		StartTime:    start,
+		EndTime:      end,
+		Type:         kind.eventType,
+		Visibility:   visibility(details, midway),
+		Location:     domain.ConstellationAt(jupiter(jde), jde).Name,
+		Source:       sourceName,
+		GalileanMoon: details,
+	}
+}
+
+// visibility describes which contacts can be watched from the site, or
+// whether the phenomenon can be seen at all
+func visibility(d *domain.GalileanMoonDetails, midway domain.JovianContact) string {
+	start, end := observable(d.Start), observable(d.End)
+	switch {
+	case start && end:
+		return "Beginning and end observable in a small telescope"
+	case start:
+		return fmt.Sprintf("Only the beginning is observable: %s", obstruction(d.End))
+	case end:
+		return fmt.Sprintf("Only the end is observable: %s", obstruction(d.Start))
+	case observable(midway):
+		return "Observable only while in progress"
+	default:
+		return fmt.Sprintf("Not observable: %s", obstruction(midway))
+	}
+}
+
+// obstruction explains why a contact cannot be watched
+func obstruction(c domain.JovianContact) string {
+	switch {
+	case c.Hidden:
+		return "the moon is hidden by Jupiter or its shadow"
+	case c.JupiterAltitudeDeg <= 0:
+		return "Jupiter is below the horizon"
+	default:
+		return "the sky is too bright"
+	}
+}
+
+// slug turns a name such as "SHADOW_TRANSIT" into "shadow-transit"
+func slug(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+}
+
+func (r *jupiterMoonsRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs end with the UTC date of the beginning
+	if !strings.HasPrefix(id, "jovian-") || len(id) < len("2006-01-02") {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", id[len(id)-len("2006-01-02"):])
+	if err != nil {
+		return nil, nil
+	}
+
+	timeRange := domain.TimeRange{Start: date, End: date.AddDate(0, 0, 1)}
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.ID == id {
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *jupiterMoonsRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []domain.Event
+	for _, event := range events {
+		if event.Type == eventType {
+			filtered = append(filtered, event)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *jupiterMoonsRepository) Name() string {
+	return sourceName
+}
@@ -0,0 +1,256 @@
+package jupitermoons
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+var (
+	newYork = domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
+	sydney  = domain.Observer{Latitude: -33.8688, Longitude: 151.2093, TimeZone: "Australia/Sydney"}
+
+	// January 24, 2015 brought a triple shadow transit of Io, Europa and
+	// Callisto
+	tripleShadowDay = domain.TimeRange{
+		Start: time.Date(2015, 1, 24, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2015, 1, 25, 0, 0, 0, 0, time.UTC),
+	}
+)
+
+func TestJupiterMoonsRepository_GetEvents(t *testing.T) {
+	// The same phenomena are reported from every site, whether or not they
+	// can be watched there
+	wantIDs := []string{
+		"jovian-shadow-transit-callisto-2015-01-24",
+		"jovian-shadow-transit-io-2015-01-24",
+		"jovian-transit-io-2015-01-24",
+		"jovian-transit-callisto-2015-01-24",
+		"jovian-shadow-transit-europa-2015-01-24",
+		"jovian-transit-europa-2015-01-24",
+	}
+	tests := []struct {
+		name     string
+		observer domain.Observer
+		// wantObservable lists the phenomena that can be watched in part
+		wantObservable []string
+	}{
+		{
+			name:           "night-time from New York",
+			observer:       newYork,
+			wantObservable: wantIDs,
+		},
+		{
+			name:     "Jupiter rising during the evening in Sydney",
+			observer: sydney,
+			wantObservable: []string{
+				"jovian-transit-callisto-2015-01-24",
+				"jovian-transit-europa-2015-01-24",
+			},
+		},
+	}
+
+	repo := NewJupiterMoonsRepository()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := repo.GetEvents(context.Background(), tripleShadowDay, tt.observer)
+			if err != nil {
+				t.Fatalf("GetEvents() error = %v", err)
+			}
+			if len(events) != len(wantIDs) {
+				t.Fatalf("GetEvents() returned %d events, want %d", len(events), len(wantIDs))
+			}
+			var observable []string
+			for i, event := range events {
+				if event.ID != wantIDs[i] {
+					t.Errorf("event %d ID = %v, want %v", i, event.ID, wantIDs[i])
+				}
+				if event.Type != domain.Transit || event.GalileanMoon == nil || !event.EndTime.After(event.StartTime) {
+					t.Errorf("event = %+v", event)
+				}
+				if !strings.HasPrefix(event.Visibility, "Not observable") {
+					observable = append(observable, event.ID)
+				}
+			}
+			if strings.Join(observable, ",") != strings.Join(tt.wantObservable, ",") {
+				t.Errorf("observable events = %v, want %v", observable, tt.wantObservable)
+			}
+		})
+	}
+}
+
+func TestJupiterMoonsRepository_EclipsesAndOccultations(t *testing.T) {
+	repo := NewJupiterMoonsRepository()
+	week := domain.TimeRange{Start: tripleShadowDay.Start, End: tripleShadowDay.Start.AddDate(0, 0, 7)}
+	events, err := repo.GetEvents(context.Background(), week, newYork)
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+
+	// Eclipses and occultations are transits too, told apart by their
+	// phenomenon rather than by the type of solar and lunar events
+	phenomena := make(map[domain.JovianPhenomenon]int)
+	for _, event := range events {
+		if event.Type != domain.Transit || event.Eclipse != nil || event.Occultation != nil {
+			t.Errorf("event %s has type %s", event.ID, event.Type)
+		}
+		phenomena[event.GalileanMoon.Phenomenon]++
+	}
+	for _, phenomenon := range []domain.JovianPhenomenon{domain.MoonTransit, domain.ShadowTransit, domain.MoonEclipse, domain.MoonOccultation} {
+		if phenomena[phenomenon] == 0 {
+			t.Errorf("no %s during the week", phenomenon)
+		}
+	}
+}
+
+func TestJupiterMoonsRepository_January2015(t *testing.T) {
+	repo := NewJupiterMoonsRepository()
+	january := domain.TimeRange{
+		Start: time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2015, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+	events, err := repo.GetEvents(context.Background(), january, newYork)
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+
+	// Each phenomenon of a moon recurs with its synodic period, which the
+	// changing distance of Jupiter and the mutual perturbations of the moons
+	// shift by up to drift, and which changes by less than five minutes
+	// from one cycle to the next. Each lasts as long as the moon takes to
+	// cross the disk or the shadow.
+	const smoothness = 5 * time.Minute
+	moons := map[string]struct {
+		period     float64 // days
+		drift      time.Duration
+		count      [2]int // each phenomenon recurs within this range in the month
+		minH, maxH float64
+	}{
+		"Io":       {1.769861, 10 * time.Minute, [2]int{17, 18}, 2.2, 2.4},
+		"Europa":   {3.554095, 15 * time.Minute, [2]int{8, 9}, 2.8, 3.0},
+		"Ganymede": {7.166389, time.Hour, [2]int{4, 5}, 3.5, 3.7},
+		"Callisto": {16.753552, 4 * time.Hour, [2]int{1, 2}, 4.7, 4.9},
+	}
+
+	starts := make(map[string][]time.Time)
+	for _, event := range events {
+		d := event.GalileanMoon
+		moon, ok := moons[d.Moon]
+		if !ok {
+			t.Fatalf("event %s of unknown moon %q", event.ID, d.Moon)
+		}
+		if h := event.EndTime.Sub(event.StartTime).Hours(); h < moon.minH || h > moon.maxH {
+			t.Errorf("%s lasts %.2f h, want %.1f to %.1f", event.ID, h, moon.minH, moon.maxH)
+		}
+		key := d.Moon + " " + string(d.Phenomenon)
+		starts[key] = append(starts[key], event.StartTime)
+
+		// Before the opposition of February 6 the shadow of Jupiter lies
+		// beside the disk: eclipses of the inner moons end, and their
+		// occultations begin, hidden behind the planet
+		if d.Moon != "Callisto" {
+			switch d.Phenomenon {
+			case domain.MoonEclipse:
+				if d.Start.Hidden || !d.End.Hidden {
+					t.Errorf("%s contacts hidden = %v, %v, want the end only", event.ID, d.Start.Hidden, d.End.Hidden)
+				}
+			case domain.MoonOccultation:
+				if !d.Start.Hidden || d.End.Hidden {
+					t.Errorf("%s contacts hidden = %v, %v, want the beginning only", event.ID, d.Start.Hidden, d.End.Hidden)
+				}
+			}
+		}
+	}
+
+	for name, moon := range moons {
+		period := time.Duration(moon.period * 24 * float64(time.Hour))
+		for _, phenomenon := range []domain.JovianPhenomenon{domain.MoonTransit, domain.ShadowTransit, domain.MoonEclipse, domain.MoonOccultation} {
+			key := name + " " + string(phenomenon)
+			times := starts[key]
+			if len(times) < moon.count[0] || len(times) > moon.count[1] {
+				t.Errorf("%d of %s, want %d to %d", len(times), key, moon.count[0], moon.count[1])
+			}
+			var last time.Duration
+			for i := 1; i < len(times); i++ {
+				gap := times[i].Sub(times[i-1])
+				cycles := (gap + period/2) / period
+				if cycles == 0 {
+					t.Fatalf("%s at %v and %v within a period", key, times[i-1], times[i])
+				}
+				perCycle := gap / cycles
+				if (perCycle-period).Abs() > moon.drift || (last != 0 && (perCycle-last).Abs() > smoothness) {
+					t.Errorf("%s at %v recurs after %v a cycle, after %v before", key, times[i], perCycle, last)
+				}
+				last = perCycle
+			}
+		}
+
+		// The shadow of each moon crosses the disk ahead of the moon itself
+		for _, transit := range starts[name+" "+string(domain.MoonTransit)] {
+			found := false
+			for _, shadow := range starts[name+" "+string(domain.ShadowTransit)] {
+				if lead := transit.Sub(shadow); lead > 0 && lead < 7*time.Hour {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("transit of %s at %v has no shadow transit ahead of it", name, transit)
+			}
+		}
+	}
+}
+
+func TestJupiterMoonsRepository_TripleShadowTransit(t *testing.T) {
+	repo := NewJupiterMoonsRepository()
+	events, err := repo.GetEventsByType(context.Background(), domain.Transit, tripleShadowDay, newYork)
+	if err != nil {
+		t.Fatalf("GetEventsByType() error = %v", err)
+	}
+
+	// Published as 06:28 to 06:52 UT, from the ingress of Europa's shadow to
+	// the egress of Io's
+	var start, end time.Time
+	for _, event := range events {
+		if event.GalileanMoon.Phenomenon != domain.ShadowTransit {
+			continue
+		}
+		if event.StartTime.After(start) {
+			start = event.StartTime
+		}
+		if end.IsZero() || event.EndTime.Before(end) {
+			end = event.EndTime
+		}
+	}
+	wantStart := time.Date(2015, 1, 24, 6, 28, 0, 0, time.UTC)
+	wantEnd := time.Date(2015, 1, 24, 6, 52, 0, 0, time.UTC)
+	if start.Sub(wantStart).Abs() > 3*time.Minute || end.Sub(wantEnd).Abs() > 3*time.Minute {
+		t.Errorf("three shadows on the disk from %v to %v, want %v to %v", start, end, wantStart, wantEnd)
+	}
+}
+
+func TestJupiterMoonsRepository_GetEventByID(t *testing.T) {
+	repo := NewJupiterMoonsRepository()
+
+	event, err := repo.GetEventByID(context.Background(), "jovian-transit-europa-2015-01-24", sydney)
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if event == nil {
+		t.Fatal("GetEventByID() = nil, want the transit of Europa")
+	}
+	details := event.GalileanMoon
+	if details.Moon != "Europa" || details.Start.JupiterAltitudeDeg > 0 || details.End.JupiterAltitudeDeg <= 0 {
+		t.Errorf("details = %+v", details)
+	}
+	if event.Visibility != "Only the end is observable: Jupiter is below the horizon" {
+		t.Errorf("Visibility = %q", event.Visibility)
+	}
+
+	event, err = repo.GetEventByID(context.Background(), "jovian-eclipse-io-2015-01-24", newYork)
+	if err != nil || event != nil {
+		t.Errorf("GetEventByID() = %v, %v, want nil", event, err)
+	}
+}
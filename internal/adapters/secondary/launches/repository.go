@@ -0,0 +1,294 @@
+package launches
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"astralis/internal/adapters/secondary/ttlcache"
+	"astralis/internal/core/domain"
+)
+
+const sourceName = "Launch Library 2"
+
+const (
+	// pageSize is the number of launches asked for in each page of the feed
+	pageSize = 100
+	// maxPages bounds the pages followed for a single range, as the public
+	// feed is rate limited
+	maxPages = 10
+	// pageTTL is how long pages of the feed are reused before they are
+	// fetched again, which also keeps within the rate limit
+	pageTTL = 10 * time.Minute
+)
+
+type launchRepository struct {
+	baseURL    string
+	httpClient *http.Client
+	pages      *ttlcache.Cache[page]
+}
+
+// NewLaunchRepository creates a repository that reports rocket launches from
+// a Launch Library 2 compatible feed, such as
+// https://ll.thespacedevs.com/2.2.0/launch/
+func NewLaunchRepository(baseURL string) *launchRepository {
+	return &launchRepository{
+		baseURL:    strings.TrimSuffix(baseURL, "/") + "/",
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		pages:      ttlcache.New[page](pageTTL),
+	}
+}
+
+// page is a page of launches, with the URL of the next one
+type page struct {
+	Count   int      `json:"count"`
+	Next    *string  `json:"next"`
+	Results []launch `json:"results"`
+}
+
+type launch struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status struct {
+		Name string `json:"name"`
+	} `json:"status"`
+	NET                   time.Time  `json:"net"`
+	WindowStart           *time.Time `json:"window_start"`
+	WindowEnd             *time.Time `json:"window_end"`
+	LaunchServiceProvider struct {
+		Name string `json:"name"`
+	} `json:"launch_service_provider"`
+	Rocket struct {
+		Configuration struct {
+			FullName string `json:"full_name"`
+		} `json:"configuration"`
+	} `json:"rocket"`
+	Mission *struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Type        string `json:"type"`
+		Orbit       *struct {
+			Name string `json:"name"`
+		} `json:"orbit"`
+	} `json:"mission"`
+	Pad struct {
+		Name      string     `json:"name"`
+		Latitude  coordinate `json:"latitude"`
+		Longitude coordinate `json:"longitude"`
+		Location  struct {
+			Name string `json:"name"`
+		} `json:"location"`
+	} `json:"pad"`
+}
+
+// coordinate is a pad latitude or longitude, which version 2.2.0 of the API
+// writes as a string and later versions as a number
+type coordinate float64
+
+func (c *coordinate) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" || s == "" {
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("coordinate %s: %w", data, err)
+	}
+	*c = coordinate(v)
+	return nil
+}
+
+func (l launch) event(observer domain.Observer) domain.Event {
+	details := &domain.LaunchDetails{
+		LaunchID:    l.ID,
+		Status:      l.Status.Name,
+		Provider:    l.LaunchServiceProvider.Name,
+		Rocket:      l.Rocket.Configuration.FullName,
+		NET:         l.NET.UTC(),
+		WindowStart: l.NET.UTC(),
+		WindowEnd:   l.NET.UTC(),
+		Pad:         l.Pad.Name,
+		PadLocation: l.Pad.Location.Name,
+		Latitude:    float64(l.Pad.Latitude),
+		Longitude:   float64(l.Pad.Longitude),
+	}
+	// Launches without a window are instantaneous at the NET
+	if l.WindowStart != nil {
+		details.WindowStart = l.WindowStart.UTC()
+	}
+	if l.WindowEnd != nil {
+		details.WindowEnd = l.WindowEnd.UTC()
+	}
+	if l.Mission != nil {
+		details.Mission = l.Mission.Name
+		details.MissionType = l.Mission.Type
+		details.MissionDescription = l.Mission.Description
+		if l.Mission.Orbit != nil {
+			details.Orbit = l.Mission.Orbit.Name
+		}
+	}
+	details.DistanceKm = observer.DistanceKm(details.Latitude, details.Longitude)
+
+	description := details.MissionDescription
+	if description == "" {
+		description = fmt.Sprintf("%s launch by %s from %s, %s", details.Rocket, details.Provider, details.Pad, details.PadLocation)
+	}
+
+	return domain.Event{
+		ID:          "launch-" + l.ID,
+		Title:       l.Name,
+		Description: description,
+		StartTime:   details.WindowStart,
+		EndTime:     details.WindowEnd,
+		Type:        domain.Launch,
+		Visibility:  fmt.Sprintf("Distance: %.0f km", details.DistanceKm),
+		Location:    details.PadLocation,
+		Source:      sourceName,
+		Launch:      details,
+	}
+}
+
+func (r *launchRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	// Launches whose window overlaps the range widened to whole hours, in
+	// order of NET. Ranges starting now ask for the same pages for an hour,
+	// so that the cached ones are reused; launches outside the range itself
+	// are left out below.
+	from := timeRange.Start.UTC().Truncate(time.Hour)
+	to := timeRange.End.UTC().Truncate(time.Hour).Add(time.Hour)
+	query := url.Values{}
+	query.Set("window_end__gte", from.Format(time.RFC3339))
+	query.Set("window_start__lte", to.Format(time.RFC3339))
+	query.Set("ordering", "net")
+	query.Set("mode", "normal")
+	query.Set("limit", strconv.Itoa(pageSize))
+	next := r.baseURL + "?" + query.Encode()
+
+	var events []domain.Event
+	for pages := 0; next != "" && pages < maxPages; pages++ {
+		p, err := r.page(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, l := range p.Results {
+			event := l.event(observer)
+			if event.EndTime.Before(timeRange.Start) || !event.StartTime.Before(timeRange.End) {
+				continue
+			}
+			events = append(events, event)
+		}
+
+		next = ""
+		if p.Next != nil {
+			next = *p.Next
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	return events, nil
+}
+
+// page returns the page of the feed at url, fetching it unless it was
+// fetched less than pageTTL ago
+func (r *launchRepository) page(ctx context.Context, url string) (page, error) {
+	if p, ok := r.pages.Get(url); ok {
+		return p, nil
+	}
+
+	var p page
+	found, err := r.fetch(ctx, url, &p)
+	if err != nil {
+		return page{}, err
+	}
+	if !found {
+		return page{}, fmt.Errorf("launch feed %s not found", r.baseURL)
+	}
+	r.pages.Put(url, p)
+	return p, nil
+}
+
+// fetch decodes the JSON document at url into v, reporting whether it was
+// found
+func (r *launchRepository) fetch(ctx context.Context, url string, v interface{}) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("fetching launches: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("launch API returned status: %s", resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return false, fmt.Errorf("decoding launches: %w", err)
+	}
+	return true, nil
+}
+
+func (r *launchRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs hold the Launch Library UUID rather than a date, as launches slip
+	launchID, ok := strings.CutPrefix(id, "launch-")
+	if !ok || !isUUID(launchID) {
+		return nil, nil
+	}
+
+	launchURL, err := url.JoinPath(r.baseURL, launchID+"/")
+	if err != nil {
+		return nil, fmt.Errorf("launch feed %s: %w", r.baseURL, err)
+	}
+
+	var l launch
+	found, err := r.fetch(ctx, launchURL, &l)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	event := l.event(observer)
+	return &event, nil
+}
+
+// isUUID checks that s looks like a UUID, so that it can be put in a URL path
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case i == 8 || i == 13 || i == 18 || i == 23:
+			if c != '-' {
+				return false
+			}
+		case !strings.ContainsRune("0123456789abcdefABCDEF", c):
+			return false
+		}
+	}
+	return true
+}
+
+func (r *launchRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	if eventType != domain.Launch {
+		return nil, nil
+	}
+	return r.GetEvents(ctx, timeRange, observer)
+}
+
+func (r *launchRepository) Name() string {
+	return sourceName
+}
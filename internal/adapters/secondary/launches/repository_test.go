@@ -0,0 +1,263 @@
+package launches
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"astralis/internal/adapters/secondary/apitest"
+	"astralis/internal/core/domain"
+)
+
+// feedPath is where the stand-in serves the feed, as the public API does
+const feedPath = "/2.2.0/launch/"
+
+// newLaunchServer stands in for a Launch Library 2 feed, serving the
+// launches recorded in testdata two to a page
+func newLaunchServer(t *testing.T) *apitest.Server {
+	var recorded []json.RawMessage
+	apitest.ReadJSON(t, "launches.json", &recorded)
+	launches := make([]launch, len(recorded))
+	for i, raw := range recorded {
+		if err := json.Unmarshal(raw, &launches[i]); err != nil {
+			t.Fatalf("launch %d of launches.json: %v", i, err)
+		}
+	}
+
+	return apitest.NewServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, feedPath) {
+			http.NotFound(w, r)
+			return
+		}
+
+		// A single launch
+		if id := strings.Trim(strings.TrimPrefix(r.URL.Path, feedPath), "/"); id != "" {
+			for i, l := range launches {
+				if l.ID == id {
+					w.Write(recorded[i])
+					return
+				}
+			}
+			http.Error(w, `{"detail":"Not found."}`, http.StatusNotFound)
+			return
+		}
+
+		query := r.URL.Query()
+		from, err1 := time.Parse(time.RFC3339, query.Get("window_end__gte"))
+		to, err2 := time.Parse(time.RFC3339, query.Get("window_start__lte"))
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		if err1 != nil || err2 != nil || query.Get("mode") != "normal" {
+			http.Error(w, `{"detail":"invalid filters"}`, http.StatusBadRequest)
+			return
+		}
+
+		var matched []json.RawMessage
+		for i, l := range launches {
+			start, end := l.NET, l.NET
+			if l.WindowStart != nil && l.WindowEnd != nil {
+				start, end = *l.WindowStart, *l.WindowEnd
+			}
+			if !end.Before(from) && !start.After(to) {
+				matched = append(matched, recorded[i])
+			}
+		}
+
+		p := struct {
+			Count   int               `json:"count"`
+			Next    *string           `json:"next"`
+			Results []json.RawMessage `json:"results"`
+		}{Count: len(matched), Results: []json.RawMessage{}}
+		for i := offset; i < len(matched) && i < offset+2; i++ {
+			p.Results = append(p.Results, matched[i])
+		}
+		if offset+2 < len(matched) {
+			query.Set("offset", strconv.Itoa(offset+2))
+			next := "http://" + r.Host + feedPath + "?" + query.Encode()
+			p.Next = &next
+		}
+		json.NewEncoder(w).Encode(p)
+	})
+}
+
+var newYork = domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
+
+var may10to13 = domain.TimeRange{
+	Start: time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC),
+	End:   time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC),
+}
+
+func TestLaunchRepository_GetEvents(t *testing.T) {
+	server := newLaunchServer(t)
+	repo := NewLaunchRepository(server.URL + strings.TrimSuffix(feedPath, "/"))
+
+	events, err := repo.GetEvents(context.Background(), may10to13, newYork)
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+
+	// The three launches come two to a page
+	if got := len(server.Requests()); got != 2 {
+		t.Errorf("sent %d requests, want 2", got)
+	}
+	wantIDs := []string{
+		"launch-3f2b1a8e-6c1d-4a57-9a0e-1d2c3b4a5f60",
+		"launch-9c8d7e6f-5a4b-4c3d-8e2f-1a0b9c8d7e6f",
+		"launch-0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d",
+	}
+	if len(events) != len(wantIDs) {
+		t.Fatalf("GetEvents() returned %d events, want %d", len(events), len(wantIDs))
+	}
+	for i, id := range wantIDs {
+		event := events[i]
+		if event.ID != id || event.Type != domain.Launch || event.Source != sourceName || event.Launch == nil || !event.IsValid() {
+			t.Errorf("event %d = %+v, want %s", i, event, id)
+		}
+	}
+
+	starlink := events[0]
+	details := starlink.Launch
+	if starlink.Title != "Falcon 9 Block 5 | Starlink Group 6-57" || starlink.Location != "Cape Canaveral SFS, FL, USA" ||
+		!starlink.StartTime.Equal(time.Date(2024, 5, 10, 18, 0, 0, 0, time.UTC)) || !starlink.EndTime.Equal(time.Date(2024, 5, 10, 22, 0, 0, 0, time.UTC)) {
+		t.Errorf("event = %+v", starlink)
+	}
+	if details.Provider != "SpaceX" || details.Rocket != "Falcon 9 Block 5" || details.Status != "Go for Launch" ||
+		details.Mission != "Starlink Group 6-57" || details.MissionType != "Communications" || details.Orbit != "Low Earth Orbit" ||
+		details.Pad != "Space Launch Complex 40" || details.Latitude != 28.56194122 || details.Longitude != -80.57735736 ||
+		!details.NET.Equal(time.Date(2024, 5, 10, 19, 30, 0, 0, time.UTC)) || starlink.Description != details.MissionDescription {
+		t.Errorf("launch = %+v", details)
+	}
+	if details.DistanceKm < 1470 || details.DistanceKm > 1485 {
+		t.Errorf("DistanceKm = %.1f, want about 1478", details.DistanceKm)
+	}
+
+	// Numeric coordinates are read too, and launches without a mission
+	// description are described by their rocket and pad
+	electron := events[1]
+	if electron.Launch.Latitude != -39.262833 || electron.Launch.Longitude != 177.864469 || electron.Launch.Mission != "" ||
+		electron.Description != "Electron launch by Rocket Lab from Rocket Lab Launch Complex 1A, Onenui Station, Mahia Peninsula, New Zealand" {
+		t.Errorf("event = %+v", electron)
+	}
+
+	// Launches without a window take place at the NET
+	longMarch := events[2]
+	net := time.Date(2024, 5, 12, 15, 20, 0, 0, time.UTC)
+	if !longMarch.StartTime.Equal(net) || !longMarch.EndTime.Equal(net) || longMarch.Launch.Orbit != "" ||
+		!strings.HasPrefix(longMarch.Description, "Long March 3B/E launch by") {
+		t.Errorf("event = %+v", longMarch)
+	}
+}
+
+func TestLaunchRepository_PageCache(t *testing.T) {
+	server := newLaunchServer(t)
+	repo := NewLaunchRepository(server.URL + feedPath)
+
+	// Pages fetched a moment ago are reused
+	first, err := repo.GetEvents(context.Background(), may10to13, newYork)
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	again, err := repo.GetEvents(context.Background(), may10to13, newYork)
+	if err != nil || len(again) != len(first) {
+		t.Errorf("GetEvents() again = %d events, %v, want %d", len(again), err, len(first))
+	}
+	if got := len(server.Requests()); got != 2 {
+		t.Errorf("sent %d requests, want the 2 pages fetched once", got)
+	}
+
+	// Ranges starting a few seconds apart, as ranges starting now do, ask
+	// for the same pages
+	later := domain.TimeRange{Start: may10to13.Start.Add(5 * time.Second), End: may10to13.End.Add(5 * time.Second)}
+	if _, err := repo.GetEvents(context.Background(), later, newYork); err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if got := len(server.Requests()); got != 2 {
+		t.Errorf("sent %d requests, want the 2 pages fetched once", got)
+	}
+
+	// Another range is another query of the feed
+	if _, err := repo.GetEvents(context.Background(), domain.TimeRange{Start: may10to13.Start, End: may10to13.End.Add(2 * time.Hour)}, newYork); err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if got := len(server.Requests()); got != 4 {
+		t.Errorf("sent %d requests, want 4", got)
+	}
+}
+
+func TestLaunchRepository_GetEventByID(t *testing.T) {
+	server := newLaunchServer(t)
+	repo := NewLaunchRepository(server.URL + feedPath)
+
+	event, err := repo.GetEventByID(context.Background(), "launch-9c8d7e6f-5a4b-4c3d-8e2f-1a0b9c8d7e6f", newYork)
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if event == nil || event.Title != "Electron | PREFIRE 2" {
+		t.Fatalf("GetEventByID() = %+v, want the Electron launch", event)
+	}
+	if got := server.Requests()[0].Path; got != feedPath+"9c8d7e6f-5a4b-4c3d-8e2f-1a0b9c8d7e6f/" {
+		t.Errorf("requested %s, want the launch", got)
+	}
+
+	event, err = repo.GetEventByID(context.Background(), "launch-00000000-0000-4000-8000-000000000000", newYork)
+	if err != nil || event != nil {
+		t.Errorf("GetEventByID() of an unknown launch = %v, %v, want nil", event, err)
+	}
+
+	// IDs of other sources and malformed IDs are not looked up
+	for _, id := range []string{"fireball-2024-05-10T13:02:51", "launch-../../admin", "launch-9c8d7e6f"} {
+		event, err := repo.GetEventByID(context.Background(), id, newYork)
+		if err != nil || event != nil {
+			t.Errorf("GetEventByID(%q) = %v, %v, want nil", id, event, err)
+		}
+	}
+	if got := len(server.Requests()); got != 2 {
+		t.Errorf("sent %d requests, want 2", got)
+	}
+}
+
+func TestLaunchRepository_GetEventsByType(t *testing.T) {
+	server := newLaunchServer(t)
+	repo := NewLaunchRepository(server.URL + feedPath)
+
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 5, 20, 13, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 5, 21, 0, 0, 0, 0, time.UTC),
+	}
+	events, err := repo.GetEventsByType(context.Background(), domain.Launch, timeRange, newYork)
+	if err != nil || len(events) != 1 || events[0].Launch.Mission != "USSF-106" {
+		t.Errorf("GetEventsByType() = %v, %v, want the launch whose window is open", events, err)
+	}
+
+	events, err = repo.GetEventsByType(context.Background(), domain.Fireball, timeRange, newYork)
+	if err != nil || events != nil {
+		t.Errorf("GetEventsByType(FIREBALL) = %v, %v, want nil", events, err)
+	}
+}
+
+func TestLaunchRepository_Error(t *testing.T) {
+	server := apitest.NewServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"detail":"Request was throttled."}`, http.StatusTooManyRequests)
+	})
+	repo := NewLaunchRepository(server.URL + feedPath)
+
+	if _, err := repo.GetEvents(context.Background(), may10to13, newYork); err == nil {
+		t.Error("GetEvents() error = nil, want an error")
+	}
+}
+
+func TestCoordinate(t *testing.T) {
+	var pad struct {
+		Latitude  coordinate `json:"latitude"`
+		Longitude coordinate `json:"longitude"`
+	}
+	if err := json.Unmarshal([]byte(`{"latitude": "28.5", "longitude": -80.5}`), &pad); err != nil || pad.Latitude != 28.5 || pad.Longitude != -80.5 {
+		t.Errorf("Unmarshal() = %+v, %v", pad, err)
+	}
+	if err := json.Unmarshal([]byte(`{"latitude": "north"}`), &pad); err == nil {
+		t.Error("Unmarshal() error = nil, want an error")
+	}
+}
@@ -0,0 +1,163 @@
+package lunar
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+)
+
+const (
+	sourceName = "Local Lunar Ephemeris"
+
+	// supermoonDistanceKm is the Earth-Moon distance below which a new or
+	// full moon is reported as a supermoon
+	supermoonDistanceKm = 360000
+)
+
+var phases = map[astro.Quarter]struct {
+	phase domain.MoonPhase
+	slug  string
+	title string
+}{
+	astro.NewMoon:      {domain.NewMoon, "new", "New Moon"},
+	astro.FirstQuarter: {domain.FirstQuarter, "first-quarter", "First Quarter Moon"},
+	astro.FullMoon:     {domain.FullMoon, "full", "Full Moon"},
+	astro.LastQuarter:  {domain.LastQuarter, "last-quarter", "Last Quarter Moon"},
+}
+
+type lunarRepository struct{}
+
+// NewLunarRepository creates a repository that computes lunar phases,
+// perigees and apogees locally
+func NewLunarRepository() *lunarRepository {
+	return &lunarRepository{}
+}
+
+func (r *lunarRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	startJD := astro.JulianDay(timeRange.Start)
+	endJD := astro.JulianDay(timeRange.End)
+
+	var events []domain.Event
+	for _, phase := range astro.MoonPhases(startJD, endJD) {
+		events = append(events, phaseEvent(phase))
+	}
+	for _, apsis := range astro.MoonApsides(startJD, endJD) {
+		events = append(events, apsisEvent(apsis))
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	return events, nil
+}
+
+func phaseEvent(phase astro.Phase) domain.Event {
+	t := astro.TimeFromJulianDay(phase.JD).Round(time.Second)
+	jde := astro.JDE(phase.JD)
+	_, dist := astro.MoonGeocentric(jde)
+	info := phases[phase.Quarter]
+
+	details := &domain.MoonDetails{
+		Phase:        info.phase,
+		Illumination: astro.MoonIllumination(jde),
+		DistanceKm:   dist,
+		Supermoon:    (info.phase == domain.NewMoon || info.phase == domain.FullMoon) && dist < supermoonDistanceKm,
+	}
+
+	title := info.title
+	if details.Supermoon {
+		title = fmt.Sprintf("%s (Supermoon)", title)
+	}
+
+	return domain.Event{
+		ID:    fmt.Sprintf("moon-%s-%s", info.slug, t.Format("2006-01-02")),
+		Title: title,
+		Description: fmt.Sprintf("%s at %s UTC, %.0f%% illuminated, %.0f km from Earth",
+			info.title, t.Format("15:04"), details.Illumination*100, dist),
+		StartTime: t,
+		EndTime:   t,
+		Type:      domain.LunarPhase,
+		Location:  domain.ConstellationAt(astro.MoonApparent(jde), jde).Name,
+		Source:    sourceName,
+		Moon:      details,
+	}
+}
+
+func apsisEvent(apsis astro.Apsis) domain.Event {
+	t := astro.TimeFromJulianDay(apsis.JD).Round(time.Second)
+	jde := astro.JDE(apsis.JD)
+
+	slug, title, eventType := "apogee", "Lunar Apogee", domain.Apogee
+	if apsis.Perigee {
+		slug, title, eventType = "perigee", "Lunar Perigee", domain.Perigee
+	}
+
+	return domain.Event{
+		ID:          fmt.Sprintf("moon-%s-%s", slug, t.Format("2006-01-02")),
+		Title:       title,
+		Description: fmt.Sprintf("The Moon is %.0f km from Earth at %s UTC", apsis.Distance, t.Format("15:04")),
+		StartTime:   t,
+		EndTime:     t,
+		Type:        eventType,
+		Location:    domain.ConstellationAt(astro.MoonApparent(jde), jde).Name,
+		Source:      sourceName,
+		Moon: &domain.MoonDetails{
+			Illumination: astro.MoonIllumination(jde),
+			DistanceKm:   apsis.Distance,
+		},
+	}
+}
+
+func (r *lunarRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	if !strings.HasPrefix(id, "moon-") {
+		return nil, nil
+	}
+
+	now := time.Now()
+	timeRange := domain.TimeRange{
+		Start: now.AddDate(0, -1, 0), // Look back 1 month
+		End:   now.AddDate(0, 1, 0),  // Look forward 1 month
+	}
+
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.ID == id {
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *lunarRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []domain.Event
+	for _, event := range events {
+		if event.Type == eventType {
+			filtered = append(filtered, event)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *lunarRepository) Name() string {
+	return sourceName
+}
@@ -0,0 +1,93 @@
+package lunar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+func TestLunarRepository_Phases(t *testing.T) {
+	repo := NewLunarRepository()
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	events, err := repo.GetEventsByType(context.Background(), domain.LunarPhase, timeRange, domain.Observer{})
+	if err != nil {
+		t.Fatalf("GetEventsByType() error = %v", err)
+	}
+
+	// Published phase times for April 2024, rounded to the minute
+	want := []struct {
+		phase         domain.MoonPhase
+		time          time.Time
+		illumination  float64
+		constellation string
+	}{
+		{domain.LastQuarter, time.Date(2024, 4, 2, 3, 15, 0, 0, time.UTC), 0.5, "Sagittarius"},
+		{domain.NewMoon, time.Date(2024, 4, 8, 18, 21, 0, 0, time.UTC), 0, "Pisces"},
+		{domain.FirstQuarter, time.Date(2024, 4, 15, 19, 13, 0, 0, time.UTC), 0.5, "Gemini"},
+		{domain.FullMoon, time.Date(2024, 4, 23, 23, 49, 0, 0, time.UTC), 1, "Virgo"},
+	}
+
+	if len(events) != len(want) {
+		t.Fatalf("GetEventsByType() got %v events, want %v", len(events), len(want))
+	}
+	for i, tt := range want {
+		event := events[i]
+		if event.Moon == nil || event.Moon.Phase != tt.phase {
+			t.Errorf("event %d phase = %v, want %v", i, event.Moon, tt.phase)
+			continue
+		}
+		if diff := event.StartTime.Sub(tt.time).Abs(); diff > time.Minute {
+			t.Errorf("%v at %v, want %v", tt.phase, event.StartTime, tt.time)
+		}
+		if diff := event.Moon.Illumination - tt.illumination; diff > 0.01 || diff < -0.01 {
+			t.Errorf("%v illumination = %v, want %v", tt.phase, event.Moon.Illumination, tt.illumination)
+		}
+		if event.Location != tt.constellation {
+			t.Errorf("%v location = %q, want %q", tt.phase, event.Location, tt.constellation)
+		}
+	}
+}
+
+func TestLunarRepository_Supermoon(t *testing.T) {
+	repo := NewLunarRepository()
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 10, 10, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 10, 24, 0, 0, 0, 0, time.UTC),
+	}
+
+	events, err := repo.GetEvents(context.Background(), timeRange, domain.Observer{})
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+
+	var perigee, full *domain.Event
+	for i, event := range events {
+		switch {
+		case event.Type == domain.Perigee:
+			perigee = &events[i]
+		case event.Moon != nil && event.Moon.Phase == domain.FullMoon:
+			full = &events[i]
+		}
+	}
+
+	// The closest perigee of 2024: October 17 at 00:51 UTC, 357,173 km
+	if perigee == nil {
+		t.Fatal("GetEvents() returned no perigee")
+	}
+	if diff := perigee.StartTime.Sub(time.Date(2024, 10, 17, 0, 51, 0, 0, time.UTC)).Abs(); diff > 15*time.Minute {
+		t.Errorf("perigee at %v, want 2024-10-17 00:51 UTC", perigee.StartTime)
+	}
+	if diff := perigee.Moon.DistanceKm - 357173; diff > 50 || diff < -50 {
+		t.Errorf("perigee distance = %v, want 357173", perigee.Moon.DistanceKm)
+	}
+
+	if full == nil || !full.Moon.Supermoon {
+		t.Errorf("full moon of 2024-10-17 = %v, want a supermoon", full)
+	}
+}
@@ -0,0 +1,224 @@
+package meteorshowers
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+)
+
+const sourceName = "IMO Meteor Shower Calendar"
+
+//go:embed showers.csv
+var showersCSV []byte
+
+// shower is one row of the embedded catalog
+type shower struct {
+	code       string
+	name       string
+	startMonth time.Month
+	startDay   int
+	endMonth   time.Month
+	endDay     int
+	// peakLongitude is the J2000 solar longitude of maximum activity
+	peakLongitude float64
+	zhr           int
+	radiantRA     float64
+	radiantDec    float64
+	velocity      float64
+	parentBody    string
+}
+
+type meteorShowerRepository struct {
+	showers []shower
+}
+
+// NewMeteorShowerRepository creates a repository backed by the embedded
+// meteor shower catalog
+func NewMeteorShowerRepository() (*meteorShowerRepository, error) {
+	showers, err := parseShowers(showersCSV)
+	if err != nil {
+		return nil, fmt.Errorf("loading meteor shower catalog: %w", err)
+	}
+	return &meteorShowerRepository{showers: showers}, nil
+}
+
+func parseShowers(data []byte) ([]shower, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 10
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	showers := make([]shower, 0, len(records))
+	for _, record := range records {
+		s := shower{code: record[0], name: record[1], parentBody: record[9]}
+		if s.startMonth, s.startDay, err = parseMonthDay(record[2]); err != nil {
+			return nil, fmt.Errorf("%s start: %w", s.code, err)
+		}
+		if s.endMonth, s.endDay, err = parseMonthDay(record[3]); err != nil {
+			return nil, fmt.Errorf("%s end: %w", s.code, err)
+		}
+		if s.zhr, err = strconv.Atoi(record[5]); err != nil {
+			return nil, fmt.Errorf("%s ZHR: %w", s.code, err)
+		}
+
+		var values [4]float64
+		for i, column := range []int{4, 6, 7, 8} {
+			if values[i], err = strconv.ParseFloat(record[column], 64); err != nil {
+				return nil, fmt.Errorf("%s column %d: %w", s.code, column, err)
+			}
+		}
+		s.peakLongitude, s.radiantRA, s.radiantDec, s.velocity = values[0], values[1], values[2], values[3]
+		showers = append(showers, s)
+	}
+	return showers, nil
+}
+
+func parseMonthDay(value string) (time.Month, int, error) {
+	t, err := time.Parse("01-02", value)
+	if err != nil {
+		return 0, 0, err
+	}
+	return t.Month(), t.Day(), nil
+}
+
+func (r *meteorShowerRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	var events []domain.Event
+
+	// Windows that cross the new year belong to the year in which they start
+	for year := timeRange.Start.UTC().Year() - 1; year <= timeRange.End.UTC().Year(); year++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, s := range r.showers {
+			event := s.event(year, observer)
+			if event.StartTime.Before(timeRange.End) && event.EndTime.After(timeRange.Start) {
+				events = append(events, event)
+			}
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	return events, nil
+}
+
+// event expands a catalog entry into the shower whose activity starts in
+// year, as seen by the observer
+func (s shower) event(year int, observer domain.Observer) domain.Event {
+	start := time.Date(year, s.startMonth, s.startDay, 0, 0, 0, 0, time.UTC)
+	end := time.Date(year, s.endMonth, s.endDay, 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	if !end.After(start) {
+		end = end.AddDate(1, 0, 0)
+	}
+	peak := s.peak(start, end)
+
+	description := fmt.Sprintf("The %s are active from %s to %s and peak on %s UTC with a ZHR of about %d",
+		s.name, start.Format("January 2"), end.AddDate(0, 0, -1).Format("January 2"),
+		peak.Format("January 2 15:04"), s.zhr)
+	if s.parentBody != "" {
+		description += fmt.Sprintf(". Parent body: %s", s.parentBody)
+	}
+
+	return domain.Event{
+		ID:          fmt.Sprintf("meteor-%s-%d", strings.ToLower(s.code), year),
+		Title:       s.name,
+		Description: description,
+		StartTime:   start,
+		EndTime:     end,
+		Type:        domain.MeteorShower,
+		Visibility:  s.visibility(observer),
+		Location:    domain.ConstellationAt(astro.Equatorial{RA: s.radiantRA, Dec: s.radiantDec}, astro.J2000).Name,
+		Source:      sourceName,
+		Shower: &domain.MeteorShowerDetails{
+			Code:        s.code,
+			Peak:        peak,
+			ZHR:         s.zhr,
+			RadiantRA:   s.radiantRA,
+			RadiantDec:  s.radiantDec,
+			VelocityKmS: s.velocity,
+			ParentBody:  s.parentBody,
+		},
+	}
+}
+
+// visibility describes how high the radiant climbs for the observer. The
+// hourly rate actually seen falls off with the sine of the radiant altitude.
+func (s shower) visibility(observer domain.Observer) string {
+	radiant := fmt.Sprintf("Radiant RA: %.1f°, Dec: %+.1f°", s.radiantRA, s.radiantDec)
+	culmination := 90 - math.Abs(observer.Latitude-s.radiantDec)
+	if culmination <= 0 {
+		return radiant + ". The radiant never rises from this latitude"
+	}
+	return fmt.Sprintf("%s. The radiant culminates %.0f° above the horizon, for up to %.0f meteors per hour",
+		radiant, culmination, float64(s.zhr)*math.Sin(culmination*math.Pi/180))
+}
+
+// peak returns the instant the Sun reaches the peak solar longitude within
+// the activity window
+func (s shower) peak(start, end time.Time) time.Time {
+	startJD, endJD := astro.JulianDay(start), astro.JulianDay(end)
+	longitude := astro.PrecessLongitude(s.peakLongitude, astro.JDE(startJD))
+
+	crossings := astro.SolarLongitudeCrossings(longitude, startJD, endJD)
+	if len(crossings) == 0 {
+		return start
+	}
+	return astro.TimeFromJulianDay(crossings[0]).Truncate(time.Minute)
+}
+
+func (r *meteorShowerRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs carry the year the activity window starts in, so the event can be
+	// rebuilt directly from the catalog
+	parts := strings.Split(id, "-")
+	if len(parts) != 3 || parts[0] != "meteor" {
+		return nil, nil
+	}
+	year, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, nil
+	}
+
+	for _, s := range r.showers {
+		if strings.ToLower(s.code) == parts[1] {
+			event := s.event(year, observer)
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *meteorShowerRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []domain.Event
+	for _, event := range events {
+		if event.Type == eventType {
+			filtered = append(filtered, event)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *meteorShowerRepository) Name() string {
+	return sourceName
+}
@@ -0,0 +1,145 @@
+package meteorshowers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+func TestMeteorShowerRepository_GetEvents(t *testing.T) {
+	repo, err := NewMeteorShowerRepository()
+	if err != nil {
+		t.Fatalf("NewMeteorShowerRepository() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		timeRange domain.TimeRange
+		wantID    string
+		// IMO predicted peak window for the year
+		peakFrom time.Time
+		peakTo   time.Time
+	}{
+		{
+			name: "Perseids active during the requested night",
+			timeRange: domain.TimeRange{
+				Start: time.Date(2024, 8, 10, 20, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 8, 11, 4, 0, 0, 0, time.UTC),
+			},
+			wantID:   "meteor-per-2024",
+			peakFrom: time.Date(2024, 8, 12, 13, 0, 0, 0, time.UTC),
+			peakTo:   time.Date(2024, 8, 12, 16, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Quadrantids window crossing the new year",
+			timeRange: domain.TimeRange{
+				Start: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+			},
+			wantID:   "meteor-qua-2023",
+			peakFrom: time.Date(2024, 1, 4, 8, 0, 0, 0, time.UTC),
+			peakTo:   time.Date(2024, 1, 4, 10, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := repo.GetEventsByType(context.Background(), domain.MeteorShower, tt.timeRange, domain.Observer{})
+			if err != nil {
+				t.Fatalf("GetEventsByType() error = %v", err)
+			}
+
+			var found *domain.Event
+			for i, event := range events {
+				if event.ID == tt.wantID {
+					found = &events[i]
+				}
+				if !event.IsValid() || event.Shower == nil {
+					t.Errorf("GetEventsByType() returned incomplete event %v", event.ID)
+				}
+			}
+			if found == nil {
+				t.Fatalf("GetEventsByType() missing %v", tt.wantID)
+			}
+
+			peak := found.Shower.Peak
+			if peak.Before(tt.peakFrom) || peak.After(tt.peakTo) {
+				t.Errorf("%v peak = %v, want between %v and %v", tt.wantID, peak, tt.peakFrom, tt.peakTo)
+			}
+			if peak.Before(found.StartTime) || peak.After(found.EndTime) {
+				t.Errorf("%v peak %v outside activity window", tt.wantID, peak)
+			}
+		})
+	}
+}
+
+func TestMeteorShowerRepository_GetEventByID(t *testing.T) {
+	repo, err := NewMeteorShowerRepository()
+	if err != nil {
+		t.Fatalf("NewMeteorShowerRepository() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		wantNil  bool
+		wantZHR  int
+		wantYear int
+	}{
+		{name: "Geminids", id: "meteor-gem-2030", wantZHR: 150, wantYear: 2030},
+		{name: "unknown shower", id: "meteor-xyz-2030", wantNil: true},
+		{name: "malformed id", id: "meteor-gem", wantNil: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := repo.GetEventByID(context.Background(), tt.id, domain.Observer{})
+			if err != nil {
+				t.Fatalf("GetEventByID() error = %v", err)
+			}
+			if tt.wantNil {
+				if event != nil {
+					t.Errorf("GetEventByID() = %v, want nil", event)
+				}
+				return
+			}
+			if event == nil {
+				t.Fatal("GetEventByID() got nil, want event")
+			}
+			if event.Shower.ZHR != tt.wantZHR || event.StartTime.Year() != tt.wantYear {
+				t.Errorf("GetEventByID() = %v, want ZHR %v in %v", event, tt.wantZHR, tt.wantYear)
+			}
+		})
+	}
+}
+
+func TestMeteorShowerRepository_Visibility(t *testing.T) {
+	repo, err := NewMeteorShowerRepository()
+	if err != nil {
+		t.Fatalf("NewMeteorShowerRepository() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		observer domain.Observer
+		want     string
+	}{
+		{name: "Ursids from Oslo", observer: domain.Observer{Latitude: 59.9}, want: "culminates 74° above the horizon"},
+		{name: "Ursids from Sydney", observer: domain.Observer{Latitude: -33.9}, want: "never rises"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := repo.GetEventByID(context.Background(), "meteor-urs-2024", tt.observer)
+			if err != nil || event == nil {
+				t.Fatalf("GetEventByID() = %v, %v", event, err)
+			}
+			if !strings.Contains(event.Visibility, tt.want) {
+				t.Errorf("Visibility = %q, want it to contain %q", event.Visibility, tt.want)
+			}
+		})
+	}
+}
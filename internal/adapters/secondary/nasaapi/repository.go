@@ -1,47 +1,499 @@
 package nasaapi
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log"
+	"math"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"astralis/internal/core/domain"
 )
 
 const (
-	nasaBaseURL = "https://api.nasa.gov"
-	cameBaseURL = "https://api.nasa.gov/DONKI"
+	nasaBaseURL  = "https://api.nasa.gov"
+	donkiBaseURL = nasaBaseURL + "/DONKI"
+	sourceName   = "NASA DONKI API"
 )
 
 type nasaAPIRepository struct {
 	apiKey     string
+	baseURL    string
 	httpClient *http.Client
 }
 
-type cmeEvent struct {
-	ActivityID     string    `json:"activityID"`
-	StartTime      time.Time `json:"startTime"`
-	Note           string    `json:"note"`
-	CatalogID      string    `json:"catalog"`
-	SourceLocation string    `json:"sourceLocation"`
-}
-
-// NewNASARepository creates a new NASA API repository
+// NewNASARepository creates a new NASA API repository that reports the
+// coronal mass ejections, solar flares, geomagnetic storms, solar energetic
+// particle events, interplanetary shocks, high-speed streams and radiation
+// belt enhancements of the DONKI space weather database
 func NewNASARepository(apiKey string) *nasaAPIRepository {
 	return &nasaAPIRepository{
 		apiKey:     apiKey,
+		baseURL:    donkiBaseURL,
 		httpClient: &http.Client{Timeout: 10 * time.Second},
 	}
 }
 
-func (r *nasaAPIRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange) ([]domain.Event, error) {
-	// Get solar events (CMEs)
-	url := fmt.Sprintf("%s/CME/?start_date=%s&end_date=%s&api_key=%s",
-		cameBaseURL,
-		timeRange.Start.Format("2006-01-02"),
-		timeRange.End.Format("2006-01-02"),
+// endpoint is a DONKI endpoint, the type of its events and the decoder of
+// its records
+type endpoint struct {
+	name      string
+	eventType domain.EventType
+	decode    func(data []byte) ([]domain.Event, error)
+}
+
+var endpoints = []endpoint{
+	{"CME", domain.CoronalMassEjection, decode[cmeEvent]},
+	{"FLR", domain.SolarFlare, decode[flareEvent]},
+	{"GST", domain.GeomagneticStorm, decode[stormEvent]},
+	{"SEP", domain.SolarEnergeticParticle, decode[sepEvent]},
+	{"IPS", domain.InterplanetaryShock, decode[shockEvent]},
+	{"HSS", domain.HighSpeedStream, decode[streamEvent]},
+	{"RBE", domain.RadiationBeltEnhancement, decode[beltEvent]},
+}
+
+// record is a DONKI record that can be turned into an event
+type record interface {
+	event() domain.Event
+}
+
+func decode[T record](data []byte) ([]domain.Event, error) {
+	var records []T
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	events := make([]domain.Event, 0, len(records))
+	for _, r := range records {
+		event := r.event()
+		event.Related = links(event.ID, event.SpaceWeather.LinkedEvents)
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// links orders the activities DONKI links to an event by the times their IDs
+// start with: the earlier ones caused it and the later ones followed from it
+func links(id string, linked []string) []domain.EventLink {
+	at, ok := activityTime(id)
+	if !ok {
+		return nil
+	}
+	var links []domain.EventLink
+	for _, other := range linked {
+		t, ok := activityTime(other)
+		if !ok || other == id {
+			continue
+		}
+		relation := domain.FollowedBy
+		if t.Before(at) {
+			relation = domain.CausedBy
+		}
+		links = append(links, domain.EventLink{ID: other, Relation: relation, Source: sourceName})
+	}
+	return links
+}
+
+// activityTime parses the time an activity ID starts with, such as
+// "2024-05-10T17:36:00" in "2024-05-10T17:36:00-GST-001"
+func activityTime(id string) (time.Time, bool) {
+	const layout = "2006-01-02T15:04:05"
+	if len(id) < len(layout) {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(layout, id[:len(layout)])
+	return t, err == nil
+}
+
+// donkiTime parses the minute resolution UTC times of DONKI, such as
+// "2024-05-10T17:36Z"; null and empty times are left zero
+type donkiTime struct {
+	time.Time
+}
+
+func (t *donkiTime) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		return nil
+	}
+	parsed, err := time.Parse("2006-01-02T15:04Z", s)
+	if err != nil {
+		if parsed, err = time.Parse(time.RFC3339, s); err != nil {
+			return fmt.Errorf("parsing time %q: %w", s, err)
+		}
+	}
+	t.Time = parsed
+	return nil
+}
+
+// activity holds the fields shared by all DONKI records
+type activity struct {
+	Instruments []struct {
+		DisplayName string `json:"displayName"`
+	} `json:"instruments"`
+	LinkedEvents []struct {
+		ActivityID string `json:"activityID"`
+	} `json:"linkedEvents"`
+	Link string `json:"link"`
+}
+
+func (a activity) details(id string) *domain.SpaceWeatherDetails {
+	details := &domain.SpaceWeatherDetails{ActivityID: id, Link: a.Link}
+	for _, i := range a.Instruments {
+		details.Instruments = append(details.Instruments, i.DisplayName)
+	}
+	for _, l := range a.LinkedEvents {
+		details.LinkedEvents = append(details.LinkedEvents, l.ActivityID)
+	}
+	return details
+}
+
+// instruments names the instruments of a record for descriptions
+func instruments(details *domain.SpaceWeatherDetails) string {
+	if len(details.Instruments) == 0 {
+		return "DONKI"
+	}
+	return strings.Join(details.Instruments, ", ")
+}
+
+type cmeEvent struct {
+	activity
+	ActivityID      string    `json:"activityID"`
+	StartTime       donkiTime `json:"startTime"`
+	Note            string    `json:"note"`
+	CatalogID       string    `json:"catalog"`
+	SourceLocation  string    `json:"sourceLocation"`
+	ActiveRegionNum int       `json:"activeRegionNum"`
+	CMEAnalyses     []struct {
+		Speed          float64 `json:"speed"`
+		HalfAngle      float64 `json:"halfAngle"`
+		IsMostAccurate bool    `json:"isMostAccurate"`
+		EnlilList      []struct {
+			ModelCompletionTime       donkiTime `json:"modelCompletionTime"`
+			EstimatedShockArrivalTime donkiTime `json:"estimatedShockArrivalTime"`
+			Kp18                      float64   `json:"kp_18"`
+			Kp90                      float64   `json:"kp_90"`
+			Kp135                     float64   `json:"kp_135"`
+			Kp180                     float64   `json:"kp_180"`
+		} `json:"enlilList"`
+	} `json:"cmeAnalyses"`
+}
+
+func (c cmeEvent) event() domain.Event {
+	details := c.details(c.ActivityID)
+	details.SourceLocation = c.SourceLocation
+	details.ActiveRegion = c.ActiveRegionNum
+	var modelled time.Time
+	for _, a := range c.CMEAnalyses {
+		if a.IsMostAccurate {
+			details.SpeedKmS, details.HalfAngleDeg = a.Speed, a.HalfAngle
+		}
+		// The latest WSA-ENLIL run that expects the CME at Earth gives the
+		// forecast
+		for _, run := range a.EnlilList {
+			if run.EstimatedShockArrivalTime.IsZero() || run.ModelCompletionTime.Before(modelled) {
+				continue
+			}
+			modelled = run.ModelCompletionTime.Time
+			arrival := run.EstimatedShockArrivalTime.Time
+			details.PredictedArrival = &arrival
+			details.PredictedKpIndex = max(run.Kp18, run.Kp90, run.Kp135, run.Kp180)
+		}
+	}
+
+	title := "Coronal Mass Ejection"
+	if c.SourceLocation != "" {
+		title += " from " + c.SourceLocation
+	}
+	description := strings.TrimRight(c.Note, ". \n")
+	if description == "" {
+		description = fmt.Sprintf("A coronal mass ejection left the Sun at %s", c.StartTime.Format("15:04 MST"))
+	}
+	if details.SpeedKmS > 0 {
+		description += fmt.Sprintf(". Most accurate analysis: %.0f km/s with a half angle of %.0f°", details.SpeedKmS, details.HalfAngleDeg)
+	}
+	if details.PredictedArrival != nil {
+		description += fmt.Sprintf(". Predicted to reach Earth at %s, with Kp up to %.0f", details.PredictedArrival.Format("2006-01-02 15:04 MST"), details.PredictedKpIndex)
+	}
+
+	return domain.Event{
+		ID:           c.ActivityID,
+		Title:        title,
+		Description:  description,
+		StartTime:    c.StartTime.Time,
+		EndTime:      c.StartTime.Add(24 * time.Hour), // Approximate duration
+		Type:         domain.CoronalMassEjection,
+		Location:     c.SourceLocation,
+		Source:       sourceName,
+		SpaceWeather: details,
+	}
+}
+
+type flareEvent struct {
+	activity
+	FlrID           string    `json:"flrID"`
+	BeginTime       donkiTime `json:"beginTime"`
+	PeakTime        donkiTime `json:"peakTime"`
+	EndTime         donkiTime `json:"endTime"`
+	ClassType       string    `json:"classType"`
+	SourceLocation  string    `json:"sourceLocation"`
+	ActiveRegionNum int       `json:"activeRegionNum"`
+}
+
+func (f flareEvent) event() domain.Event {
+	details := f.details(f.FlrID)
+	details.SourceLocation = f.SourceLocation
+	details.ActiveRegion = f.ActiveRegionNum
+	details.ClassType = f.ClassType
+	details.PeakFlux = peakFlux(f.ClassType)
+
+	description := fmt.Sprintf("A class %s solar flare", f.ClassType)
+	if f.ActiveRegionNum != 0 {
+		description += fmt.Sprintf(" from active region %d", f.ActiveRegionNum)
+	}
+	if f.SourceLocation != "" {
+		description += fmt.Sprintf(" at %s", f.SourceLocation)
+	}
+	if !f.PeakTime.IsZero() {
+		peak := f.PeakTime.Time
+		details.PeakTime = &peak
+		description += fmt.Sprintf(", peaking at %s", peak.Format("15:04 MST"))
+	}
+
+	// Flares still in progress have no end time yet
+	end := f.EndTime.Time
+	if end.IsZero() {
+		end = f.BeginTime.Time
+		if details.PeakTime != nil {
+			end = *details.PeakTime
+		}
+	}
+
+	return domain.Event{
+		ID:           f.FlrID,
+		Title:        fmt.Sprintf("%s Solar Flare", f.ClassType),
+		Description:  description,
+		StartTime:    f.BeginTime.Time,
+		EndTime:      end,
+		Type:         domain.SolarFlare,
+		Location:     f.SourceLocation,
+		Source:       sourceName,
+		SpaceWeather: details,
+	}
+}
+
+// peakFlux converts a GOES X-ray class such as "M2.5" to the peak flux in
+// W/m² it stands for, or returns 0 for a malformed class
+func peakFlux(class string) float64 {
+	if class == "" {
+		return 0
+	}
+	base := map[byte]float64{'A': 1e-8, 'B': 1e-7, 'C': 1e-6, 'M': 1e-5, 'X': 1e-4}[class[0]]
+	scale, err := strconv.ParseFloat(class[1:], 64)
+	if err != nil {
+		return 0
+	}
+	return base * scale
+}
+
+type stormEvent struct {
+	activity
+	GstID      string    `json:"gstID"`
+	StartTime  donkiTime `json:"startTime"`
+	AllKpIndex []struct {
+		ObservedTime donkiTime `json:"observedTime"`
+		KpIndex      float64   `json:"kpIndex"`
+		Source       string    `json:"source"`
+	} `json:"allKpIndex"`
+}
+
+func (s stormEvent) event() domain.Event {
+	details := s.details(s.GstID)
+	end := s.StartTime.Time
+	for _, kp := range s.AllKpIndex {
+		details.KpIndices = append(details.KpIndices, domain.KpIndex{Time: kp.ObservedTime.Time, Kp: kp.KpIndex, Source: kp.Source})
+		details.MaxKpIndex = math.Max(details.MaxKpIndex, kp.KpIndex)
+		// Each observation closes a three-hour interval
+		if kp.ObservedTime.After(end) {
+			end = kp.ObservedTime.Time
+		}
+	}
+	details.StormLevel = stormLevel(details.MaxKpIndex)
+
+	title := "Geomagnetic Storm"
+	if details.StormLevel != "" {
+		title = fmt.Sprintf("%s Geomagnetic Storm", details.StormLevel)
+	}
+
+	return domain.Event{
+		ID:    s.GstID,
+		Title: title,
+		Description: fmt.Sprintf("A geomagnetic storm began at %s and reached a planetary K index of %.2f",
+			s.StartTime.Format("15:04 MST"), details.MaxKpIndex),
+		StartTime:    s.StartTime.Time,
+		EndTime:      end,
+		Type:         domain.GeomagneticStorm,
+		Source:       sourceName,
+		SpaceWeather: details,
+	}
+}
+
+// stormLevel returns the NOAA G scale level of a storm, G1 for Kp 5 to G5
+// for Kp 9, counting a 9- as G4
+func stormLevel(kp float64) string {
+	if kp < 5 {
+		return ""
+	}
+	return fmt.Sprintf("G%d", min(int(kp)-4, 5))
+}
+
+type sepEvent struct {
+	activity
+	SepID     string    `json:"sepID"`
+	EventTime donkiTime `json:"eventTime"`
+}
+
+func (s sepEvent) event() domain.Event {
+	details := s.details(s.SepID)
+	return domain.Event{
+		ID:           s.SepID,
+		Title:        "Solar Energetic Particle Event",
+		Description:  fmt.Sprintf("Energetic particles from the Sun were detected by %s", instruments(details)),
+		StartTime:    s.EventTime.Time,
+		EndTime:      s.EventTime.Time,
+		Type:         domain.SolarEnergeticParticle,
+		Source:       sourceName,
+		SpaceWeather: details,
+	}
+}
+
+type shockEvent struct {
+	activity
+	ActivityID string    `json:"activityID"`
+	EventTime  donkiTime `json:"eventTime"`
+	Location   string    `json:"location"`
+	Catalog    string    `json:"catalog"`
+}
+
+func (s shockEvent) event() domain.Event {
+	details := s.details(s.ActivityID)
+	details.Observatory = s.Location
+	return domain.Event{
+		ID:           s.ActivityID,
+		Title:        fmt.Sprintf("Interplanetary Shock at %s", s.Location),
+		Description:  fmt.Sprintf("An interplanetary shock in the solar wind reached %s, detected by %s", s.Location, instruments(details)),
+		StartTime:    s.EventTime.Time,
+		EndTime:      s.EventTime.Time,
+		Type:         domain.InterplanetaryShock,
+		Location:     s.Location,
+		Source:       sourceName,
+		SpaceWeather: details,
+	}
+}
+
+type streamEvent struct {
+	activity
+	HssID     string    `json:"hssID"`
+	EventTime donkiTime `json:"eventTime"`
+}
+
+func (s streamEvent) event() domain.Event {
+	details := s.details(s.HssID)
+	return domain.Event{
+		ID:           s.HssID,
+		Title:        "High-Speed Solar Wind Stream",
+		Description:  fmt.Sprintf("A high-speed solar wind stream from a coronal hole arrived, detected by %s", instruments(details)),
+		StartTime:    s.EventTime.Time,
+		EndTime:      s.EventTime.Time,
+		Type:         domain.HighSpeedStream,
+		Source:       sourceName,
+		SpaceWeather: details,
+	}
+}
+
+type beltEvent struct {
+	activity
+	RbeID     string    `json:"rbeID"`
+	EventTime donkiTime `json:"eventTime"`
+}
+
+func (b beltEvent) event() domain.Event {
+	details := b.details(b.RbeID)
+	return domain.Event{
+		ID:           b.RbeID,
+		Title:        "Radiation Belt Enhancement",
+		Description:  fmt.Sprintf("The flux of energetic electrons in the outer radiation belt rose, detected by %s", instruments(details)),
+		StartTime:    b.EventTime.Time,
+		EndTime:      b.EventTime.Time,
+		Type:         domain.RadiationBeltEnhancement,
+		Source:       sourceName,
+		SpaceWeather: details,
+	}
+}
+
+func (r *nasaAPIRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	return r.events(ctx, timeRange, endpoints)
+}
+
+// events queries the given endpoints concurrently and returns their events
+// that overlap the time range. Endpoints that fail are logged and left out,
+// so that one unavailable endpoint does not hide the others; an error is
+// only returned when all of them fail.
+func (r *nasaAPIRepository) events(ctx context.Context, timeRange domain.TimeRange, endpoints []endpoint) ([]domain.Event, error) {
+	results := make([][]domain.Event, len(endpoints))
+	errs := make([]error, len(endpoints))
+
+	var wg sync.WaitGroup
+	for i, e := range endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = r.fetch(ctx, e, timeRange)
+		}()
+	}
+	wg.Wait()
+
+	var events []domain.Event
+	failed := 0
+	for i, e := range endpoints {
+		if errs[i] != nil {
+			log.Printf("%s: skipping %s events: %s", sourceName, e.name, errs[i])
+			failed++
+			continue
+		}
+		for _, event := range results[i] {
+			if event.StartTime.After(timeRange.End) || event.EndTime.Before(timeRange.Start) {
+				continue
+			}
+			events = append(events, event)
+		}
+	}
+	if failed == len(endpoints) {
+		return nil, errs[0]
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	return events, nil
+}
+
+func (r *nasaAPIRepository) fetch(ctx context.Context, e endpoint, timeRange domain.TimeRange) ([]domain.Event, error) {
+	url := fmt.Sprintf("%s/%s?startDate=%s&endDate=%s&api_key=%s",
+		r.baseURL,
+		e.name,
+		timeRange.Start.UTC().Format("2006-01-02"),
+		timeRange.End.UTC().Format("2006-01-02"),
 		r.apiKey,
 	)
 
@@ -52,7 +504,7 @@ func (r *nasaAPIRepository) GetEvents(ctx context.Context, timeRange domain.Time
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("fetching CME events: %w", err)
+		return nil, fmt.Errorf("fetching %s events: %w", e.name, err)
 	}
 	defer resp.Body.Close()
 
@@ -60,54 +512,45 @@ func (r *nasaAPIRepository) GetEvents(ctx context.Context, timeRange domain.Time
 		return nil, fmt.Errorf("NASA API returned status: %s", resp.Status)
 	}
 
-	var rawEvents []map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&rawEvents); err != nil {
-		return nil, fmt.Errorf("decoding CME events: %w", err)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s events: %w", e.name, err)
 	}
-	var cmeEvents []cmeEvent
-	for _, raw := range rawEvents {
-		var event cmeEvent
-		event.ActivityID = raw["activityID"].(string)
-		event.Note = raw["note"].(string)
-		event.CatalogID = raw["catalog"].(string)
-		event.SourceLocation = raw["sourceLocation"].(string)
-
-		startTime, err := time.Parse("2006-01-02T15:04Z", raw["startTime"].(string))
-		if err != nil {
-			return nil, fmt.Errorf("parsing start time: %w", err)
-		}
-		event.StartTime = startTime
-		cmeEvents = append(cmeEvents, event)
+	// DONKI answers with an empty body when nothing was recorded
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
 	}
 
-	var events []domain.Event
-	for _, cme := range cmeEvents {
-		event := domain.Event{
-			ID:          cme.ActivityID,
-			Title:       fmt.Sprintf("Solar CME Event - %s", cme.SourceLocation),
-			Description: cme.Note,
-			StartTime:   cme.StartTime,
-			EndTime:     cme.StartTime.Add(24 * time.Hour), // Approximate duration
-			Type:        domain.Other,
-			Location:    cme.SourceLocation,
-			Source:      "NASA DONKI API",
-		}
-		events = append(events, event)
+	events, err := e.decode(body)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s events: %w", e.name, err)
 	}
-
 	return events, nil
 }
 
-func (r *nasaAPIRepository) GetEventByID(ctx context.Context, id string) (*domain.Event, error) {
-	// For NASA API, we'll need to fetch all events and filter by ID
-	// This is not efficient but NASA's free API doesn't provide a direct lookup
-	now := time.Now()
-	timeRange := domain.TimeRange{
-		Start: now.AddDate(0, -1, 0), // Look back 1 month
-		End:   now.AddDate(0, 1, 0),  // Look forward 1 month
+func (r *nasaAPIRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// Activity IDs start with the time of the event and name the endpoint,
+	// such as "2024-05-10T17:36:00-GST-001"
+	if len(id) < len("2006-01-02") {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", id[:len("2006-01-02")])
+	if err != nil {
+		return nil, nil
+	}
+
+	var matching []endpoint
+	for _, e := range endpoints {
+		if strings.Contains(id, "-"+e.name+"-") {
+			matching = append(matching, e)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, nil
 	}
 
-	events, err := r.GetEvents(ctx, timeRange)
+	timeRange := domain.TimeRange{Start: date, End: date.AddDate(0, 0, 1)}
+	events, err := r.events(ctx, timeRange, matching)
 	if err != nil {
 		return nil, err
 	}
@@ -121,22 +564,16 @@ func (r *nasaAPIRepository) GetEventByID(ctx context.Context, id string) (*domai
 	return nil, nil
 }
 
-func (r *nasaAPIRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange) ([]domain.Event, error) {
-	events, err := r.GetEvents(ctx, timeRange)
-	if err != nil {
-		return nil, err
-	}
-
-	var filtered []domain.Event
-	for _, event := range events {
-		if event.Type == eventType {
-			filtered = append(filtered, event)
+func (r *nasaAPIRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	// Each endpoint yields a single type, so only its endpoint is queried
+	for _, e := range endpoints {
+		if e.eventType == eventType {
+			return r.events(ctx, timeRange, []endpoint{e})
 		}
 	}
-
-	return filtered, nil
+	return nil, nil
 }
 
 func (r *nasaAPIRepository) Name() string {
-	return "NASA API"
+	return sourceName
 }
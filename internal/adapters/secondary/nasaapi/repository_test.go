@@ -0,0 +1,240 @@
+package nasaapi
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"astralis/internal/adapters/secondary/apitest"
+	"astralis/internal/core/domain"
+)
+
+// newDONKIServer stands in for DONKI, serving the responses recorded in
+// testdata
+func newDONKIServer(t *testing.T) *apitest.Server {
+	return apitest.NewServer(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("api_key") != "test-key" || query.Get("startDate") == "" || query.Get("endDate") == "" {
+			http.Error(w, "missing parameters", http.StatusBadRequest)
+			return
+		}
+		data, err := os.ReadFile(filepath.Join("testdata", path.Base(r.URL.Path)+".json"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	})
+}
+
+// queried lists the DONKI endpoints queried so far, such as "FLR"
+func queried(server *apitest.Server) []string {
+	var names []string
+	for _, u := range server.Requests() {
+		names = append(names, path.Base(u.Path))
+	}
+	return names
+}
+
+func newTestRepository(url string) *nasaAPIRepository {
+	repo := NewNASARepository("test-key")
+	repo.baseURL = url
+	return repo
+}
+
+var may2024Storm = domain.TimeRange{
+	Start: time.Date(2024, 5, 9, 0, 0, 0, 0, time.UTC),
+	End:   time.Date(2024, 5, 12, 0, 0, 0, 0, time.UTC),
+}
+
+func TestNASARepository_GetEvents(t *testing.T) {
+	server := newDONKIServer(t)
+	repo := newTestRepository(server.URL)
+
+	events, err := repo.GetEvents(context.Background(), may2024Storm, domain.Observer{})
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+
+	want := []struct {
+		id        string
+		eventType domain.EventType
+	}{
+		{"2024-05-09T08:45:00-FLR-001", domain.SolarFlare},
+		{"2024-05-09T09:24:00-CME-001", domain.CoronalMassEjection},
+		{"2024-05-10T06:27:00-FLR-001", domain.SolarFlare},
+		{"2024-05-10T15:00:00-GST-001", domain.GeomagneticStorm},
+		{"2024-05-10T16:36:00-IPS-001", domain.InterplanetaryShock},
+		{"2024-05-11T01:10:00-FLR-001", domain.SolarFlare},
+		{"2024-05-11T02:10:00-SEP-001", domain.SolarEnergeticParticle},
+		{"2024-05-11T17:55:00-RBE-001", domain.RadiationBeltEnhancement},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("GetEvents() returned %d events, want %d", len(events), len(want))
+	}
+	for i, tt := range want {
+		event := events[i]
+		if event.ID != tt.id || event.Type != tt.eventType || event.SpaceWeather == nil || event.SpaceWeather.ActivityID != tt.id {
+			t.Errorf("event %d = %s %s, want %s %s", i, event.ID, event.Type, tt.id, tt.eventType)
+		}
+		if !event.IsValid() || event.Source != sourceName || event.EndTime.Before(event.StartTime) {
+			t.Errorf("event %s = %+v", event.ID, event)
+		}
+	}
+
+	cme := events[1].SpaceWeather
+	if cme.SpeedKmS != 1024 || cme.HalfAngleDeg != 60 || cme.ActiveRegion != 13664 || len(cme.LinkedEvents) != 3 {
+		t.Errorf("CME details = %+v", cme)
+	}
+	wantArrival := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	if cme.PredictedArrival == nil || !cme.PredictedArrival.Equal(wantArrival) || cme.PredictedKpIndex != 9 {
+		t.Errorf("CME forecast = %v with Kp %v, want %v with Kp 9", cme.PredictedArrival, cme.PredictedKpIndex, wantArrival)
+	}
+	if events[1].Location != "S13W08" {
+		t.Errorf("CME location = %q, want S13W08", events[1].Location)
+	}
+
+	// The flare launched the CME, which drove the shock and the storm
+	wantLinks := []domain.EventLink{
+		{ID: "2024-05-09T08:45:00-FLR-001", Relation: domain.CausedBy, Source: sourceName},
+		{ID: "2024-05-10T16:36:00-IPS-001", Relation: domain.FollowedBy, Source: sourceName},
+		{ID: "2024-05-10T15:00:00-GST-001", Relation: domain.FollowedBy, Source: sourceName},
+	}
+	if !slices.Equal(events[1].Related, wantLinks) {
+		t.Errorf("CME related = %v, want %v", events[1].Related, wantLinks)
+	}
+	if links := events[0].Related; len(links) != 1 || links[0] != (domain.EventLink{ID: "2024-05-09T09:24:00-CME-001", Relation: domain.FollowedBy, Source: sourceName}) {
+		t.Errorf("flare related = %v, want the CME", links)
+	}
+	if links := events[2].Related; links != nil {
+		t.Errorf("unlinked flare related = %v, want none", links)
+	}
+}
+
+func TestNASARepository_Details(t *testing.T) {
+	server := newDONKIServer(t)
+	repo := newTestRepository(server.URL)
+
+	flares, err := repo.GetEventsByType(context.Background(), domain.SolarFlare, may2024Storm, domain.Observer{})
+	if err != nil {
+		t.Fatalf("GetEventsByType() error = %v", err)
+	}
+	if len(flares) != 3 {
+		t.Fatalf("GetEventsByType() returned %d flares, want 3", len(flares))
+	}
+	flare := flares[1]
+	details := flare.SpaceWeather
+	wantPeak := time.Date(2024, 5, 10, 6, 54, 0, 0, time.UTC)
+	if details.ClassType != "X3.9" || details.PeakTime == nil || !details.PeakTime.Equal(wantPeak) ||
+		details.PeakFlux < 3.89e-4 || details.PeakFlux > 3.91e-4 {
+		t.Errorf("flare details = %+v", details)
+	}
+	if flare.Title != "X3.9 Solar Flare" || !flare.EndTime.Equal(time.Date(2024, 5, 10, 7, 6, 0, 0, time.UTC)) {
+		t.Errorf("flare = %+v", flare)
+	}
+	// A flare without an end time ends at its peak
+	if ongoing := flares[2]; !ongoing.EndTime.Equal(*ongoing.SpaceWeather.PeakTime) {
+		t.Errorf("ongoing flare ends at %v, want its peak", ongoing.EndTime)
+	}
+
+	storms, err := repo.GetEventsByType(context.Background(), domain.GeomagneticStorm, may2024Storm, domain.Observer{})
+	if err != nil {
+		t.Fatalf("GetEventsByType() error = %v", err)
+	}
+	if len(storms) != 1 {
+		t.Fatalf("GetEventsByType() returned %d storms, want 1", len(storms))
+	}
+	storm := storms[0]
+	if storm.SpaceWeather.MaxKpIndex != 9 || storm.SpaceWeather.StormLevel != "G5" || len(storm.SpaceWeather.KpIndices) != 5 {
+		t.Errorf("storm details = %+v", storm.SpaceWeather)
+	}
+	if storm.Title != "G5 Geomagnetic Storm" || !storm.EndTime.Equal(time.Date(2024, 5, 11, 6, 0, 0, 0, time.UTC)) {
+		t.Errorf("storm = %+v", storm)
+	}
+
+	// Only the endpoints of the requested types are queried
+	if got := queried(server); !slices.Equal(got, []string{"FLR", "GST"}) {
+		t.Errorf("requested endpoints = %v, want FLR and GST", got)
+	}
+}
+
+func TestNASARepository_GetEventByID(t *testing.T) {
+	server := newDONKIServer(t)
+	repo := newTestRepository(server.URL)
+
+	event, err := repo.GetEventByID(context.Background(), "2024-05-10T16:36:00-IPS-001", domain.Observer{})
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if event == nil || event.Type != domain.InterplanetaryShock || event.SpaceWeather.Observatory != "Earth" {
+		t.Fatalf("GetEventByID() = %+v, want the shock at Earth", event)
+	}
+	if got := queried(server); !slices.Equal(got, []string{"IPS"}) {
+		t.Errorf("requested endpoints = %v, want IPS", got)
+	}
+
+	for _, id := range []string{"2024-05-12T16:36:00-IPS-001", "2024-05-10T16:36:00-XYZ-001", "sunrise-2024-05-10"} {
+		event, err := repo.GetEventByID(context.Background(), id, domain.Observer{})
+		if err != nil || event != nil {
+			t.Errorf("GetEventByID(%q) = %v, %v, want nil", id, event, err)
+		}
+	}
+}
+
+func TestNASARepository_Errors(t *testing.T) {
+	donki := newDONKIServer(t)
+	server := apitest.NewServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if path.Base(r.URL.Path) == "RBE" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		donki.Config.Handler.ServeHTTP(w, r)
+	})
+	repo := newTestRepository(server.URL)
+
+	// The other endpoints are still reported while one is unavailable
+	events, err := repo.GetEvents(context.Background(), may2024Storm, domain.Observer{})
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if len(events) != 7 {
+		t.Errorf("GetEvents() returned %d events, want all but the belt enhancement", len(events))
+	}
+	for _, event := range events {
+		if event.Type == domain.RadiationBeltEnhancement {
+			t.Errorf("GetEvents() returned %s from the failed endpoint", event.ID)
+		}
+	}
+
+	if _, err := repo.GetEventsByType(context.Background(), domain.RadiationBeltEnhancement, may2024Storm, domain.Observer{}); err == nil {
+		t.Error("GetEventsByType() error = nil, want the RBE failure")
+	}
+	if _, err := repo.GetEventByID(context.Background(), "2024-05-11T17:55:00-RBE-001", domain.Observer{}); err == nil {
+		t.Error("GetEventByID() error = nil, want the RBE failure")
+	}
+}
+
+func TestStormLevel(t *testing.T) {
+	tests := []struct {
+		kp   float64
+		want string
+	}{
+		{4.67, ""},
+		{5, "G1"},
+		{6.33, "G2"},
+		{7, "G3"},
+		{8.67, "G4"},
+		{9, "G5"},
+	}
+
+	for _, tt := range tests {
+		if got := stormLevel(tt.kp); got != tt.want {
+			t.Errorf("stormLevel(%v) = %q, want %q", tt.kp, got, tt.want)
+		}
+	}
+}
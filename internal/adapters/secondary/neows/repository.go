@@ -0,0 +1,234 @@
+package neows
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+const (
+	neowsBaseURL = "https://api.nasa.gov/neo/rest/v1"
+	sourceName   = "NASA NeoWs API"
+)
+
+// The feed returns at most seven days of close approaches, both dates
+// included. Each page counts against the hourly quota of the API key shared
+// with DONKI; the REST API refuses ranges longer than 366 days, which take
+// 53 pages at most.
+const feedDays = 7
+
+type neowsRepository struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewNeoWsRepository creates a repository that reports the close approaches
+// of asteroids to the Earth from the NASA Near Earth Object Web Service
+func NewNeoWsRepository(apiKey string) *neowsRepository {
+	return &neowsRepository{
+		apiKey:     apiKey,
+		baseURL:    neowsBaseURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// feed is a page of the NeoWs feed, with the objects approaching the Earth
+// on each date
+type feed struct {
+	NearEarthObjects map[string][]nearEarthObject `json:"near_earth_objects"`
+}
+
+type nearEarthObject struct {
+	ReferenceID       string  `json:"neo_reference_id"`
+	Name              string  `json:"name"`
+	JPLURL            string  `json:"nasa_jpl_url"`
+	AbsoluteMagnitude float64 `json:"absolute_magnitude_h"`
+	EstimatedDiameter struct {
+		Meters struct {
+			Min float64 `json:"estimated_diameter_min"`
+			Max float64 `json:"estimated_diameter_max"`
+		} `json:"meters"`
+	} `json:"estimated_diameter"`
+	PotentiallyHazardous bool `json:"is_potentially_hazardous_asteroid"`
+	SentryObject         bool `json:"is_sentry_object"`
+	CloseApproaches      []struct {
+		// Epoch is the instant of closest approach in milliseconds since
+		// the Unix epoch
+		Epoch            int64 `json:"epoch_date_close_approach"`
+		RelativeVelocity struct {
+			KmS float64 `json:"kilometers_per_second,string"`
+		} `json:"relative_velocity"`
+		MissDistance struct {
+			AU    float64 `json:"astronomical,string"`
+			Lunar float64 `json:"lunar,string"`
+			Km    float64 `json:"kilometers,string"`
+		} `json:"miss_distance"`
+		OrbitingBody string `json:"orbiting_body"`
+	} `json:"close_approach_data"`
+}
+
+func (o nearEarthObject) events() []domain.Event {
+	// Names are the number and provisional designation, or the designation
+	// alone in parentheses
+	name := strings.TrimSpace(o.Name)
+	if strings.HasPrefix(name, "(") && strings.HasSuffix(name, ")") {
+		name = name[1 : len(name)-1]
+	}
+
+	var events []domain.Event
+	for _, ca := range o.CloseApproaches {
+		if ca.OrbitingBody != "Earth" {
+			continue
+		}
+		at := time.UnixMilli(ca.Epoch).UTC()
+		details := &domain.CloseApproachDetails{
+			ReferenceID:          o.ReferenceID,
+			Name:                 name,
+			AbsoluteMagnitude:    o.AbsoluteMagnitude,
+			DiameterMinM:         o.EstimatedDiameter.Meters.Min,
+			DiameterMaxM:         o.EstimatedDiameter.Meters.Max,
+			PotentiallyHazardous: o.PotentiallyHazardous,
+			SentryObject:         o.SentryObject,
+			MissDistanceKm:       ca.MissDistance.Km,
+			MissDistanceLunar:    ca.MissDistance.Lunar,
+			MissDistanceAU:       ca.MissDistance.AU,
+			RelativeVelocityKmS:  ca.RelativeVelocity.KmS,
+			Link:                 o.JPLURL,
+		}
+
+		description := fmt.Sprintf("Asteroid %s passes %.0f km from the Earth, %.1f times the distance of the Moon, at %.1f km/s. It is estimated at %.0f to %.0f m across",
+			name, details.MissDistanceKm, details.MissDistanceLunar, details.RelativeVelocityKmS, details.DiameterMinM, details.DiameterMaxM)
+		if details.PotentiallyHazardous {
+			description += " and is classed as potentially hazardous"
+		}
+
+		events = append(events, domain.Event{
+			ID:            fmt.Sprintf("neo-%s-%s", o.ReferenceID, at.Format("2006-01-02")),
+			Title:         fmt.Sprintf("%s Close Approach", name),
+			Description:   description,
+			StartTime:     at,
+			EndTime:       at,
+			Type:          domain.CloseApproach,
+			Source:        sourceName,
+			CloseApproach: details,
+		})
+	}
+	return events
+}
+
+func (r *neowsRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	first := date(timeRange.Start)
+	last := date(timeRange.End.Add(-time.Nanosecond))
+
+	// Longer ranges are fetched a page of feedDays at a time
+	var events []domain.Event
+	for start := first; !start.After(last); start = start.AddDate(0, 0, feedDays) {
+		end := start.AddDate(0, 0, feedDays-1)
+		if end.After(last) {
+			end = last
+		}
+		page, err := r.fetch(ctx, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range page {
+			if event.StartTime.Before(timeRange.Start) || !event.StartTime.Before(timeRange.End) {
+				continue
+			}
+			events = append(events, event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	return events, nil
+}
+
+// date returns the UTC date of an instant
+func date(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// fetch returns the close approaches of a page of the feed, from start to end
+// included
+func (r *neowsRepository) fetch(ctx context.Context, start, end time.Time) ([]domain.Event, error) {
+	url := fmt.Sprintf("%s/feed?start_date=%s&end_date=%s&api_key=%s",
+		r.baseURL,
+		start.Format("2006-01-02"),
+		end.Format("2006-01-02"),
+		r.apiKey,
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching close approaches: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("NeoWs API returned status: %s", resp.Status)
+	}
+
+	var f feed
+	if err := json.NewDecoder(resp.Body).Decode(&f); err != nil {
+		return nil, fmt.Errorf("decoding close approaches: %w", err)
+	}
+
+	var events []domain.Event
+	for _, objects := range f.NearEarthObjects {
+		for _, o := range objects {
+			events = append(events, o.events()...)
+		}
+	}
+	return events, nil
+}
+
+func (r *neowsRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs end with the date of the approach
+	if !strings.HasPrefix(id, "neo-") || len(id) < len("2006-01-02") {
+		return nil, nil
+	}
+	day, err := time.Parse("2006-01-02", id[len(id)-len("2006-01-02"):])
+	if err != nil {
+		return nil, nil
+	}
+
+	timeRange := domain.TimeRange{Start: day, End: day.AddDate(0, 0, 1)}
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.ID == id {
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *neowsRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	if eventType != domain.CloseApproach {
+		return nil, nil
+	}
+	return r.GetEvents(ctx, timeRange, observer)
+}
+
+func (r *neowsRepository) Name() string {
+	return sourceName
+}
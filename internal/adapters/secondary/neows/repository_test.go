@@ -0,0 +1,213 @@
+package neows
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"astralis/internal/adapters/secondary/apitest"
+	"astralis/internal/core/domain"
+)
+
+// newFeedServer stands in for NeoWs, serving the dates of the requested
+// window from the feed recorded in testdata. NeoWs refuses windows of more
+// than seven days.
+func newFeedServer(t *testing.T) *apitest.Server {
+	var recorded struct {
+		NearEarthObjects map[string]json.RawMessage `json:"near_earth_objects"`
+	}
+	apitest.ReadJSON(t, "feed.json", &recorded)
+
+	return apitest.NewServer(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		start, err1 := time.Parse("2006-01-02", query.Get("start_date"))
+		end, err2 := time.Parse("2006-01-02", query.Get("end_date"))
+		if r.URL.Path != "/feed" || query.Get("api_key") != "test-key" || err1 != nil || err2 != nil {
+			http.Error(w, "missing parameters", http.StatusBadRequest)
+			return
+		}
+		if end.Before(start) || end.Sub(start) > 7*24*time.Hour {
+			http.Error(w, "Date Format Exception - Expected format (yyyy-mm-dd) - The Feed date limit is only 7 Days", http.StatusBadRequest)
+			return
+		}
+
+		objects := map[string]json.RawMessage{}
+		for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			if o, ok := recorded.NearEarthObjects[day.Format("2006-01-02")]; ok {
+				objects[day.Format("2006-01-02")] = o
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"near_earth_objects": objects})
+	})
+}
+
+// windows lists the windows of the feed requested from the server, such as
+// "2024-05-01_2024-05-07"
+func windows(server *apitest.Server) []string {
+	var windows []string
+	for _, u := range server.Requests() {
+		windows = append(windows, u.Query().Get("start_date")+"_"+u.Query().Get("end_date"))
+	}
+	return windows
+}
+
+func newTestRepository(url string) *neowsRepository {
+	repo := NewNeoWsRepository("test-key")
+	repo.baseURL = url
+	return repo
+}
+
+func TestNeoWsRepository_GetEvents(t *testing.T) {
+	server := newFeedServer(t)
+	repo := newTestRepository(server.URL)
+
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 5, 16, 12, 0, 0, 0, time.UTC),
+	}
+	events, err := repo.GetEvents(context.Background(), timeRange, domain.Observer{})
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+
+	// The range is paged through in windows of seven days
+	wantWindows := []string{"2024-05-01_2024-05-07", "2024-05-08_2024-05-14", "2024-05-15_2024-05-16"}
+	if got := windows(server); !slices.Equal(got, wantWindows) {
+		t.Errorf("requested windows = %v, want %v", got, wantWindows)
+	}
+
+	// 2024 KA passes after the end of the range
+	wantIDs := []string{"neo-54450981-2024-05-03", "neo-2415029-2024-05-05", "neo-54451230-2024-05-09", "neo-54451377-2024-05-15"}
+	if len(events) != len(wantIDs) {
+		t.Fatalf("GetEvents() returned %d events, want %d", len(events), len(wantIDs))
+	}
+	for i, id := range wantIDs {
+		event := events[i]
+		if event.ID != id || event.Type != domain.CloseApproach || event.CloseApproach == nil {
+			t.Errorf("event %d = %s %s, want %s", i, event.ID, event.Type, id)
+		}
+		if !event.IsValid() || event.Source != sourceName {
+			t.Errorf("event %s = %+v", event.ID, event)
+		}
+	}
+
+	hazardous := events[1]
+	details := hazardous.CloseApproach
+	if hazardous.Title != "415029 (2011 UL21) Close Approach" || !hazardous.StartTime.Equal(time.Date(2024, 5, 5, 3, 12, 0, 0, time.UTC)) {
+		t.Errorf("event = %+v", hazardous)
+	}
+	if details.ReferenceID != "2415029" || !details.PotentiallyHazardous || details.SentryObject ||
+		details.MissDistanceAU != 0.044 || details.MissDistanceKm < 6582306 || details.MissDistanceKm > 6582307 ||
+		details.MissDistanceLunar < 17.12 || details.MissDistanceLunar > 17.13 || details.RelativeVelocityKmS != 26.39 ||
+		details.DiameterMinM != 1905.7 || details.DiameterMaxM != 4261.3 || details.AbsoluteMagnitude != 15.84 {
+		t.Errorf("details = %+v", details)
+	}
+
+	// Designations alone lose their parentheses
+	if small := events[2]; small.CloseApproach.Name != "2024 JX" || small.Title != "2024 JX Close Approach" || !small.CloseApproach.SentryObject {
+		t.Errorf("event = %+v", small)
+	}
+}
+
+func TestNeoWsRepository_GetEventByID(t *testing.T) {
+	server := newFeedServer(t)
+	repo := newTestRepository(server.URL)
+
+	event, err := repo.GetEventByID(context.Background(), "neo-54451377-2024-05-15", domain.Observer{})
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if got := windows(server); !slices.Equal(got, []string{"2024-05-15_2024-05-15"}) {
+		t.Errorf("requested windows = %v, want 2024-05-15 alone", got)
+	}
+	if event == nil || event.CloseApproach.Name != "2024 JY" {
+		t.Errorf("GetEventByID() = %+v, want 2024 JY", event)
+	}
+
+	for _, id := range []string{"neo-54451377-2024-05-16", "neo-54451377", "sunrise-2024-05-15"} {
+		event, err := repo.GetEventByID(context.Background(), id, domain.Observer{})
+		if err != nil || event != nil {
+			t.Errorf("GetEventByID(%q) = %v, %v, want nil", id, event, err)
+		}
+	}
+}
+
+func TestNeoWsRepository_GetEventsByType(t *testing.T) {
+	server := newFeedServer(t)
+	repo := newTestRepository(server.URL)
+
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC),
+	}
+	events, err := repo.GetEventsByType(context.Background(), domain.CloseApproach, timeRange, domain.Observer{})
+	if err != nil || len(events) != 1 || events[0].ID != "neo-54451230-2024-05-09" {
+		t.Errorf("GetEventsByType() = %v, %v, want 2024 JX", events, err)
+	}
+
+	events, err = repo.GetEventsByType(context.Background(), domain.Eclipse, timeRange, domain.Observer{})
+	if err != nil || events != nil {
+		t.Errorf("GetEventsByType(ECLIPSE) = %v, %v, want nil", events, err)
+	}
+	if got := windows(server); len(got) != 1 {
+		t.Errorf("requested windows = %v, want one", got)
+	}
+}
+
+func TestNeoWsRepository_Errors(t *testing.T) {
+	server := apitest.NewServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	})
+
+	repo := newTestRepository(server.URL)
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+	}
+	if _, err := repo.GetEvents(context.Background(), timeRange, domain.Observer{}); err == nil {
+		t.Error("GetEvents() error = nil, want the rate limit")
+	}
+}
+
+func TestNeoWsRepository_LongRange(t *testing.T) {
+	server := newFeedServer(t)
+	repo := newTestRepository(server.URL)
+
+	// The longest range the REST API accepts is paged through as a whole,
+	// a week at a time without gaps
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	events, err := repo.GetEvents(context.Background(), timeRange, domain.Observer{})
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if len(events) == 0 {
+		t.Error("GetEvents() returned no events, want those of the recorded feed")
+	}
+
+	got := windows(server)
+	if len(got) != 53 {
+		t.Fatalf("requested %d windows, want 53", len(got))
+	}
+	next := timeRange.Start
+	for _, window := range got {
+		start, end, _ := strings.Cut(window, "_")
+		if start != next.Format("2006-01-02") {
+			t.Errorf("window %s starts after a gap, want it to start on %s", window, next.Format("2006-01-02"))
+		}
+		last, err := time.Parse("2006-01-02", end)
+		if err != nil {
+			t.Fatalf("window %s: %v", window, err)
+		}
+		next = last.AddDate(0, 0, 1)
+	}
+	if !next.Equal(timeRange.End) {
+		t.Errorf("windows end on %s, want %s", next.AddDate(0, 0, -1).Format("2006-01-02"), timeRange.End.AddDate(0, 0, -1).Format("2006-01-02"))
+	}
+}
@@ -0,0 +1,277 @@
+package occultations
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+)
+
+const sourceName = "Local Lunar Occultation Predictor"
+
+//go:embed stars.csv
+var starsCSV []byte
+
+// star is one row of the embedded catalog
+type star struct {
+	name        string
+	designation string
+	// position is the J2000 position at epoch J2000
+	position astro.Equatorial
+	// pmRA and pmDec are the proper motions in degrees a year, pmRA along
+	// the great circle
+	pmRA, pmDec float64
+	magnitude   float64
+}
+
+// target is a star or planet that the Moon may occult
+type target struct {
+	name        string
+	designation string
+	planet      bool
+	position    astro.PositionFunc
+	magnitude   func(jde float64) float64
+}
+
+type occultationRepository struct {
+	stars          []star
+	magnitudeLimit float64
+}
+
+// NewOccultationRepository creates a repository that predicts occultations
+// by the Moon of the planets and of the stars of the embedded catalog
+// brighter than magnitudeLimit
+func NewOccultationRepository(magnitudeLimit float64) (*occultationRepository, error) {
+	stars, err := parseStars(starsCSV)
+	if err != nil {
+		return nil, fmt.Errorf("loading star catalog: %w", err)
+	}
+	return &occultationRepository{stars: stars, magnitudeLimit: magnitudeLimit}, nil
+}
+
+func parseStars(data []byte) ([]star, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 7
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	stars := make([]star, 0, len(records))
+	for _, record := range records {
+		var values [5]float64
+		for i := range values {
+			if values[i], err = strconv.ParseFloat(record[i+2], 64); err != nil {
+				return nil, fmt.Errorf("%s column %d: %w", record[0], i+2, err)
+			}
+		}
+		stars = append(stars, star{
+			name:        record[0],
+			designation: record[1],
+			position:    astro.Equatorial{RA: values[0], Dec: values[1]},
+			pmRA:        values[2] / 3.6e6,
+			pmDec:       values[3] / 3.6e6,
+			magnitude:   values[4],
+		})
+	}
+	return stars, nil
+}
+
+// targets returns the planets and the catalog stars bright enough to report
+func (r *occultationRepository) targets() []target {
+	var targets []target
+	for _, p := range astro.Planets {
+		p := p
+		targets = append(targets, target{
+			name:      p.String(),
+			planet:    true,
+			position:  func(jde float64) astro.Equatorial { return astro.PlanetApparent(p, jde) },
+			magnitude: func(jde float64) float64 { return astro.PlanetAppearanceAt(p, jde).Magnitude },
+		})
+	}
+	for _, s := range r.stars {
+		if s.magnitude > r.magnitudeLimit {
+			continue
+		}
+		s := s
+		targets = append(targets, target{
+			name:        s.name,
+			designation: s.designation,
+			position: func(jde float64) astro.Equatorial {
+				// Proper motion over a few decades is small enough to apply
+				// linearly
+				years := (jde - astro.J2000) / 365.25
+				pos := astro.Equatorial{
+					RA:  s.position.RA + s.pmRA*years/math.Cos(s.position.Dec*math.Pi/180),
+					Dec: s.position.Dec + s.pmDec*years,
+				}
+				return astro.ApparentPlace(pos, jde)
+			},
+			magnitude: func(float64) float64 { return s.magnitude },
+		})
+	}
+	return targets
+}
+
+func (r *occultationRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	startJD := astro.JulianDay(timeRange.Start)
+	endJD := astro.JulianDay(timeRange.End)
+	site := astro.Site{Latitude: observer.Latitude, Longitude: observer.Longitude, Elevation: observer.Elevation}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	targets := r.targets()
+	positions := make([]astro.PositionFunc, len(targets))
+	for i, t := range targets {
+		positions[i] = t.position
+	}
+
+	var events []domain.Event
+	for _, o := range astro.Occultations(positions, site, startJD, endJD) {
+		// Occultations that happen entirely with the Moon below the horizon
+		// cannot be observed
+		if o.DisappearanceAltitude < 0 && o.ReappearanceAltitude < 0 {
+			continue
+		}
+		events = append(events, occultationEvent(targets[o.Target], o, site, observer))
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	return events, nil
+}
+
+func occultationEvent(t target, o astro.Occultation, site astro.Site, observer domain.Observer) domain.Event {
+	jde := astro.JDE(o.Disappearance)
+	details := &domain.OccultationDetails{
+		Body:        t.name,
+		Designation: t.designation,
+		Planet:      t.planet,
+		Magnitude:   t.magnitude(jde),
+		Disappearance: domain.OccultationContact{
+			Time:             astro.EventTime(o.Disappearance),
+			Limb:             limb(o.DisappearanceLimb),
+			PositionAngleDeg: o.DisappearanceAngle,
+			MoonAltitudeDeg:  o.DisappearanceAltitude,
+		},
+		Reappearance: domain.OccultationContact{
+			Time:             astro.EventTime(o.Reappearance),
+			Limb:             limb(o.ReappearanceLimb),
+			PositionAngleDeg: o.ReappearanceAngle,
+			MoonAltitudeDeg:  o.ReappearanceAltitude,
+		},
+		MoonIllumination: astro.MoonIllumination(jde),
+		SunAltitudeDeg:   astro.Altitude(astro.SunApparent, o.Disappearance, site),
+	}
+
+	return domain.Event{
+		ID:    fmt.Sprintf("occultation-%s-%s", slug(t.name), details.Disappearance.Time.Format("2006-01-02")),
+		Title: fmt.Sprintf("Lunar Occultation of %s", t.name),
+		Description: fmt.Sprintf("%s (magnitude %.1f) disappears at the Moon's %s limb at %s and reappears at the %s limb at %s. The Moon is %.0f%% illuminated",
+			t.name, details.Magnitude,
+			strings.ToLower(string(details.Disappearance.Limb)), observer.ClockSeconds(details.Disappearance.Time),
+			strings.ToLower(string(details.Reappearance.Limb)), observer.ClockSeconds(details.Reappearance.Time),
+			details.MoonIllumination*100),
+		StartTime:   details.Disappearance.Time,
+		EndTime:     details.Reappearance.Time,
+		Type:        domain.Occultation,
+		Visibility:  visibility(details),
+		Location:    domain.ConstellationAt(t.position(jde), jde).Name,
+		Source:      sourceName,
+		Occultation: details,
+	}
+}
+
+// visibility describes which contacts can be seen and how dark the sky is
+func visibility(d *domain.OccultationDetails) string {
+	var text string
+	switch {
+	case d.Disappearance.MoonAltitudeDeg < 0:
+		text = "The Moon rises during the occultation; only the reappearance is visible"
+	case d.Reappearance.MoonAltitudeDeg < 0:
+		text = "The Moon sets during the occultation; only the disappearance is visible"
+	default:
+		text = "Both contacts are visible"
+	}
+
+	switch {
+	case d.SunAltitudeDeg > 0:
+		text += ", in daylight"
+	case d.SunAltitudeDeg > astro.NauticalTwilightAltitude:
+		text += ", in twilight"
+	default:
+		text += ", in a dark sky"
+	}
+	return text
+}
+
+func limb(l astro.Limb) domain.Limb {
+	if l == astro.BrightLimb {
+		return domain.BrightLimb
+	}
+	return domain.DarkLimb
+}
+
+// slug turns a name such as "Theta2 Tauri" into "theta2-tauri"
+func slug(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), "-"))
+}
+
+func (r *occultationRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs end with the date of disappearance
+	if !strings.HasPrefix(id, "occultation-") || len(id) < len("2006-01-02") {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", id[len(id)-len("2006-01-02"):])
+	if err != nil {
+		return nil, nil
+	}
+
+	timeRange := domain.TimeRange{Start: date, End: date.AddDate(0, 0, 1)}
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.ID == id {
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *occultationRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []domain.Event
+	for _, event := range events {
+		if event.Type == eventType {
+			filtered = append(filtered, event)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *occultationRepository) Name() string {
+	return sourceName
+}
@@ -0,0 +1,115 @@
+package occultations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+var (
+	newYork    = domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
+	losAngeles = domain.Observer{Latitude: 34.0522, Longitude: -118.2437, TimeZone: "America/Los_Angeles"}
+)
+
+func TestOccultationRepository_GetEvents(t *testing.T) {
+	december2022 := domain.TimeRange{
+		Start: time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name           string
+		observer       domain.Observer
+		magnitudeLimit float64
+		wantIDs        []string
+	}{
+		{
+			name:           "Mars near opposition from Los Angeles",
+			observer:       losAngeles,
+			magnitudeLimit: 4,
+			wantIDs:        []string{"occultation-mars-2022-12-08"},
+		},
+		{
+			name:           "Eta Leonis from New York, where Mars is missed",
+			observer:       newYork,
+			magnitudeLimit: 4,
+			wantIDs:        []string{"occultation-eta-leonis-2022-12-14"},
+		},
+		{
+			name:           "faint stars filtered by the magnitude limit",
+			observer:       newYork,
+			magnitudeLimit: 2,
+			wantIDs:        nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewOccultationRepository(tt.magnitudeLimit)
+			if err != nil {
+				t.Fatalf("NewOccultationRepository() error = %v", err)
+			}
+			events, err := repo.GetEvents(context.Background(), december2022, tt.observer)
+			if err != nil {
+				t.Fatalf("GetEvents() error = %v", err)
+			}
+			if len(events) != len(tt.wantIDs) {
+				t.Fatalf("GetEvents() returned %d events, want %d", len(events), len(tt.wantIDs))
+			}
+			for i, event := range events {
+				if event.ID != tt.wantIDs[i] {
+					t.Errorf("event %d ID = %v, want %v", i, event.ID, tt.wantIDs[i])
+				}
+				if event.Type != domain.Occultation || event.StartTime != event.Occultation.Disappearance.Time ||
+					event.EndTime != event.Occultation.Reappearance.Time {
+					t.Errorf("event = %+v", event)
+				}
+			}
+		})
+	}
+}
+
+func TestOccultationRepository_Contacts(t *testing.T) {
+	repo, err := NewOccultationRepository(4)
+	if err != nil {
+		t.Fatalf("NewOccultationRepository() error = %v", err)
+	}
+
+	event, err := repo.GetEventByID(context.Background(), "occultation-eta-leonis-2022-12-14", newYork)
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if event == nil {
+		t.Fatal("GetEventByID() = nil, want the occultation of Eta Leonis")
+	}
+
+	// The waning Moon leads with its bright limb
+	details := event.Occultation
+	if details.Disappearance.Limb != domain.BrightLimb || details.Reappearance.Limb != domain.DarkLimb {
+		t.Errorf("limbs = %v, %v, want bright then dark", details.Disappearance.Limb, details.Reappearance.Limb)
+	}
+	wantD := time.Date(2022, 12, 14, 3, 8, 0, 0, time.UTC)
+	if d := details.Disappearance.Time.Sub(wantD).Abs(); d > 2*time.Minute {
+		t.Errorf("disappearance = %v, want %v", details.Disappearance.Time, wantD)
+	}
+	if details.Planet || details.Designation != "Eta Leonis" || details.Magnitude != 3.48 {
+		t.Errorf("details = %+v", details)
+	}
+
+	event, err = repo.GetEventByID(context.Background(), "occultation-regulus-2022-12-14", newYork)
+	if err != nil || event != nil {
+		t.Errorf("GetEventByID() = %v, %v, want nil", event, err)
+	}
+}
+
+func TestParseStars(t *testing.T) {
+	stars, err := parseStars(starsCSV)
+	if err != nil {
+		t.Fatalf("parseStars() error = %v", err)
+	}
+	if len(stars) < 50 || stars[0].name != "Aldebaran" {
+		t.Errorf("parseStars() = %d stars starting with %v", len(stars), stars[0].name)
+	}
+}
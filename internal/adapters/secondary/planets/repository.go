@@ -0,0 +1,168 @@
+package planets
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+)
+
+const sourceName = "Local Planet Phenomena"
+
+type planetRepository struct{}
+
+// NewPlanetRepository creates a repository that computes oppositions,
+// greatest elongations and stationary points of the planets
+func NewPlanetRepository() *planetRepository {
+	return &planetRepository{}
+}
+
+func (r *planetRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	startJD := astro.JulianDay(timeRange.Start)
+	endJD := astro.JulianDay(timeRange.End)
+
+	var events []domain.Event
+	for _, p := range astro.Planets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, ph := range astro.PlanetPhenomena(p, startJD, endJD) {
+			events = append(events, newEvent(ph))
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	return events, nil
+}
+
+func newEvent(ph astro.PlanetPhenomenon) domain.Event {
+	at := astro.TimeFromJulianDay(ph.JD).Round(time.Second)
+	jde := astro.JDE(ph.JD)
+	look := astro.PlanetAppearanceAt(ph.Planet, jde)
+	name := ph.Planet.String()
+
+	details := &domain.PlanetDetails{
+		Name:            name,
+		Magnitude:       look.Magnitude,
+		AngularDiameter: look.AngularDiameter,
+		ElongationDeg:   look.Elongation,
+		DistanceAU:      look.Distance,
+	}
+
+	var eventType domain.EventType
+	var title, prefix string
+	switch ph.Kind {
+	case astro.Opposition:
+		eventType, prefix = domain.Opposition, "opposition"
+		title = fmt.Sprintf("%s at Opposition", name)
+	case astro.GreatestElongationEast, astro.GreatestElongationWest:
+		eventType, prefix = domain.Elongation, "elongation"
+		details.Direction, title = domain.East, fmt.Sprintf("%s at Greatest Eastern Elongation", name)
+		if ph.Kind == astro.GreatestElongationWest {
+			details.Direction, title = domain.West, fmt.Sprintf("%s at Greatest Western Elongation", name)
+		}
+	default:
+		eventType, prefix = domain.Station, "station"
+		details.Direction = domain.Retrograde
+		if ph.Kind == astro.StationDirect {
+			details.Direction = domain.Direct
+		}
+		title = fmt.Sprintf("%s Stationary", name)
+	}
+
+	return domain.Event{
+		ID:          fmt.Sprintf("%s-%s-%s", prefix, strings.ToLower(name), at.Format("2006-01-02")),
+		Title:       title,
+		Description: describe(ph.Kind, name, look),
+		StartTime:   at,
+		EndTime:     at,
+		Type:        eventType,
+		Visibility:  visibility(ph.Kind, look),
+		Location:    domain.ConstellationAt(astro.PlanetApparent(ph.Planet, jde), jde).Name,
+		Source:      sourceName,
+		Planet:      details,
+	}
+}
+
+func describe(kind astro.PlanetPhenomenonKind, name string, look astro.PlanetAppearance) string {
+	appearance := fmt.Sprintf("magnitude %.1f, %.1f\" across", look.Magnitude, look.AngularDiameter)
+	switch kind {
+	case astro.Opposition:
+		return fmt.Sprintf("%s is opposite the Sun and visible all night (%s)", name, appearance)
+	case astro.GreatestElongationEast:
+		return fmt.Sprintf("%s is %.1f° east of the Sun in the evening sky (%s)", name, look.Elongation, appearance)
+	case astro.GreatestElongationWest:
+		return fmt.Sprintf("%s is %.1f° west of the Sun in the morning sky (%s)", name, look.Elongation, appearance)
+	case astro.StationRetrograde:
+		return fmt.Sprintf("%s stops and begins its retrograde motion (%s)", name, appearance)
+	default:
+		return fmt.Sprintf("%s stops and resumes its direct motion (%s)", name, appearance)
+	}
+}
+
+func visibility(kind astro.PlanetPhenomenonKind, look astro.PlanetAppearance) string {
+	switch {
+	case kind == astro.Opposition:
+		return "Visible all night"
+	case kind == astro.GreatestElongationEast:
+		return "Evening sky, after sunset"
+	case kind == astro.GreatestElongationWest:
+		return "Morning sky, before sunrise"
+	case look.Elongation < 15:
+		return "Too close to the Sun to observe"
+	default:
+		return fmt.Sprintf("Elongation from the Sun: %.1f°", look.Elongation)
+	}
+}
+
+func (r *planetRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs start with the kind of event and end with its date
+	ours := strings.HasPrefix(id, "opposition-") || strings.HasPrefix(id, "elongation-") || strings.HasPrefix(id, "station-")
+	if !ours || len(id) < len("2006-01-02") {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", id[len(id)-len("2006-01-02"):])
+	if err != nil {
+		return nil, nil
+	}
+
+	timeRange := domain.TimeRange{Start: date, End: date.AddDate(0, 0, 1)}
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.ID == id {
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *planetRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []domain.Event
+	for _, event := range events {
+		if event.Type == eventType {
+			filtered = append(filtered, event)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *planetRepository) Name() string {
+	return sourceName
+}
@@ -0,0 +1,126 @@
+package planets
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+func TestPlanetRepository_GetEventsByType(t *testing.T) {
+	tests := []struct {
+		name          string
+		eventType     domain.EventType
+		timeRange     domain.TimeRange
+		wantID        string
+		wantTime      time.Time
+		wantDirection domain.PlanetDirection
+		wantMagnitude float64
+		wantDiameter  float64
+	}{
+		{
+			name:      "Mars opposition 2020",
+			eventType: domain.Opposition,
+			timeRange: domain.TimeRange{
+				Start: time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantID:        "opposition-mars-2020-10-13",
+			wantTime:      time.Date(2020, 10, 13, 23, 20, 0, 0, time.UTC),
+			wantMagnitude: -2.6,
+			wantDiameter:  22.3,
+		},
+		{
+			name:      "Saturn opposition 2023",
+			eventType: domain.Opposition,
+			timeRange: domain.TimeRange{
+				Start: time.Date(2023, 8, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantID:        "opposition-saturn-2023-08-27",
+			wantTime:      time.Date(2023, 8, 27, 8, 0, 0, 0, time.UTC),
+			wantMagnitude: 0.4,
+			wantDiameter:  18.9,
+		},
+		{
+			name:      "Venus greatest eastern elongation 2023",
+			eventType: domain.Elongation,
+			timeRange: domain.TimeRange{
+				Start: time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC),
+			},
+			wantID:        "elongation-venus-2023-06-04",
+			wantTime:      time.Date(2023, 6, 4, 11, 0, 0, 0, time.UTC),
+			wantDirection: domain.East,
+			wantMagnitude: -4.3,
+			wantDiameter:  23.7,
+		},
+		{
+			name:      "Mars stationary retrograde 2022",
+			eventType: domain.Station,
+			timeRange: domain.TimeRange{
+				Start: time.Date(2022, 10, 15, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2022, 11, 15, 0, 0, 0, 0, time.UTC),
+			},
+			wantID:        "station-mars-2022-10-30",
+			wantTime:      time.Date(2022, 10, 30, 13, 0, 0, 0, time.UTC),
+			wantDirection: domain.Retrograde,
+			wantMagnitude: -1.2,
+			wantDiameter:  14.9,
+		},
+	}
+
+	repo := NewPlanetRepository()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := repo.GetEventsByType(context.Background(), tt.eventType, tt.timeRange, domain.Observer{})
+			if err != nil {
+				t.Fatalf("GetEventsByType() error = %v", err)
+			}
+
+			var found *domain.Event
+			for i, event := range events {
+				if event.Type != tt.eventType {
+					t.Errorf("GetEventsByType() returned %v event %v", event.Type, event.ID)
+				}
+				if event.ID == tt.wantID {
+					found = &events[i]
+				}
+			}
+			if found == nil {
+				t.Fatalf("GetEventsByType() missing %v in %v", tt.wantID, events)
+			}
+
+			if diff := found.StartTime.Sub(tt.wantTime).Abs(); diff > 3*time.Hour {
+				t.Errorf("StartTime = %v, want %v", found.StartTime, tt.wantTime)
+			}
+			if found.Planet.Direction != tt.wantDirection {
+				t.Errorf("Direction = %v, want %v", found.Planet.Direction, tt.wantDirection)
+			}
+			if diff := found.Planet.Magnitude - tt.wantMagnitude; diff > 0.1 || diff < -0.1 {
+				t.Errorf("Magnitude = %v, want %v", found.Planet.Magnitude, tt.wantMagnitude)
+			}
+			if diff := found.Planet.AngularDiameter - tt.wantDiameter; diff > 0.3 || diff < -0.3 {
+				t.Errorf("AngularDiameter = %v, want %v", found.Planet.AngularDiameter, tt.wantDiameter)
+			}
+		})
+	}
+}
+
+func TestPlanetRepository_GetEventByID(t *testing.T) {
+	repo := NewPlanetRepository()
+
+	event, err := repo.GetEventByID(context.Background(), "opposition-jupiter-2023-11-03", domain.Observer{})
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if event == nil || event.Planet.Name != "Jupiter" {
+		t.Errorf("GetEventByID() = %v, want the Jupiter opposition", event)
+	}
+
+	event, err = repo.GetEventByID(context.Background(), "opposition-venus-2023-11-03", domain.Observer{})
+	if err != nil || event != nil {
+		t.Errorf("GetEventByID() = %v, %v, want nil", event, err)
+	}
+}
@@ -0,0 +1,255 @@
+package satellites
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+)
+
+const sourceName = "Local Satellite Pass Predictor"
+
+// maxElementAge bounds how far from the element set epoch passes are
+// predicted; SGP4 errors for low orbits grow to kilometres within days
+const maxElementAge = 14 * 24 * time.Hour
+
+// elementFileExtensions are the files read from a TLE directory
+var elementFileExtensions = map[string]bool{".tle": true, ".txt": true, ".3le": true}
+
+type satelliteRepository struct {
+	path         string
+	minElevation float64
+}
+
+// NewSatelliteRepository creates a repository that predicts satellite
+// passes from the two-line element sets in a file, or in the .tle, .txt and
+// .3le files of a directory. Files are read again on every request, so they
+// can be refreshed while the server runs. Passes culminating below
+// minElevation degrees are skipped.
+func NewSatelliteRepository(path string, minElevation float64) (*satelliteRepository, error) {
+	r := &satelliteRepository{path: path, minElevation: minElevation}
+	if _, err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// load reads the element sets, keeping the most recent one of each satellite
+func (r *satelliteRepository) load() ([]astro.TLE, error) {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return nil, fmt.Errorf("loading element sets: %w", err)
+	}
+
+	files := []string{r.path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(r.path)
+		if err != nil {
+			return nil, fmt.Errorf("loading element sets: %w", err)
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && elementFileExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+				files = append(files, filepath.Join(r.path, entry.Name()))
+			}
+		}
+	}
+
+	latest := make(map[int]astro.TLE)
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("loading element sets: %w", err)
+		}
+		tles, err := astro.ParseTLEs(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", file, err)
+		}
+		for _, tle := range tles {
+			if prev, ok := latest[tle.CatalogNumber]; !ok || tle.EpochJD > prev.EpochJD {
+				latest[tle.CatalogNumber] = tle
+			}
+		}
+	}
+
+	tles := make([]astro.TLE, 0, len(latest))
+	for _, tle := range latest {
+		tles = append(tles, tle)
+	}
+	sort.Slice(tles, func(i, j int) bool { return tles[i].CatalogNumber < tles[j].CatalogNumber })
+	return tles, nil
+}
+
+func (r *satelliteRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	tles, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var events []domain.Event
+	for _, tle := range tles {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		passes, err := r.passes(tle, timeRange, observer)
+		if err != nil {
+			// A satellite that decays within the range is skipped
+			continue
+		}
+		events = append(events, passes...)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	return events, nil
+}
+
+// passes predicts the passes of one satellite within the range, limited to
+// the span over which its element set is trusted
+func (r *satelliteRepository) passes(tle astro.TLE, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	epoch := astro.TimeFromJulianDay(tle.EpochJD)
+	start := maxTime(timeRange.Start, epoch.Add(-maxElementAge))
+	end := minTime(timeRange.End, epoch.Add(maxElementAge))
+	if !start.Before(end) {
+		return nil, nil
+	}
+
+	sat, err := astro.NewSatellite(tle)
+	if err != nil {
+		return nil, err
+	}
+	site := astro.Site{Latitude: observer.Latitude, Longitude: observer.Longitude, Elevation: observer.Elevation}
+	passes, err := sat.Passes(site, astro.JulianDay(start), astro.JulianDay(end), r.minElevation)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]domain.Event, 0, len(passes))
+	for _, p := range passes {
+		events = append(events, passEvent(tle, p, observer))
+	}
+	return events, nil
+}
+
+func passEvent(tle astro.TLE, p astro.SatellitePass, observer domain.Observer) domain.Event {
+	details := &domain.SatellitePassDetails{
+		Name:               tle.Name,
+		CatalogNumber:      tle.CatalogNumber,
+		Rise:               astro.EventTime(p.Rise),
+		Culmination:        astro.EventTime(p.Culmination),
+		Set:                astro.EventTime(p.Set),
+		RiseAzimuth:        p.RisePosition.Azimuth,
+		SetAzimuth:         p.SetPosition.Azimuth,
+		MaxElevation:       p.CulminationPosition.Altitude,
+		CulminationAzimuth: p.CulminationPosition.Azimuth,
+		Visible:            p.Visible,
+		ElementsEpoch:      astro.TimeFromJulianDay(tle.EpochJD).Round(time.Second),
+	}
+
+	visibility := "Not visible: the satellite is in the Earth's shadow or the sky is too bright"
+	if p.Visible {
+		visibility = "Visible to the naked eye: the satellite is sunlit while the sky is dark"
+	}
+
+	return domain.Event{
+		ID:    fmt.Sprintf("satellite-%d-%s", tle.CatalogNumber, details.Rise.Format("20060102T1504Z")),
+		Title: fmt.Sprintf("%s Pass", tle.Name),
+		Description: fmt.Sprintf("Rises at %s in the %s, reaches %.0f° in the %s at %s and sets at %s in the %s",
+			observer.ClockSeconds(details.Rise), compass(details.RiseAzimuth),
+			details.MaxElevation, compass(details.CulminationAzimuth), observer.ClockSeconds(details.Culmination),
+			observer.ClockSeconds(details.Set), compass(details.SetAzimuth)),
+		StartTime:  details.Rise,
+		EndTime:    details.Set,
+		Type:       domain.Satellite,
+		Visibility: visibility,
+		Source:     sourceName,
+		Satellite:  details,
+	}
+}
+
+// compass names the 16-point compass direction of an azimuth
+func compass(azimuth float64) string {
+	points := []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+	return points[int(astro.NormalizeDegrees(azimuth+11.25)/22.5)%16]
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func (r *satelliteRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs are satellite-<catalog number>-<rise time>
+	parts := strings.Split(id, "-")
+	if len(parts) != 3 || parts[0] != "satellite" {
+		return nil, nil
+	}
+	catalogNumber, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, nil
+	}
+	rise, err := time.Parse("20060102T1504Z", parts[2])
+	if err != nil {
+		return nil, nil
+	}
+
+	tles, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	for _, tle := range tles {
+		if tle.CatalogNumber != catalogNumber {
+			continue
+		}
+		timeRange := domain.TimeRange{Start: rise.Add(-time.Hour), End: rise.Add(time.Hour)}
+		events, err := r.passes(tle, timeRange, observer)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			if event.ID == id {
+				return &event, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *satelliteRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []domain.Event
+	for _, event := range events {
+		if event.Type == eventType {
+			filtered = append(filtered, event)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *satelliteRepository) Name() string {
+	return sourceName
+}
@@ -0,0 +1,126 @@
+package satellites
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+var newYork = domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
+
+func TestSatelliteRepository_GetEvents(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		timeRange   domain.TimeRange
+		wantCount   int
+		wantRise    time.Time
+		wantMaxElev float64
+		wantVisible bool
+	}{
+		{
+			name: "evening pass of the ISS from a single file",
+			path: "testdata/stations/iss.tle",
+			timeRange: domain.TimeRange{
+				Start: time.Date(2008, 9, 21, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2008, 9, 21, 1, 0, 0, 0, time.UTC),
+			},
+			wantCount:   1,
+			wantRise:    time.Date(2008, 9, 21, 0, 23, 57, 0, time.UTC),
+			wantMaxElev: 50.2,
+			wantVisible: true,
+		},
+		{
+			name: "ISS in the Earth's shadow after midnight",
+			path: "testdata/stations",
+			timeRange: domain.TimeRange{
+				Start: time.Date(2008, 9, 21, 6, 30, 0, 0, time.UTC),
+				End:   time.Date(2008, 9, 21, 7, 30, 0, 0, time.UTC),
+			},
+			wantCount:   1,
+			wantRise:    time.Date(2008, 9, 21, 6, 47, 48, 0, time.UTC),
+			wantMaxElev: 34.8,
+			wantVisible: false,
+		},
+		{
+			name: "element sets too old for the range",
+			path: "testdata/stations",
+			timeRange: domain.TimeRange{
+				Start: time.Date(2009, 9, 21, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2009, 9, 22, 0, 0, 0, 0, time.UTC),
+			},
+			wantCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := NewSatelliteRepository(tt.path, 10)
+			if err != nil {
+				t.Fatalf("NewSatelliteRepository() error = %v", err)
+			}
+			events, err := repo.GetEvents(context.Background(), tt.timeRange, newYork)
+			if err != nil {
+				t.Fatalf("GetEvents() error = %v", err)
+			}
+
+			var iss []domain.Event
+			for _, event := range events {
+				if event.Satellite.CatalogNumber == 25544 {
+					iss = append(iss, event)
+				}
+			}
+			if len(iss) != tt.wantCount {
+				t.Fatalf("GetEvents() returned %d ISS passes, want %d", len(iss), tt.wantCount)
+			}
+			if tt.wantCount == 0 {
+				return
+			}
+
+			pass := iss[0].Satellite
+			if diff := pass.Rise.Sub(tt.wantRise).Abs(); diff > 2*time.Second {
+				t.Errorf("rise = %v, want %v", pass.Rise, tt.wantRise)
+			}
+			if diff := pass.MaxElevation - tt.wantMaxElev; diff > 0.1 || diff < -0.1 {
+				t.Errorf("max elevation = %v, want %v", pass.MaxElevation, tt.wantMaxElev)
+			}
+			if pass.Visible != tt.wantVisible {
+				t.Errorf("visible = %v, want %v", pass.Visible, tt.wantVisible)
+			}
+			if !(pass.Rise.Before(pass.Culmination) && pass.Culmination.Before(pass.Set)) {
+				t.Errorf("pass out of order: %v %v %v", pass.Rise, pass.Culmination, pass.Set)
+			}
+			if iss[0].Type != domain.Satellite || iss[0].StartTime != pass.Rise || iss[0].EndTime != pass.Set {
+				t.Errorf("event = %+v", iss[0])
+			}
+		})
+	}
+}
+
+func TestSatelliteRepository_GetEventByID(t *testing.T) {
+	repo, err := NewSatelliteRepository("testdata/stations", 10)
+	if err != nil {
+		t.Fatalf("NewSatelliteRepository() error = %v", err)
+	}
+
+	event, err := repo.GetEventByID(context.Background(), "satellite-25544-20080921T0023Z", newYork)
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if event == nil || event.Title != "ISS (ZARYA) Pass" {
+		t.Fatalf("GetEventByID() = %v, want the ISS pass", event)
+	}
+
+	event, err = repo.GetEventByID(context.Background(), "satellite-25544-20080921T0123Z", newYork)
+	if err != nil || event != nil {
+		t.Errorf("GetEventByID() = %v, %v, want nil", event, err)
+	}
+}
+
+func TestNewSatelliteRepository_MissingPath(t *testing.T) {
+	if _, err := NewSatelliteRepository("testdata/missing.tle", 10); err == nil {
+		t.Error("NewSatelliteRepository() with a missing file, want error")
+	}
+}
@@ -0,0 +1,182 @@
+package seasons
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+)
+
+const sourceName = "Local Season Calculator"
+
+var markers = map[astro.SeasonKind]struct {
+	marker    domain.SeasonMarker
+	eventType domain.EventType
+	title     string
+	// north and south name the astronomical season that begins in each
+	// hemisphere
+	north, south string
+}{
+	astro.MarchEquinox:     {domain.MarchEquinox, domain.Equinox, "March Equinox", "spring", "autumn"},
+	astro.JuneSolstice:     {domain.JuneSolstice, domain.Solstice, "June Solstice", "summer", "winter"},
+	astro.SeptemberEquinox: {domain.SeptemberEquinox, domain.Equinox, "September Equinox", "autumn", "spring"},
+	astro.DecemberSolstice: {domain.DecemberSolstice, domain.Solstice, "December Solstice", "winter", "summer"},
+}
+
+type seasonRepository struct{}
+
+// NewSeasonRepository creates a repository that computes equinoxes,
+// solstices and the Earth's perihelion and aphelion
+func NewSeasonRepository() *seasonRepository {
+	return &seasonRepository{}
+}
+
+func (r *seasonRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	startJD := astro.JulianDay(timeRange.Start)
+	endJD := astro.JulianDay(timeRange.End)
+
+	var events []domain.Event
+	for _, s := range astro.Seasons(startJD, endJD) {
+		events = append(events, seasonEvent(s, observer))
+	}
+	for _, a := range astro.EarthApsides(startJD, endJD) {
+		events = append(events, apsisEvent(a, observer))
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	return events, nil
+}
+
+func seasonEvent(s astro.Season, observer domain.Observer) domain.Event {
+	m := markers[s.Kind]
+	details := newDetails(m.marker, s.JD, observer)
+
+	begins := m.north
+	if observer.Latitude < 0 {
+		begins = m.south
+	}
+
+	return domain.Event{
+		ID:    fmt.Sprintf("season-%s-%d", strings.ToLower(strings.ReplaceAll(string(m.marker), "_", "-")), details.UTC.Year()),
+		Title: m.title,
+		Description: fmt.Sprintf("%s at %s. Astronomical %s begins in the northern hemisphere and %s in the southern",
+			m.title, instant(details), m.north, m.south),
+		StartTime:  details.UTC,
+		EndTime:    details.UTC,
+		Type:       m.eventType,
+		Visibility: fmt.Sprintf("Start of astronomical %s at the observer's location", begins),
+		Location:   sunConstellation(s.JD),
+		Source:     sourceName,
+		Season:     details,
+	}
+}
+
+func apsisEvent(a astro.EarthApsis, observer domain.Observer) domain.Event {
+	marker, eventType, title, name := domain.EarthAphelion, domain.Aphelion, "Earth at Aphelion", "aphelion"
+	if a.Perihelion {
+		marker, eventType, title, name = domain.EarthPerihelion, domain.Perihelion, "Earth at Perihelion", "perihelion"
+	}
+	details := newDetails(marker, a.JD, observer)
+	details.DistanceAU = a.Distance
+
+	return domain.Event{
+		ID:    fmt.Sprintf("earth-%s-%d", name, details.UTC.Year()),
+		Title: title,
+		Description: fmt.Sprintf("%s at %s, %.6f AU (%.0f km) from the Sun",
+			title, instant(details), a.Distance, a.Distance*astro.AstronomicalUnitKm),
+		StartTime: details.UTC,
+		EndTime:   details.UTC,
+		Type:      eventType,
+		Location:  sunConstellation(a.JD),
+		Source:    sourceName,
+		Season:    details,
+	}
+}
+
+// sunConstellation names the constellation the Sun is in at a Julian day
+func sunConstellation(jd float64) string {
+	jde := astro.JDE(jd)
+	return domain.ConstellationAt(astro.SunApparent(jde), jde).Name
+}
+
+func newDetails(marker domain.SeasonMarker, jd float64, observer domain.Observer) *domain.SeasonDetails {
+	utc := astro.TimeFromJulianDay(jd).Round(time.Minute)
+	loc := observer.Location()
+	return &domain.SeasonDetails{
+		Marker:   marker,
+		UTC:      utc,
+		Local:    utc.In(loc),
+		TimeZone: loc.String(),
+	}
+}
+
+// instant formats a season marker in UTC, followed by local time unless the
+// observer keeps UTC
+func instant(d *domain.SeasonDetails) string {
+	text := d.UTC.Format("2006-01-02 15:04 UTC")
+	if d.Local.Location() != time.UTC {
+		text += d.Local.Format(" (2006-01-02 15:04 MST)")
+	}
+	return text
+}
+
+func (r *seasonRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs start with the kind of event and end with its year
+	ours := strings.HasPrefix(id, "season-") || strings.HasPrefix(id, "earth-")
+	i := strings.LastIndex(id, "-")
+	if !ours || i < 0 {
+		return nil, nil
+	}
+	year, err := strconv.Atoi(id[i+1:])
+	if err != nil {
+		return nil, nil
+	}
+
+	timeRange := domain.TimeRange{
+		Start: time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(year+1, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.ID == id {
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *seasonRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []domain.Event
+	for _, event := range events {
+		if event.Type == eventType {
+			filtered = append(filtered, event)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *seasonRepository) Name() string {
+	return sourceName
+}
@@ -0,0 +1,84 @@
+package seasons
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+// Instants of 2024 from the U.S. Naval Observatory, rounded to the minute
+func TestSeasonRepository_GetEvents(t *testing.T) {
+	newYork := domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	events, err := NewSeasonRepository().GetEvents(context.Background(), timeRange, newYork)
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+
+	tests := []struct {
+		id        string
+		wantType  domain.EventType
+		wantTime  time.Time
+		tolerance time.Duration
+		wantLocal string
+	}{
+		{"earth-perihelion-2024", domain.Perihelion, time.Date(2024, 1, 3, 0, 39, 0, 0, time.UTC), 30 * time.Minute, "2024-01-02"},
+		{"season-march-equinox-2024", domain.Equinox, time.Date(2024, 3, 20, 3, 6, 0, 0, time.UTC), time.Minute, "2024-03-19"},
+		{"season-june-solstice-2024", domain.Solstice, time.Date(2024, 6, 20, 20, 51, 0, 0, time.UTC), time.Minute, "2024-06-20"},
+		{"earth-aphelion-2024", domain.Aphelion, time.Date(2024, 7, 5, 5, 6, 0, 0, time.UTC), 30 * time.Minute, "2024-07-05"},
+		{"season-september-equinox-2024", domain.Equinox, time.Date(2024, 9, 22, 12, 44, 0, 0, time.UTC), time.Minute, "2024-09-22"},
+		{"season-december-solstice-2024", domain.Solstice, time.Date(2024, 12, 21, 9, 21, 0, 0, time.UTC), time.Minute, "2024-12-21"},
+	}
+
+	if len(events) != len(tests) {
+		t.Fatalf("GetEvents() got %v events, want %v", len(events), len(tests))
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			event := events[i]
+			if event.ID != tt.id || event.Type != tt.wantType {
+				t.Fatalf("event %d = %v %v, want %v %v", i, event.ID, event.Type, tt.id, tt.wantType)
+			}
+			if diff := event.Season.UTC.Sub(tt.wantTime).Abs(); diff > tt.tolerance {
+				t.Errorf("UTC = %v, want %v", event.Season.UTC, tt.wantTime)
+			}
+			if !event.Season.Local.Equal(event.Season.UTC) || event.Season.TimeZone != "America/New_York" {
+				t.Errorf("Local = %v in %v, want the same instant in America/New_York", event.Season.Local, event.Season.TimeZone)
+			}
+			if got := event.Season.Local.Format("2006-01-02"); got != tt.wantLocal {
+				t.Errorf("local date = %v, want %v", got, tt.wantLocal)
+			}
+		})
+	}
+}
+
+func TestSeasonRepository_GetEventByID(t *testing.T) {
+	repo := NewSeasonRepository()
+	sydney := domain.Observer{Latitude: -33.8688, Longitude: 151.2093, TimeZone: "Australia/Sydney"}
+
+	event, err := repo.GetEventByID(context.Background(), "season-december-solstice-2030", sydney)
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if event == nil || event.Season.Marker != domain.DecemberSolstice {
+		t.Fatalf("GetEventByID() = %v, want the December solstice", event)
+	}
+	if event.Visibility != "Start of astronomical summer at the observer's location" {
+		t.Errorf("Visibility = %q, want summer in the southern hemisphere", event.Visibility)
+	}
+	if event.Location != "Sagittarius" {
+		t.Errorf("Location = %q, want the Sun in Sagittarius", event.Location)
+	}
+
+	event, err = repo.GetEventByID(context.Background(), "season-december-solstice", sydney)
+	if err != nil || event != nil {
+		t.Errorf("GetEventByID() = %v, %v, want nil", event, err)
+	}
+}
@@ -0,0 +1,302 @@
+package smallbodies
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+)
+
+// gaussianMotion is the mean daily motion in degrees of a body with a
+// semi-major axis of one AU
+const gaussianMotion = 0.9856076686
+
+// body is a small body with its elements prepared for the ephemeris
+type body struct {
+	info     domain.SmallBody
+	elements astro.OrbitalElements
+	law      astro.MagnitudeLaw
+}
+
+// parseElements reads orbital elements in either of the Minor Planet Center
+// formats, MPCORB for asteroids or CometEls for comets, detected line by
+// line. Header lines of MPCORB.DAT and blank lines are skipped.
+func parseElements(r io.Reader) ([]body, error) {
+	var bodies []body
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 1024), 1024*1024)
+
+	header := false
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), " \r")
+		switch {
+		case strings.HasPrefix(line, "-----"):
+			// MPCORB.DAT ends its header with a dashed rule
+			header = false
+			continue
+		case lineNo == 1 && !isCometLine(line) && !isAsteroidLine(line):
+			header = true
+			continue
+		case header || strings.TrimSpace(line) == "":
+			continue
+		}
+
+		var b body
+		var err error
+		switch {
+		case isCometLine(line):
+			b, err = parseComet(line)
+		case isAsteroidLine(line):
+			b, err = parseAsteroid(line)
+		default:
+			err = fmt.Errorf("unrecognised orbit format")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		bodies = append(bodies, b)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return bodies, nil
+}
+
+// isCometLine recognises the perihelion date of the CometEls format in
+// columns 15-29
+func isCometLine(line string) bool {
+	return len(line) >= 100 && isDigits(line[14:18]) && line[18] == ' ' && line[21] == ' '
+}
+
+// isAsteroidLine recognises the packed epoch of the MPCORB format in
+// columns 21-25
+func isAsteroidLine(line string) bool {
+	return len(line) >= 103 && line[19] == ' ' && line[25] == ' ' && strings.ContainsRune("IJK", rune(line[20]))
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// columns returns the text between two 1-based inclusive columns
+func columns(line string, from, to int) string {
+	if from > len(line) {
+		return ""
+	}
+	return strings.TrimSpace(line[from-1 : min(to, len(line))])
+}
+
+// parseComet reads a line of the CometEls.txt format
+func parseComet(line string) (body, error) {
+	var err error
+	number := func(from, to int) float64 {
+		if err != nil {
+			return 0
+		}
+		var v float64
+		v, err = strconv.ParseFloat(columns(line, from, to), 64)
+		return v
+	}
+
+	year := number(15, 18)
+	month := number(20, 21)
+	day := number(23, 29)
+	q := number(31, 39)
+	e := number(42, 49)
+	w := number(52, 59)
+	node := number(62, 69)
+	i := number(72, 79)
+	if err != nil {
+		return body{}, err
+	}
+
+	// Absolute magnitude and slope may be blank for poorly observed comets
+	h, _ := strconv.ParseFloat(columns(line, 92, 95), 64)
+	k, _ := strconv.ParseFloat(columns(line, 97, 100), 64)
+
+	perihelion := astro.JulianDay(time.Date(int(year), time.Month(month), 0, 0, 0, 0, 0, time.UTC)) + day
+	designation, name := splitCometName(columns(line, 103, 158))
+
+	return body{
+		info: domain.SmallBody{
+			Kind:                 domain.Comet,
+			Designation:          designation,
+			Name:                 name,
+			PerihelionDistanceAU: q,
+			Eccentricity:         e,
+			InclinationDeg:       i,
+			NodeDeg:              node,
+			ArgPerihelionDeg:     w,
+			PerihelionTime:       astro.TimeFromJulianDay(astro.UniversalTime(perihelion)).Round(time.Minute),
+			AbsoluteMagnitude:    h,
+			Slope:                k,
+		},
+		elements: astro.OrbitalElements{
+			PerihelionDistance: q,
+			Eccentricity:       e,
+			Inclination:        i,
+			Node:               node,
+			ArgPerihelion:      w,
+			PerihelionJDE:      perihelion,
+		},
+		law: astro.CometMagnitude,
+	}, nil
+}
+
+// splitCometName separates "1P/Halley" and "C/2020 F3 (NEOWISE)" into a
+// designation and a name
+func splitCometName(s string) (designation, name string) {
+	if open := strings.LastIndex(s, " ("); open > 0 && strings.HasSuffix(s, ")") {
+		return s[:open], s[open+2 : len(s)-1]
+	}
+	if slash := strings.Index(s, "/"); slash > 0 && isDigits(s[:slash-1]) {
+		return s[:slash], s[slash+1:]
+	}
+	return s, ""
+}
+
+// parseAsteroid reads a line of the MPCORB.DAT format
+func parseAsteroid(line string) (body, error) {
+	var err error
+	number := func(from, to int) float64 {
+		if err != nil {
+			return 0
+		}
+		var v float64
+		v, err = strconv.ParseFloat(columns(line, from, to), 64)
+		return v
+	}
+
+	m := number(27, 35)
+	w := number(38, 46)
+	node := number(49, 57)
+	i := number(60, 68)
+	e := number(71, 79)
+	a := number(93, 103)
+	if err != nil {
+		return body{}, err
+	}
+	epoch, err := unpackEpoch(columns(line, 21, 25))
+	if err != nil {
+		return body{}, err
+	}
+
+	// H and G may be blank; G defaults to 0.15
+	h, _ := strconv.ParseFloat(columns(line, 9, 13), 64)
+	g, gErr := strconv.ParseFloat(columns(line, 15, 19), 64)
+	if gErr != nil {
+		g = 0.15
+	}
+
+	// Perihelion is found by running the mean anomaly back to zero
+	perihelion := epoch - astro.NormalizeDegrees(m)/(gaussianMotion/(a*math.Sqrt(a)))
+	designation, name := splitAsteroidName(columns(line, 167, 194), columns(line, 1, 7))
+
+	return body{
+		info: domain.SmallBody{
+			Kind:                 domain.Asteroid,
+			Designation:          designation,
+			Name:                 name,
+			PerihelionDistanceAU: a * (1 - e),
+			Eccentricity:         e,
+			InclinationDeg:       i,
+			NodeDeg:              node,
+			ArgPerihelionDeg:     w,
+			PerihelionTime:       astro.TimeFromJulianDay(astro.UniversalTime(perihelion)).Round(time.Minute),
+			AbsoluteMagnitude:    h,
+			Slope:                g,
+		},
+		elements: astro.OrbitalElements{
+			PerihelionDistance: a * (1 - e),
+			Eccentricity:       e,
+			Inclination:        i,
+			Node:               node,
+			ArgPerihelion:      w,
+			PerihelionJDE:      perihelion,
+		},
+		law: astro.AsteroidMagnitude,
+	}, nil
+}
+
+// splitAsteroidName separates the readable designation "(1) Ceres" into a
+// number and a name, falling back to the packed designation
+func splitAsteroidName(readable, packed string) (designation, name string) {
+	if strings.HasPrefix(readable, "(") {
+		if end := strings.Index(readable, ")"); end > 0 {
+			return readable[1:end], strings.TrimSpace(readable[end+1:])
+		}
+	}
+	if readable != "" {
+		return readable, ""
+	}
+	if n, err := unpackNumber(packed); err == nil {
+		return strconv.Itoa(n), ""
+	}
+	return packed, ""
+}
+
+// unpackNumber decodes a packed asteroid number such as "00433" or "A0345"
+func unpackNumber(packed string) (int, error) {
+	if len(packed) != 5 {
+		return 0, fmt.Errorf("invalid packed number %q", packed)
+	}
+	lead, err := packedDigit(packed[0])
+	if err != nil {
+		return 0, err
+	}
+	rest, err := strconv.Atoi(packed[1:])
+	if err != nil {
+		return 0, err
+	}
+	return lead*10000 + rest, nil
+}
+
+// unpackEpoch decodes a packed date such as "K2555" (2025 May 5.0 TT) to a
+// Julian ephemeris day
+func unpackEpoch(packed string) (float64, error) {
+	if len(packed) != 5 {
+		return 0, fmt.Errorf("invalid packed epoch %q", packed)
+	}
+	century, err := packedDigit(packed[0])
+	if err != nil {
+		return 0, err
+	}
+	year, err := strconv.Atoi(packed[1:3])
+	if err != nil {
+		return 0, fmt.Errorf("invalid packed epoch %q", packed)
+	}
+	month, err := packedDigit(packed[3])
+	if err != nil {
+		return 0, err
+	}
+	day, err := packedDigit(packed[4])
+	if err != nil {
+		return 0, err
+	}
+	date := time.Date(century*100+year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	return astro.JulianDay(date), nil
+}
+
+// packedDigit decodes the MPC base-62 digits 0-9, A-Z and a-z
+func packedDigit(c byte) (int, error) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0'), nil
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 10, nil
+	case c >= 'a' && c <= 'z':
+		return int(c-'a') + 36, nil
+	}
+	return 0, fmt.Errorf("invalid packed digit %q", c)
+}
@@ -0,0 +1,377 @@
+package smallbodies
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+)
+
+const sourceName = "Local Small Body Ephemeris"
+
+// elementFileExtensions are the files read from an elements directory
+var elementFileExtensions = map[string]bool{".txt": true, ".dat": true}
+
+// brighteningHorizon bounds the search for the peak and the fading of a
+// body after it crosses the magnitude limit, in days
+const brighteningHorizon = 730.0
+
+type smallBodyRepository struct {
+	bodies         []body
+	magnitudeLimit float64
+}
+
+// NewSmallBodyRepository creates a repository that computes the perihelia
+// of the comets and asteroids in a file of Minor Planet Center orbital
+// elements, or in the .txt and .dat files of a directory, and the moments
+// they become brighter than magnitudeLimit. Positions follow the unperturbed
+// two-body orbit, so predictions degrade away from the elements' epoch.
+func NewSmallBodyRepository(path string, magnitudeLimit float64) (*smallBodyRepository, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading orbital elements: %w", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("loading orbital elements: %w", err)
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && elementFileExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	r := &smallBodyRepository{magnitudeLimit: magnitudeLimit}
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("loading orbital elements: %w", err)
+		}
+		bodies, err := parseElements(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", file, err)
+		}
+		r.bodies = append(r.bodies, bodies...)
+	}
+	return r, nil
+}
+
+func (r *smallBodyRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	startJDE := astro.JDE(astro.JulianDay(timeRange.Start))
+	endJDE := astro.JDE(astro.JulianDay(timeRange.End))
+
+	var events []domain.Event
+	for _, b := range r.bodies {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		events = append(events, r.perihelia(b, startJDE, endJDE, observer)...)
+		events = append(events, r.brightenings(b, startJDE, endJDE, observer)...)
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	return events, nil
+}
+
+// perihelia returns the perihelion passages of a body within the range,
+// repeating periodic orbits
+func (r *smallBodyRepository) perihelia(b body, startJDE, endJDE float64, observer domain.Observer) []domain.Event {
+	t0 := b.elements.PerihelionJDE
+	period := b.elements.Period()
+
+	first, last := 0.0, 0.0
+	if !math.IsInf(period, 1) {
+		first = math.Ceil((startJDE - t0) / period)
+		last = math.Floor((endJDE - t0) / period)
+	}
+
+	var events []domain.Event
+	for k := first; k <= last; k++ {
+		jde := t0 + k*period
+		if jde < startJDE || jde >= endJDE {
+			continue
+		}
+		details := newDetails(b, jde)
+		events = append(events, domain.Event{
+			ID:    fmt.Sprintf("perihelion-%s-%s", slug(b.info.Designation), details.time.Format("2006-01-02")),
+			Title: fmt.Sprintf("%s at Perihelion", b.info.FullName()),
+			Description: fmt.Sprintf("Closest to the Sun at %s, %.3f AU from the Sun and %.3f AU from the Earth, at predicted magnitude %.1f",
+				dateClock(details.time, observer), details.SunDistanceAU, details.EarthDistanceAU, details.Magnitude),
+			StartTime:  details.time,
+			EndTime:    details.time,
+			Type:       domain.Perihelion,
+			Visibility: visibility(details.SmallBodyDetails),
+			Location:   constellation(details.SmallBodyDetails),
+			Source:     sourceName,
+			SmallBody:  details.SmallBodyDetails,
+		})
+	}
+	return events
+}
+
+// brightenings returns the moments within the range at which a body becomes
+// brighter than the magnitude limit, with the peak and fading that follow
+func (r *smallBodyRepository) brightenings(b body, startJDE, endJDE float64, observer domain.Observer) []domain.Event {
+	if !r.canReachLimit(b) {
+		return nil
+	}
+
+	excess := func(jde float64) float64 {
+		return r.magnitude(b, jde) - r.magnitudeLimit
+	}
+
+	var events []domain.Event
+	prevJDE, prev := startJDE, excess(startJDE)
+	for jde := prevJDE + r.step(b, prevJDE); prevJDE < endJDE; jde += r.step(b, jde) {
+		cur := excess(jde)
+		if prev > 0 && cur <= 0 {
+			crossing := bisect(prevJDE, jde, excess)
+			if crossing < endJDE {
+				events = append(events, r.brighteningEvent(b, crossing, observer))
+			}
+		}
+		prevJDE, prev = jde, cur
+	}
+	return events
+}
+
+func (r *smallBodyRepository) brighteningEvent(b body, crossing float64, observer domain.Observer) domain.Event {
+	excess := func(jde float64) float64 {
+		return r.magnitude(b, jde) - r.magnitudeLimit
+	}
+
+	// Follow the body until it fades past the limit again, keeping track of
+	// its brightest sample
+	peak, peakMag := crossing, excess(crossing)
+	fade := math.NaN()
+	prevJDE := crossing
+	for jde := crossing + r.step(b, crossing); jde <= crossing+brighteningHorizon; jde += r.step(b, jde) {
+		cur := excess(jde)
+		if cur > 0 {
+			fade = bisect(prevJDE, jde, excess)
+			break
+		}
+		if cur < peakMag {
+			peak, peakMag = jde, cur
+		}
+		prevJDE = jde
+	}
+	peak = minimize(math.Max(crossing, peak-r.step(b, peak)), peak+r.step(b, peak), excess)
+
+	details := newDetails(b, crossing)
+	peakTime := minuteTime(peak)
+	details.PeakMagnitude = r.magnitude(b, peak)
+	details.PeakTime = &peakTime
+
+	description := fmt.Sprintf("Predicted to brighten past magnitude %.1f at %s, %.3f AU from the Earth and %.0f° from the Sun. Peaks at magnitude %.1f on %s",
+		r.magnitudeLimit, dateClock(details.time, observer), details.EarthDistanceAU, details.ElongationDeg,
+		details.PeakMagnitude, peakTime.In(observer.Location()).Format("2006-01-02"))
+
+	end := details.time
+	if !math.IsNaN(fade) {
+		end = minuteTime(fade)
+		description += fmt.Sprintf(" and fades below magnitude %.1f on %s",
+			r.magnitudeLimit, end.In(observer.Location()).Format("2006-01-02"))
+	}
+
+	return domain.Event{
+		ID:          fmt.Sprintf("brightening-%s-%s", slug(b.info.Designation), details.time.Format("2006-01-02")),
+		Title:       fmt.Sprintf("%s Brighter Than Magnitude %.1f", b.info.FullName(), r.magnitudeLimit),
+		Description: description,
+		StartTime:   details.time,
+		EndTime:     end,
+		Type:        domain.Brightening,
+		Visibility:  visibility(details.SmallBodyDetails),
+		Location:    constellation(details.SmallBodyDetails),
+		Source:      sourceName,
+		SmallBody:   details.SmallBodyDetails,
+	}
+}
+
+func (r *smallBodyRepository) magnitude(b body, jde float64) float64 {
+	return astro.SmallBodyAt(b.elements, b.law, b.info.AbsoluteMagnitude, b.info.Slope, jde).Magnitude
+}
+
+// step is the sampling interval of the brightness search: a day, shortened
+// as the body approaches the Earth and its brightness changes quickly
+func (r *smallBodyRepository) step(b body, jde float64) float64 {
+	delta := astro.SmallBodyAt(b.elements, b.law, b.info.AbsoluteMagnitude, b.info.Slope, jde).EarthDistance
+	return math.Max(1.0/24, math.Min(1, 10*delta))
+}
+
+// canReachLimit rules out bodies whose perihelion lies so far beyond the
+// Earth's orbit that they cannot become brighter than the limit, sparing the
+// search over the bulk of an MPCORB file
+func (r *smallBodyRepository) canReachLimit(b body) bool {
+	const earthAphelion = 1.0167
+	q := b.elements.PerihelionDistance
+	if q <= earthAphelion {
+		return true
+	}
+
+	// The phase term only ever dims an asteroid, and a comet's heliocentric
+	// term is smallest at perihelion unless its slope is negative
+	brightest := b.info.AbsoluteMagnitude + 5*math.Log10(q-earthAphelion)
+	switch {
+	case b.law == astro.CometMagnitude && b.info.Slope < 0:
+		return true
+	case b.law == astro.CometMagnitude:
+		brightest += 2.5 * b.info.Slope * math.Log10(q)
+	default:
+		brightest += 5 * math.Log10(q)
+	}
+	return brightest <= r.magnitudeLimit
+}
+
+// details carries the event instant alongside the domain details
+type details struct {
+	*domain.SmallBodyDetails
+	time time.Time
+}
+
+func newDetails(b body, jde float64) details {
+	place := astro.SmallBodyAt(b.elements, b.law, b.info.AbsoluteMagnitude, b.info.Slope, jde)
+	return details{
+		SmallBodyDetails: &domain.SmallBodyDetails{
+			Body:            b.info,
+			Magnitude:       place.Magnitude,
+			RA:              place.Position.RA,
+			Dec:             place.Position.Dec,
+			SunDistanceAU:   place.SunDistance,
+			EarthDistanceAU: place.EarthDistance,
+			ElongationDeg:   place.Elongation,
+		},
+		time: minuteTime(jde),
+	}
+}
+
+// constellation names the constellation a body is in at the instant of an
+// event
+func constellation(d *domain.SmallBodyDetails) string {
+	return domain.ConstellationAt(astro.Equatorial{RA: d.RA, Dec: d.Dec}, astro.J2000).Name
+}
+
+// visibility describes how a body can be seen at the instant of an event
+func visibility(d *domain.SmallBodyDetails) string {
+	switch {
+	case d.ElongationDeg < 20:
+		return "Not observable: too close to the Sun"
+	case d.Magnitude <= 6:
+		return "Visible to the naked eye from a dark site"
+	case d.Magnitude <= 9:
+		return "Visible in binoculars from a dark site"
+	default:
+		return "Requires a telescope"
+	}
+}
+
+// minuteTime converts a Julian ephemeris day to UTC to the minute, as finely
+// as orbital elements place perihelia and brightenings
+func minuteTime(jde float64) time.Time {
+	return astro.TimeFromJulianDay(astro.UniversalTime(jde)).Round(time.Minute)
+}
+
+// dateClock formats an instant with its date in the observer's time zone,
+// as perihelia and brightenings are described weeks ahead
+func dateClock(t time.Time, observer domain.Observer) string {
+	return t.In(observer.Location()).Format("2006-01-02 15:04 MST")
+}
+
+// slug turns a designation such as "C/2020 F3" into "c-2020-f3"
+func slug(designation string) string {
+	return strings.ToLower(strings.Join(strings.FieldsFunc(designation, func(c rune) bool {
+		return !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z')
+	}), "-"))
+}
+
+// bisect finds the root of f between a and b, where it changes sign, to
+// within a minute
+func bisect(a, b float64, f func(float64) float64) float64 {
+	fa := f(a)
+	for b-a > 1.0/1440 {
+		mid := (a + b) / 2
+		fm := f(mid)
+		if (fa <= 0) == (fm <= 0) {
+			a, fa = mid, fm
+		} else {
+			b = mid
+		}
+	}
+	return (a + b) / 2
+}
+
+// minimize finds the minimum of f between a and b by golden-section search
+func minimize(a, b float64, f func(float64) float64) float64 {
+	const invPhi = 0.6180339887498949
+	for b-a > 1.0/1440 {
+		c := b - (b-a)*invPhi
+		d := a + (b-a)*invPhi
+		if f(c) < f(d) {
+			b = d
+		} else {
+			a = c
+		}
+	}
+	return (a + b) / 2
+}
+
+func (r *smallBodyRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs start with the kind of event and end with its UTC date
+	ours := strings.HasPrefix(id, "perihelion-") || strings.HasPrefix(id, "brightening-")
+	if !ours || len(id) < len("2006-01-02") {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", id[len(id)-len("2006-01-02"):])
+	if err != nil {
+		return nil, nil
+	}
+
+	timeRange := domain.TimeRange{Start: date, End: date.Add(24 * time.Hour)}
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.ID == id {
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *smallBodyRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []domain.Event
+	for _, event := range events {
+		if event.Type == eventType {
+			filtered = append(filtered, event)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *smallBodyRepository) Name() string {
+	return sourceName
+}
@@ -0,0 +1,178 @@
+package smallbodies
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+var newYork = domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
+
+func TestSmallBodyRepository_GetEvents(t *testing.T) {
+	tests := []struct {
+		name      string
+		timeRange domain.TimeRange
+		wantIDs   []string
+	}{
+		{
+			name: "Halley's 1986 apparition",
+			timeRange: domain.TimeRange{
+				Start: time.Date(1985, 7, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(1986, 7, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantIDs: []string{"brightening-1p-1985-10-12", "perihelion-1-1986-02-07", "perihelion-1p-1986-02-09"},
+		},
+		{
+			name: "Encke's return predicted from the 1990 elements",
+			timeRange: domain.TimeRange{
+				Start: time.Date(1990, 7, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(1991, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantIDs: []string{"perihelion-1-1990-09-15", "brightening-2p-1990-09-24", "perihelion-2p-1990-10-28"},
+		},
+		{
+			name: "Ceres stays brighter than the limit",
+			timeRange: domain.TimeRange{
+				Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantIDs: nil,
+		},
+	}
+
+	repo, err := NewSmallBodyRepository("testdata", 10)
+	if err != nil {
+		t.Fatalf("NewSmallBodyRepository() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := repo.GetEvents(context.Background(), tt.timeRange, newYork)
+			if err != nil {
+				t.Fatalf("GetEvents() error = %v", err)
+			}
+			if len(events) != len(tt.wantIDs) {
+				t.Fatalf("GetEvents() returned %d events, want %d", len(events), len(tt.wantIDs))
+			}
+			for i, event := range events {
+				if event.ID != tt.wantIDs[i] {
+					t.Errorf("event %d ID = %v, want %v", i, event.ID, tt.wantIDs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSmallBodyRepository_Brightening(t *testing.T) {
+	repo, err := NewSmallBodyRepository("testdata/comets.txt", 10)
+	if err != nil {
+		t.Fatalf("NewSmallBodyRepository() error = %v", err)
+	}
+
+	timeRange := domain.TimeRange{
+		Start: time.Date(1990, 7, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(1991, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	events, err := repo.GetEventsByType(context.Background(), domain.Brightening, timeRange, newYork)
+	if err != nil {
+		t.Fatalf("GetEventsByType() error = %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("GetEventsByType() returned %d events, want 1", len(events))
+	}
+
+	event := events[0]
+	details := event.SmallBody
+	if math.Abs(details.Magnitude-10) > 0.01 {
+		t.Errorf("magnitude at the crossing = %v, want 10", details.Magnitude)
+	}
+	if details.PeakMagnitude >= details.Magnitude || details.PeakTime == nil {
+		t.Errorf("peak = %v at %v, want brighter than %v", details.PeakMagnitude, details.PeakTime, details.Magnitude)
+	}
+	if !(event.StartTime.Before(*details.PeakTime) && details.PeakTime.Before(event.EndTime)) {
+		t.Errorf("peak %v outside %v - %v", details.PeakTime, event.StartTime, event.EndTime)
+	}
+	if details.Body.FullName() != "2P/Encke" {
+		t.Errorf("FullName() = %v, want 2P/Encke", details.Body.FullName())
+	}
+}
+
+func TestSmallBodyRepository_GetEventByID(t *testing.T) {
+	repo, err := NewSmallBodyRepository("testdata", 10)
+	if err != nil {
+		t.Fatalf("NewSmallBodyRepository() error = %v", err)
+	}
+
+	event, err := repo.GetEventByID(context.Background(), "perihelion-1p-1986-02-09", newYork)
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if event == nil || event.Title != "1P/Halley at Perihelion" {
+		t.Fatalf("GetEventByID() = %v, want Halley's perihelion", event)
+	}
+	want := time.Date(1986, 2, 9, 11, 0, 0, 0, time.UTC)
+	if diff := event.StartTime.Sub(want).Abs(); diff > time.Minute {
+		t.Errorf("perihelion = %v, want %v", event.StartTime, want)
+	}
+
+	event, err = repo.GetEventByID(context.Background(), "perihelion-1p-1986-02-10", newYork)
+	if err != nil || event != nil {
+		t.Errorf("GetEventByID() = %v, %v, want nil", event, err)
+	}
+}
+
+func TestParseElements(t *testing.T) {
+	repo, err := NewSmallBodyRepository("testdata/MPCORB.DAT", 10)
+	if err != nil {
+		t.Fatalf("NewSmallBodyRepository() error = %v", err)
+	}
+	if len(repo.bodies) != 1 {
+		t.Fatalf("parsed %d bodies, want 1", len(repo.bodies))
+	}
+
+	ceres := repo.bodies[0]
+	if ceres.info.FullName() != "(1) Ceres" || ceres.info.Kind != domain.Asteroid {
+		t.Errorf("body = %+v, want (1) Ceres", ceres.info)
+	}
+	if math.Abs(ceres.elements.PerihelionDistance-2.548883) > 1e-6 {
+		t.Errorf("perihelion distance = %v, want 2.548883", ceres.elements.PerihelionDistance)
+	}
+	if ceres.info.AbsoluteMagnitude != 3.34 || ceres.info.Slope != 0.15 {
+		t.Errorf("H, G = %v, %v, want 3.34, 0.15", ceres.info.AbsoluteMagnitude, ceres.info.Slope)
+	}
+}
+
+func TestUnpack(t *testing.T) {
+	epoch, err := unpackEpoch("K239D")
+	if err != nil || epoch != 2460200.5 {
+		t.Errorf("unpackEpoch(K239D) = %v, %v, want 2460200.5", epoch, err)
+	}
+	number, err := unpackNumber("A0345")
+	if err != nil || number != 100345 {
+		t.Errorf("unpackNumber(A0345) = %v, %v, want 100345", number, err)
+	}
+
+	tests := []struct {
+		in                string
+		designation, name string
+	}{
+		{"1P/Halley", "1P", "Halley"},
+		{"C/2020 F3 (NEOWISE)", "C/2020 F3", "NEOWISE"},
+		{"P/2019 LD2", "P/2019 LD2", ""},
+	}
+	for _, tt := range tests {
+		designation, name := splitCometName(tt.in)
+		if designation != tt.designation || name != tt.name {
+			t.Errorf("splitCometName(%q) = %q, %q, want %q, %q", tt.in, designation, name, tt.designation, tt.name)
+		}
+	}
+}
+
+func TestNewSmallBodyRepository_MissingPath(t *testing.T) {
+	if _, err := NewSmallBodyRepository("testdata/missing.txt", 10); err == nil {
+		t.Error("NewSmallBodyRepository() with a missing file, want error")
+	}
+}
@@ -0,0 +1,53 @@
+// Package ttlcache keeps documents fetched from remote feeds for a short
+// time, so that queries close together do not fetch them again
+package ttlcache
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache holds values by key until they are ttl old. It is safe for
+// concurrent use.
+type Cache[V any] struct {
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]entry[V]
+}
+
+type entry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// New creates a cache keeping values for ttl
+func New[V any](ttl time.Duration) *Cache[V] {
+	return &Cache[V]{ttl: ttl, now: time.Now, entries: make(map[string]entry[V])}
+}
+
+// Get returns the value kept for key, reporting whether there is one that
+// has not expired
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.expires) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Put keeps value for key, dropping the values that have expired
+func (c *Cache[V]) Put(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for k, e := range c.entries {
+		if !now.Before(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = entry[V]{value: value, expires: now.Add(c.ttl)}
+}
@@ -0,0 +1,33 @@
+package ttlcache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	c := New[int](time.Minute)
+	c.now = func() time.Time { return now }
+
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get() found a value in an empty cache")
+	}
+	c.Put("a", 1)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("Get() = %d, %v, want 1", v, ok)
+	}
+
+	// Values expire after the ttl, and are dropped on the next Put
+	now = now.Add(time.Minute)
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get() found an expired value")
+	}
+	c.Put("b", 2)
+	if len(c.entries) != 1 {
+		t.Errorf("cache holds %d entries, want the expired one dropped", len(c.entries))
+	}
+	if v, ok := c.Get("b"); !ok || v != 2 {
+		t.Errorf("Get() = %d, %v, want 2", v, ok)
+	}
+}
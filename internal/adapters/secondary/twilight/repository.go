@@ -0,0 +1,329 @@
+package twilight
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+)
+
+const sourceName = "Local Twilight Calculator"
+
+// levels are the Sun altitudes separating day, the three twilight bands and
+// darkness. The Sun is in band i while it is below levels[i-1] and above
+// levels[i]; band 0 is day and band 4 is astronomical darkness.
+var levels = []float64{
+	astro.SunStandardAltitude,
+	astro.CivilTwilightAltitude,
+	astro.NauticalTwilightAltitude,
+	astro.AstronomicalTwilightAltitude,
+}
+
+var bandKinds = []domain.TwilightKind{
+	1: domain.CivilTwilight,
+	2: domain.NauticalTwilight,
+	3: domain.AstronomicalTwilight,
+}
+
+const darkBand = 4
+
+// interval is a span of a night during which the Sun stays in one band
+type interval struct {
+	band     int
+	from, to float64
+	// fromAbove is true when the Sun entered the band by sinking into it,
+	// and toAbove when it left by rising out of it
+	fromAbove, toAbove bool
+}
+
+type twilightRepository struct{}
+
+// NewTwilightRepository creates a repository that computes twilight,
+// astronomical darkness and moonless dark windows for the requesting observer
+func NewTwilightRepository() *twilightRepository {
+	return &twilightRepository{}
+}
+
+func (r *twilightRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	// Nights are dated by their evening, so a range starting in the small
+	// hours starts with the night of the day before
+	first := observer.NightDate(timeRange.Start)
+
+	var events []domain.Event
+	for date := first; date.Before(timeRange.End); date = date.AddDate(0, 0, 1) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, event := range night(date, observer) {
+			if event.StartTime.Before(timeRange.Start) || !event.StartTime.Before(timeRange.End) {
+				continue
+			}
+			events = append(events, event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	return events, nil
+}
+
+// night computes the events of the night that follows local mean noon on date
+func night(date time.Time, observer domain.Observer) []domain.Event {
+	site := astro.Site{Latitude: observer.Latitude, Longitude: observer.Longitude, Elevation: observer.Elevation}
+	startJD := astro.JulianDay(date) + 0.5 - observer.Longitude/360
+	endJD := startJD + 1
+
+	moonUp := moonIntervals(site, startJD, endJD)
+	label := date.Format("2006-01-02")
+
+	var events []domain.Event
+	for _, iv := range sunIntervals(site, startJD, endJD) {
+		switch {
+		case iv.band == darkBand:
+			events = append(events, darknessEvent(iv, moonUp, label, observer))
+			if window, ok := longestGap(iv.from, iv.to, moonUp); ok {
+				events = append(events, darkWindowEvent(window, label, observer))
+			}
+		case iv.band > 0:
+			events = append(events, twilightEvent(iv, moonUp, label, observer))
+		}
+	}
+	return events
+}
+
+// sunIntervals splits a night into the spans the Sun spends in each band
+func sunIntervals(site astro.Site, startJD, endJD float64) []interval {
+	type crossing struct {
+		jd   float64
+		down bool
+	}
+	var crossings []crossing
+	for _, h := range levels {
+		for _, he := range astro.HorizonEvents(astro.SunApparent, h, site, startJD, endJD) {
+			if he.Kind != astro.UpperTransit {
+				crossings = append(crossings, crossing{jd: he.JD, down: he.Kind == astro.Setting})
+			}
+		}
+	}
+	sort.Slice(crossings, func(i, j int) bool { return crossings[i].jd < crossings[j].jd })
+
+	band := 0
+	altitude := astro.Altitude(astro.SunApparent, startJD, site)
+	for _, h := range levels {
+		if altitude < h {
+			band++
+		}
+	}
+
+	var intervals []interval
+	current := interval{band: band, from: startJD}
+	for _, c := range crossings {
+		current.to, current.toAbove = c.jd, !c.down
+		intervals = append(intervals, current)
+
+		next := band - 1
+		if c.down {
+			next = band + 1
+		}
+		band = next
+		current = interval{band: band, from: c.jd, fromAbove: c.down}
+	}
+	current.to = endJD
+	return append(intervals, current)
+}
+
+// moonIntervals returns the spans of a night during which the Moon is up
+func moonIntervals(site astro.Site, startJD, endJD float64) [][2]float64 {
+	_, dist := astro.MoonGeocentric(astro.JDE((startJD + endJD) / 2))
+	h0 := astro.MoonStandardAltitude(dist)
+
+	var intervals [][2]float64
+	from, up := startJD, astro.Altitude(astro.MoonApparent, startJD, site) > h0
+	for _, he := range astro.HorizonEvents(astro.MoonApparent, h0, site, startJD, endJD) {
+		switch {
+		case he.Kind == astro.Rising && !up:
+			from, up = he.JD, true
+		case he.Kind == astro.Setting && up:
+			intervals = append(intervals, [2]float64{from, he.JD})
+			up = false
+		}
+	}
+	if up {
+		intervals = append(intervals, [2]float64{from, endJD})
+	}
+	return intervals
+}
+
+// overlapDays returns how long the Moon is up between two Julian days
+func overlapDays(from, to float64, moonUp [][2]float64) float64 {
+	var total float64
+	for _, up := range moonUp {
+		if start, end := max(from, up[0]), min(to, up[1]); end > start {
+			total += end - start
+		}
+	}
+	return total
+}
+
+// longestGap returns the longest span between two Julian days during which
+// the Moon is down
+func longestGap(from, to float64, moonUp [][2]float64) ([2]float64, bool) {
+	var best [2]float64
+	cursor := from
+	consider := func(end float64) {
+		if end-cursor > best[1]-best[0] {
+			best = [2]float64{cursor, end}
+		}
+	}
+	for _, up := range moonUp {
+		if up[1] <= from || up[0] >= to {
+			continue
+		}
+		consider(min(up[0], to))
+		cursor = max(cursor, up[1])
+	}
+	consider(to)
+
+	// Ignore slivers shorter than a minute
+	return best, best[1]-best[0] >= 1.0/(24*60)
+}
+
+func details(from, to float64, moonUp [][2]float64) *domain.TwilightDetails {
+	return &domain.TwilightDetails{
+		DurationMinutes:  (to - from) * 24 * 60,
+		MoonUpMinutes:    overlapDays(from, to, moonUp) * 24 * 60,
+		MoonIllumination: astro.MoonIllumination(astro.JDE((from + to) / 2)),
+	}
+}
+
+func twilightEvent(iv interval, moonUp [][2]float64, label string, observer domain.Observer) domain.Event {
+	kind := bandKinds[iv.band]
+	phase := domain.Morning
+	switch {
+	case iv.fromAbove && iv.toAbove:
+		phase = domain.AllNight
+	case iv.fromAbove:
+		phase = domain.Evening
+	}
+
+	d := details(iv.from, iv.to, moonUp)
+	d.Kind, d.Phase = kind, phase
+
+	title := fmt.Sprintf("%s %s Twilight", domain.TitleCase(string(phase)), domain.TitleCase(string(kind)))
+	if phase == domain.AllNight {
+		title = fmt.Sprintf("%s Twilight All Night", domain.TitleCase(string(kind)))
+	}
+	from, to := astro.EventTime(iv.from), astro.EventTime(iv.to)
+
+	return domain.Event{
+		ID: fmt.Sprintf("twilight-%s-%s-%s", strings.ToLower(string(kind)),
+			strings.ToLower(strings.ReplaceAll(string(phase), "_", "-")), label),
+		Title: title,
+		Description: fmt.Sprintf("%s from %s to %s (%s), while the Sun is %s below the horizon",
+			title, observer.Clock(from), observer.Clock(to), duration(d.DurationMinutes), depth(iv.band)),
+		StartTime: from,
+		EndTime:   to,
+		Type:      domain.Twilight,
+		Source:    sourceName,
+		Twilight:  d,
+	}
+}
+
+func darknessEvent(iv interval, moonUp [][2]float64, label string, observer domain.Observer) domain.Event {
+	d := details(iv.from, iv.to, moonUp)
+	from, to := astro.EventTime(iv.from), astro.EventTime(iv.to)
+
+	visibility := "Moon below the horizon all night"
+	if d.MoonUpMinutes > 0 {
+		visibility = fmt.Sprintf("Moon up for %s, %.0f%% illuminated", duration(d.MoonUpMinutes), d.MoonIllumination*100)
+	}
+
+	return domain.Event{
+		ID:    fmt.Sprintf("darkness-%s", label),
+		Title: "Astronomical Darkness",
+		Description: fmt.Sprintf("The Sun is more than 18° below the horizon from %s to %s (%s)",
+			observer.Clock(from), observer.Clock(to), duration(d.DurationMinutes)),
+		StartTime:  from,
+		EndTime:    to,
+		Type:       domain.Darkness,
+		Visibility: visibility,
+		Source:     sourceName,
+		Twilight:   d,
+	}
+}
+
+func darkWindowEvent(window [2]float64, label string, observer domain.Observer) domain.Event {
+	d := details(window[0], window[1], nil)
+	from, to := astro.EventTime(window[0]), astro.EventTime(window[1])
+
+	return domain.Event{
+		ID:    fmt.Sprintf("dark-window-%s", label),
+		Title: "Moonless Dark Window",
+		Description: fmt.Sprintf("Dark sky with the Moon below the horizon from %s to %s (%s)",
+			observer.Clock(from), observer.Clock(to), duration(d.DurationMinutes)),
+		StartTime:  from,
+		EndTime:    to,
+		Type:       domain.DarkWindow,
+		Visibility: "Best for deep-sky observing",
+		Source:     sourceName,
+		Twilight:   d,
+	}
+}
+
+// depth describes the Sun altitudes bounding a twilight band
+func depth(band int) string {
+	if band == 1 {
+		return fmt.Sprintf("up to %.0f°", -levels[band])
+	}
+	return fmt.Sprintf("%.0f° to %.0f°", -levels[band-1], -levels[band])
+}
+
+func duration(minutes float64) string {
+	m := int(minutes + 0.5)
+	return fmt.Sprintf("%dh %02dm", m/60, m%60)
+}
+
+func (r *twilightRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs start with the kind of event and end with the date of the night
+	ours := strings.HasPrefix(id, "twilight-") || strings.HasPrefix(id, "darkness-") || strings.HasPrefix(id, "dark-window-")
+	if !ours || len(id) < len("2006-01-02") {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", id[len(id)-len("2006-01-02"):])
+	if err != nil {
+		return nil, nil
+	}
+
+	for _, event := range night(date, observer) {
+		if event.ID == id {
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *twilightRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []domain.Event
+	for _, event := range events {
+		if event.Type == eventType {
+			filtered = append(filtered, event)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *twilightRepository) Name() string {
+	return sourceName
+}
@@ -0,0 +1,154 @@
+package twilight
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+var newYork = domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
+
+// Twilight times from the U.S. Naval Observatory almanac for New York on
+// 2024-06-20, rounded to the minute
+func TestTwilightRepository_GetEventsByType(t *testing.T) {
+	edt := newYork.Location()
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 6, 20, 12, 0, 0, 0, edt),
+		End:   time.Date(2024, 6, 21, 12, 0, 0, 0, edt),
+	}
+
+	repo := NewTwilightRepository()
+	events, err := repo.GetEventsByType(context.Background(), domain.Twilight, timeRange, newYork)
+	if err != nil {
+		t.Fatalf("GetEventsByType() error = %v", err)
+	}
+	if len(events) != 6 {
+		t.Fatalf("GetEventsByType() got %v events, want 6", len(events))
+	}
+
+	tests := []struct {
+		id        string
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{"twilight-civil-evening-2024-06-20", time.Date(2024, 6, 20, 20, 31, 0, 0, edt), time.Date(2024, 6, 20, 21, 4, 0, 0, edt)},
+		{"twilight-nautical-evening-2024-06-20", time.Date(2024, 6, 20, 21, 4, 0, 0, edt), time.Date(2024, 6, 20, 21, 47, 0, 0, edt)},
+		{"twilight-astronomical-evening-2024-06-20", time.Date(2024, 6, 20, 21, 47, 0, 0, edt), time.Date(2024, 6, 20, 22, 37, 0, 0, edt)},
+		{"twilight-astronomical-morning-2024-06-20", time.Date(2024, 6, 21, 3, 18, 0, 0, edt), time.Date(2024, 6, 21, 4, 9, 0, 0, edt)},
+		{"twilight-civil-morning-2024-06-20", time.Date(2024, 6, 21, 4, 52, 0, 0, edt), time.Date(2024, 6, 21, 5, 25, 0, 0, edt)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			var found *domain.Event
+			for i := range events {
+				if events[i].ID == tt.id {
+					found = &events[i]
+				}
+			}
+			if found == nil {
+				t.Fatalf("GetEventsByType() missing %v", tt.id)
+			}
+			if diff := found.StartTime.Sub(tt.wantStart).Abs(); diff > 2*time.Minute {
+				t.Errorf("StartTime = %v, want %v", found.StartTime.In(edt), tt.wantStart)
+			}
+			if diff := found.EndTime.Sub(tt.wantEnd).Abs(); diff > 2*time.Minute {
+				t.Errorf("EndTime = %v, want %v", found.EndTime.In(edt), tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestTwilightRepository_WhiteNights(t *testing.T) {
+	edinburgh := domain.Observer{Latitude: 55.9533, Longitude: -3.1883, TimeZone: "Europe/London"}
+	timeRange := domain.TimeRange{
+		Start: time.Date(2024, 6, 20, 12, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC),
+	}
+
+	events, err := NewTwilightRepository().GetEvents(context.Background(), timeRange, edinburgh)
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+
+	var allNight bool
+	for _, event := range events {
+		if event.Type == domain.Darkness || event.Type == domain.DarkWindow {
+			t.Errorf("GetEvents() returned %v, want no darkness in midsummer", event.ID)
+		}
+		if event.Twilight.Phase == domain.AllNight && event.Twilight.Kind == domain.NauticalTwilight {
+			allNight = true
+		}
+	}
+	if !allNight {
+		t.Error("GetEvents() missing nautical twilight lasting all night")
+	}
+}
+
+func TestTwilightRepository_DarkWindow(t *testing.T) {
+	edt := newYork.Location()
+	tests := []struct {
+		name      string
+		date      time.Time
+		wantFound bool
+		// wantStart is the end of astronomical twilight, or the moonset
+		// when the Moon is still up then
+		wantStart time.Time
+	}{
+		{name: "new moon", date: time.Date(2024, 6, 6, 12, 0, 0, 0, edt), wantFound: true,
+			wantStart: time.Date(2024, 6, 6, 22, 28, 0, 0, edt)},
+		{name: "waxing crescent", date: time.Date(2024, 6, 12, 12, 0, 0, 0, edt), wantFound: true,
+			wantStart: time.Date(2024, 6, 13, 0, 57, 0, 0, edt)},
+		{name: "full moon", date: time.Date(2024, 6, 21, 12, 0, 0, 0, edt)},
+	}
+
+	repo := NewTwilightRepository()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeRange := domain.TimeRange{Start: tt.date, End: tt.date.Add(24 * time.Hour)}
+			events, err := repo.GetEventsByType(context.Background(), domain.DarkWindow, timeRange, newYork)
+			if err != nil {
+				t.Fatalf("GetEventsByType() error = %v", err)
+			}
+			if !tt.wantFound {
+				if len(events) != 0 {
+					t.Errorf("GetEventsByType() = %v, want no dark window", events)
+				}
+				return
+			}
+			if len(events) != 1 {
+				t.Fatalf("GetEventsByType() got %v events, want 1", len(events))
+			}
+
+			window := events[0]
+			if diff := window.StartTime.Sub(tt.wantStart).Abs(); diff > 3*time.Minute {
+				t.Errorf("StartTime = %v, want %v", window.StartTime.In(edt), tt.wantStart)
+			}
+			if window.Twilight.MoonUpMinutes != 0 {
+				t.Errorf("MoonUpMinutes = %v, want 0", window.Twilight.MoonUpMinutes)
+			}
+			if want := window.EndTime.Sub(window.StartTime).Minutes(); window.Twilight.DurationMinutes-want > 1 || want-window.Twilight.DurationMinutes > 1 {
+				t.Errorf("DurationMinutes = %v, want %v", window.Twilight.DurationMinutes, want)
+			}
+		})
+	}
+}
+
+func TestTwilightRepository_GetEventByID(t *testing.T) {
+	repo := NewTwilightRepository()
+
+	event, err := repo.GetEventByID(context.Background(), "dark-window-2024-06-06", newYork)
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if event == nil || event.Type != domain.DarkWindow {
+		t.Errorf("GetEventByID() = %v, want the dark window", event)
+	}
+
+	event, err = repo.GetEventByID(context.Background(), "dark-window-2024-06-21", newYork)
+	if err != nil || event != nil {
+		t.Errorf("GetEventByID() = %v, %v, want nil at full moon", event, err)
+	}
+}
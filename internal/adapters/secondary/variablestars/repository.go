@@ -0,0 +1,196 @@
+package variablestars
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+)
+
+const sourceName = "Local Eclipsing Binary Predictor"
+
+//go:embed binaries.csv
+var binariesCSV []byte
+
+// A minimum is reported when the star is at least minStarAltitude degrees
+// above the horizon and the Sun is below nautical twilight at mid-eclipse
+const minStarAltitude = 10.0
+
+// binary is one row of the embedded ephemeris table
+type binary struct {
+	name         string
+	designation  string
+	variableType string
+	position     astro.Equatorial
+	maxMagnitude float64
+	minMagnitude float64
+	// epoch is the heliocentric Julian day of a primary minimum
+	epoch    float64
+	period   float64
+	duration float64
+}
+
+type variableStarRepository struct {
+	binaries []binary
+}
+
+// NewVariableStarRepository creates a repository that predicts the primary
+// minima of the eclipsing binaries of the embedded ephemeris table that can
+// be watched from the requesting observer's location
+func NewVariableStarRepository() (*variableStarRepository, error) {
+	binaries, err := parseBinaries(binariesCSV)
+	if err != nil {
+		return nil, fmt.Errorf("loading eclipsing binaries: %w", err)
+	}
+	return &variableStarRepository{binaries: binaries}, nil
+}
+
+func parseBinaries(data []byte) ([]binary, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 10
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	binaries := make([]binary, 0, len(records))
+	for _, record := range records {
+		var values [7]float64
+		for i := range values {
+			if values[i], err = strconv.ParseFloat(record[i+3], 64); err != nil {
+				return nil, fmt.Errorf("%s column %d: %w", record[0], i+3, err)
+			}
+		}
+		binaries = append(binaries, binary{
+			name:         record[0],
+			designation:  record[1],
+			variableType: record[2],
+			position:     astro.Equatorial{RA: values[0], Dec: values[1]},
+			maxMagnitude: values[2],
+			minMagnitude: values[3],
+			epoch:        values[4],
+			period:       values[5],
+			duration:     values[6],
+		})
+	}
+	return binaries, nil
+}
+
+func (r *variableStarRepository) GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	startJD := astro.JulianDay(timeRange.Start)
+	endJD := astro.JulianDay(timeRange.End)
+	site := astro.Site{Latitude: observer.Latitude, Longitude: observer.Longitude, Elevation: observer.Elevation}
+
+	var events []domain.Event
+	for _, b := range r.binaries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		b := b
+		position := func(jde float64) astro.Equatorial { return astro.ApparentPlace(b.position, jde) }
+		for _, jd := range astro.EclipsingMinima(b.position, b.epoch, b.period, startJD, endJD) {
+			starAltitude := astro.Altitude(position, jd, site)
+			sunAltitude := astro.Altitude(astro.SunApparent, jd, site)
+			if starAltitude < minStarAltitude || sunAltitude > astro.NauticalTwilightAltitude {
+				continue
+			}
+			events = append(events, minimumEvent(b, jd, starAltitude, sunAltitude, observer))
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+	return events, nil
+}
+
+func minimumEvent(b binary, jd, starAltitude, sunAltitude float64, observer domain.Observer) domain.Event {
+	details := &domain.VariableStarDetails{
+		Star:            b.name,
+		Designation:     b.designation,
+		VariableType:    b.variableType,
+		MaxMagnitude:    b.maxMagnitude,
+		MinMagnitude:    b.minMagnitude,
+		PeriodDays:      b.period,
+		Minimum:         astro.EventTime(jd),
+		DurationHours:   b.duration,
+		StarAltitudeDeg: starAltitude,
+		SunAltitudeDeg:  sunAltitude,
+	}
+	half := b.duration / 48
+
+	return domain.Event{
+		ID:    fmt.Sprintf("minimum-%s-%s", slug(b.name), details.Minimum.Format("2006-01-02")),
+		Title: fmt.Sprintf("Minimum of %s", b.name),
+		Description: fmt.Sprintf("The eclipsing binary %s (%s) fades from magnitude %.1f to %.1f, reaching minimum at %s. The eclipse lasts about %.0f hours and recurs every %.3f days",
+			b.name, b.designation, b.maxMagnitude, b.minMagnitude, observer.Clock(details.Minimum), b.duration, b.period),
+		StartTime:    astro.EventTime(jd - half),
+		EndTime:      astro.EventTime(jd + half),
+		Type:         domain.VariableStar,
+		Visibility:   fmt.Sprintf("%.0f° above the horizon at minimum", starAltitude),
+		Location:     domain.ConstellationAt(b.position, astro.J2000).Name,
+		Source:       sourceName,
+		VariableStar: details,
+	}
+}
+
+// slug turns a name such as "RZ Cassiopeiae" into "rz-cassiopeiae"
+func slug(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), "-"))
+}
+
+func (r *variableStarRepository) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	// IDs end with the date of minimum
+	if !strings.HasPrefix(id, "minimum-") || len(id) < len("2006-01-02") {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", id[len(id)-len("2006-01-02"):])
+	if err != nil {
+		return nil, nil
+	}
+
+	timeRange := domain.TimeRange{Start: date, End: date.AddDate(0, 0, 1)}
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.ID == id {
+			return &event, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *variableStarRepository) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	events, err := r.GetEvents(ctx, timeRange, observer)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []domain.Event
+	for _, event := range events {
+		if event.Type == eventType {
+			filtered = append(filtered, event)
+		}
+	}
+
+	return filtered, nil
+}
+
+func (r *variableStarRepository) Name() string {
+	return sourceName
+}
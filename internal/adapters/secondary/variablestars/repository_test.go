@@ -0,0 +1,132 @@
+package variablestars
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"astralis/internal/astro"
+	"astralis/internal/core/domain"
+)
+
+var (
+	newYork  = domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
+	santiago = domain.Observer{Latitude: -33.4489, Longitude: -70.6693, TimeZone: "America/Santiago"}
+)
+
+var october2026 = domain.TimeRange{
+	Start: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+	End:   time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func TestVariableStarRepository_GetEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		observer domain.Observer
+		want     []string
+		// wantMissing holds prefixes of IDs that must not be returned
+		wantMissing []string
+	}{
+		{
+			name:     "Algol from New York",
+			observer: newYork,
+			want: []string{
+				"minimum-algol-2026-10-09",
+				"minimum-algol-2026-10-12",
+				"minimum-algol-2026-10-15",
+				"minimum-algol-2026-10-29",
+			},
+			// The minimum of October 6 falls in daylight, and Zeta
+			// Phoenicis never rises
+			wantMissing: []string{"minimum-algol-2026-10-06", "minimum-zeta-phoenicis"},
+		},
+		{
+			name:        "southern stars from Santiago",
+			observer:    santiago,
+			want:        []string{"minimum-zeta-phoenicis-2026-10-05", "minimum-algol-2026-10-09"},
+			wantMissing: []string{"minimum-u-cephei", "minimum-rz-cassiopeiae"},
+		},
+	}
+
+	repo, err := NewVariableStarRepository()
+	if err != nil {
+		t.Fatalf("NewVariableStarRepository() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := repo.GetEvents(context.Background(), october2026, tt.observer)
+			if err != nil {
+				t.Fatalf("GetEvents() error = %v", err)
+			}
+			ids := make(map[string]bool)
+			for _, event := range events {
+				ids[event.ID] = true
+				details := event.VariableStar
+				if event.Type != domain.VariableStar || details.StarAltitudeDeg < minStarAltitude ||
+					details.SunAltitudeDeg > astro.NauticalTwilightAltitude ||
+					!event.StartTime.Before(details.Minimum) || !event.EndTime.After(details.Minimum) {
+					t.Errorf("event = %+v", event)
+				}
+			}
+			for _, id := range tt.want {
+				if !ids[id] {
+					t.Errorf("GetEvents() is missing %s", id)
+				}
+			}
+			for _, prefix := range tt.wantMissing {
+				for id := range ids {
+					if strings.HasPrefix(id, prefix) {
+						t.Errorf("GetEvents() returned %s", id)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestVariableStarRepository_GetEventByID(t *testing.T) {
+	repo, err := NewVariableStarRepository()
+	if err != nil {
+		t.Fatalf("NewVariableStarRepository() error = %v", err)
+	}
+
+	event, err := repo.GetEventByID(context.Background(), "minimum-algol-2026-10-12", newYork)
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	if event == nil {
+		t.Fatal("GetEventByID() = nil, want the minimum of Algol")
+	}
+
+	details := event.VariableStar
+	wantMinimum := time.Date(2026, 10, 12, 3, 56, 0, 0, time.UTC)
+	if d := details.Minimum.Sub(wantMinimum).Abs(); d > time.Minute {
+		t.Errorf("minimum = %v, want %v", details.Minimum, wantMinimum)
+	}
+	if d := event.EndTime.Sub(event.StartTime); d.Hours() != details.DurationHours {
+		t.Errorf("eclipse lasts %v, want %v hours", d, details.DurationHours)
+	}
+	if details.Designation != "bet Per" || details.VariableType != "EA" || details.MinMagnitude != 3.39 {
+		t.Errorf("details = %+v", details)
+	}
+	if event.Location != "Perseus" {
+		t.Errorf("Location = %q, want Perseus", event.Location)
+	}
+
+	event, err = repo.GetEventByID(context.Background(), "minimum-algol-2026-10-13", newYork)
+	if err != nil || event != nil {
+		t.Errorf("GetEventByID() = %v, %v, want nil", event, err)
+	}
+}
+
+func TestParseBinaries(t *testing.T) {
+	binaries, err := parseBinaries(binariesCSV)
+	if err != nil {
+		t.Fatalf("parseBinaries() error = %v", err)
+	}
+	if len(binaries) < 10 || binaries[0].name != "Algol" || binaries[0].period != 2.8673043 {
+		t.Errorf("parseBinaries() = %d binaries starting with %+v", len(binaries), binaries[0])
+	}
+}
@@ -0,0 +1,34 @@
+package astro
+
+import "math"
+
+const (
+	degToRad    = math.Pi / 180
+	radToDeg    = 180 / math.Pi
+	arcsecToDeg = 1.0 / 3600
+)
+
+// NormalizeDegrees reduces an angle to the range [0, 360)
+func NormalizeDegrees(a float64) float64 {
+	a = math.Mod(a, 360)
+	if a < 0 {
+		a += 360
+	}
+	return a
+}
+
+// normalizeSigned reduces an angle to the range (-180, 180]
+func normalizeSigned(a float64) float64 {
+	a = NormalizeDegrees(a)
+	if a > 180 {
+		a -= 360
+	}
+	return a
+}
+
+func sind(a float64) float64 { return math.Sin(a * degToRad) }
+func cosd(a float64) float64 { return math.Cos(a * degToRad) }
+func tand(a float64) float64 { return math.Tan(a * degToRad) }
+
+func asind(x float64) float64     { return math.Asin(x) * radToDeg }
+func atan2d(y, x float64) float64 { return math.Atan2(y, x) * radToDeg }
@@ -0,0 +1,745 @@
+package astro
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Reference values are the worked examples of Meeus, Astronomical Algorithms (2nd ed.)
+
+func TestJulianDay(t *testing.T) {
+	tests := []struct {
+		name string
+		time time.Time
+		want float64
+	}{
+		{"J2000", time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC), 2451545.0},
+		{"1987 June 19.5", time.Date(1987, 6, 19, 12, 0, 0, 0, time.UTC), 2446966.0},
+		{"1957 October 4.81", time.Date(1957, 10, 4, 19, 26, 24, 0, time.UTC), 2436116.31},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JulianDay(tt.time); math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("JulianDay() = %v, want %v", got, tt.want)
+			}
+			if got := TimeFromJulianDay(tt.want); got.Sub(tt.time).Abs() > time.Millisecond {
+				t.Errorf("TimeFromJulianDay() = %v, want %v", got, tt.time)
+			}
+			if got := EventTime(tt.want + 0.4/86400); !got.Equal(tt.time) {
+				t.Errorf("EventTime() = %v, want %v", got, tt.time)
+			}
+		})
+	}
+}
+
+func TestMeanSiderealTime(t *testing.T) {
+	// Example 12.a: 1987 April 10, 0h UT
+	got := MeanSiderealTime(2446895.5)
+	want := 13*15 + 10*0.25 + 46.3668/240
+	if math.Abs(got-want) > 1e-5 {
+		t.Errorf("MeanSiderealTime() = %v, want %v", got, want)
+	}
+}
+
+func TestDeltaT(t *testing.T) {
+	// Observed values tabulated in the Astronomical Almanac
+	tests := []struct {
+		year float64
+		want float64
+	}{
+		{1700, 8.8},
+		{1800, 13.7},
+		{1900, -2.7},
+		{1950, 29.1},
+		{1990, 56.9},
+		{2000, 63.8},
+		{2017, 68.6},
+	}
+
+	for _, tt := range tests {
+		if got := DeltaT(tt.year); math.Abs(got-tt.want) > 0.5 {
+			t.Errorf("DeltaT(%v) = %v, want %v", tt.year, got, tt.want)
+		}
+	}
+}
+
+func TestJDE(t *testing.T) {
+	tests := []struct {
+		name string
+		time time.Time
+		// want is TT - UTC in seconds
+		want float64
+	}{
+		{"1972 July 1", time.Date(1972, 7, 1, 0, 0, 0, 0, time.UTC), 43.184},
+		{"before the leap second of 1998", time.Date(1998, 12, 31, 23, 59, 59, 0, time.UTC), 63.184},
+		{"1999 January 1", time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC), 64.184},
+		{"2024 April 8", time.Date(2024, 4, 8, 18, 0, 0, 0, time.UTC), 69.184},
+		{"past the last observed Delta T", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), 69.184},
+		{"decades ahead", time.Date(2045, 1, 1, 0, 0, 0, 0, time.UTC), 69.184},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jd := JulianDay(tt.time)
+			jde := JDE(jd)
+			if got := (jde - jd) * secondsPerDay; math.Abs(got-tt.want) > 1e-3 {
+				t.Errorf("TT - UTC = %v, want %v", got, tt.want)
+			}
+			if got := UniversalTime(jde); math.Abs(got-jd)*secondsPerDay > 1e-3 {
+				t.Errorf("UniversalTime() = %v, want %v", got, jd)
+			}
+			// UTC is kept within 0.9 s of UT1
+			if got := (UT1(jd) - jd) * secondsPerDay; math.Abs(got) > 0.9 {
+				t.Errorf("UT1 - UTC = %v", got)
+			}
+		})
+	}
+
+	// Before 1972 TT follows from Delta T
+	jd := JulianDay(time.Date(1957, 10, 4, 19, 26, 24, 0, time.UTC))
+	if got := (JDE(jd) - jd) * secondsPerDay; math.Abs(got-DeltaT(1957.76)) > 0.1 {
+		t.Errorf("TT - UT in 1957 = %v, want %v", got, DeltaT(1957.76))
+	}
+}
+
+func TestTDB(t *testing.T) {
+	// TDB - TT peaks at about 1.7 ms early in October and late in March
+	for _, tt := range []struct {
+		jde  float64
+		want float64
+	}{
+		{J2000, -0.00007},
+		{2451545.0 + 90, 0.00166},
+		{2451545.0 + 273, -0.00166},
+	} {
+		if got := (TDB(tt.jde) - tt.jde) * secondsPerDay; math.Abs(got-tt.want) > 0.00005 {
+			t.Errorf("TDB - TT at %v = %v s, want %v s", tt.jde, got, tt.want)
+		}
+	}
+}
+
+func TestNutation(t *testing.T) {
+	// Example 22.a: 1987 April 10, 0h TD
+	deltaPsi, deltaEps := Nutation(2446895.5)
+	if math.Abs(deltaPsi/arcsecToDeg+3.788) > 0.5 || math.Abs(deltaEps/arcsecToDeg-9.443) > 0.5 {
+		t.Errorf("Nutation() = %v\", %v\", want -3.788\", 9.443\"", deltaPsi/arcsecToDeg, deltaEps/arcsecToDeg)
+	}
+	want := 23 + 26.0/60 + 36.850/3600
+	if got := TrueObliquity(2446895.5); math.Abs(got-want) > 0.5*arcsecToDeg {
+		t.Errorf("TrueObliquity() = %v, want %v", got, want)
+	}
+}
+
+func TestHorizontal(t *testing.T) {
+	// Example 13.b: Venus from the US Naval Observatory on 1987 April 10 at
+	// 19h21m UT, azimuth converted to be measured from the north
+	site := Site{Latitude: 38 + 55.0/60 + 17.0/3600, Longitude: -(77 + 3.0/60 + 56.0/3600)}
+	venus := Equatorial{RA: (23 + 9.0/60 + 16.641/3600) * 15, Dec: -(6 + 43.0/60 + 11.61/3600)}
+	lst := (8+34.0/60+56.853/3600)*15 + site.Longitude
+
+	got := venus.ToHorizontal(lst, site)
+	if math.Abs(got.Azimuth-248.0337) > 2e-4 || math.Abs(got.Altitude-15.1249) > 2e-4 {
+		t.Errorf("ToHorizontal() = %+v, want azimuth 248.0337, altitude 15.1249", got)
+	}
+	if back := got.ToEquatorial(lst, site); AngularSeparation(back, venus) > 1e-6 {
+		t.Errorf("ToEquatorial() = %+v, want %+v", back, venus)
+	}
+}
+
+func TestGalactic(t *testing.T) {
+	tests := []struct {
+		name string
+		eq   Equatorial
+		want Galactic
+	}{
+		// The defining points of the galactic frame in J2000
+		{"north galactic pole", Equatorial{RA: 192.85948, Dec: 27.12825}, Galactic{Latitude: 90}},
+		{"galactic centre", Equatorial{RA: 266.40510, Dec: -28.93617}, Galactic{Longitude: 0, Latitude: 0}},
+		{"M31", Equatorial{RA: 10.68471, Dec: 41.26875}, Galactic{Longitude: 121.1743, Latitude: -21.5733}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.eq.ToGalactic()
+			if math.Abs(got.Latitude-tt.want.Latitude) > 1e-3 ||
+				(tt.want.Latitude != 90 && math.Abs(normalizeSigned(got.Longitude-tt.want.Longitude)) > 1e-3) {
+				t.Errorf("ToGalactic() = %+v, want %+v", got, tt.want)
+			}
+			if back := got.ToEquatorial(); AngularSeparation(back, tt.eq) > 1e-6 {
+				t.Errorf("ToEquatorial() = %+v, want %+v", back, tt.eq)
+			}
+		})
+	}
+}
+
+func TestRefraction(t *testing.T) {
+	// Example 16.a: an apparent altitude of 0.5° is raised by 28.754'
+	got := Refraction(0.5) * 60
+	if math.Abs(got-28.754) > 0.01 {
+		t.Errorf("Refraction() = %v', want 28.754'", got)
+	}
+	// Saemundsson's formula agrees with Bennett's to within 0.1'
+	for _, apparent := range []float64{0, 0.5, 5, 30, 60} {
+		r := Refraction(apparent)
+		if diff := (RefractionFromTrue(apparent-r) - r) * 60; math.Abs(diff) > 0.1 {
+			t.Errorf("RefractionFromTrue(%v) is %v' off", apparent-r, diff)
+		}
+	}
+	if got := Refraction(90) * 60; math.Abs(got) > 0.01 {
+		t.Errorf("Refraction(90) = %v', want 0", got)
+	}
+}
+
+func TestGeomagneticLatitude(t *testing.T) {
+	tests := []struct {
+		name string
+		site Site
+		want float64
+	}{
+		{"pole", Site{Latitude: 80.65, Longitude: -72.68}, 90},
+		{"New York", Site{Latitude: 40.7128, Longitude: -74.0060}, 50.1},
+		{"Miami", Site{Latitude: 25.7617, Longitude: -80.1918}, 35.0},
+		{"Hobart", Site{Latitude: -42.8821, Longitude: 147.3272}, -49.7},
+	}
+
+	for _, tt := range tests {
+		if got := GeomagneticLatitude(tt.site); math.Abs(got-tt.want) > 0.1 {
+			t.Errorf("GeomagneticLatitude(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEarthHeliocentric(t *testing.T) {
+	// Example 25.b: 1992 October 13.0 TD
+	l, b, r := EarthHeliocentric(2448908.5)
+	if math.Abs(l-19.907372) > 1e-5 {
+		t.Errorf("EarthHeliocentric() longitude = %v, want %v", l, 19.907372)
+	}
+	if math.Abs(b-(-0.000179)) > 1e-5 {
+		t.Errorf("EarthHeliocentric() latitude = %v, want %v", b, -0.000179)
+	}
+	if math.Abs(r-0.99760775) > 1e-7 {
+		t.Errorf("EarthHeliocentric() radius = %v, want %v", r, 0.99760775)
+	}
+}
+
+func TestSunApparent(t *testing.T) {
+	// Example 25.b: 13h13m30.749s, -7°47'01.74"
+	got := SunApparent(2448908.5)
+	wantRA := (13 + 13.0/60 + 30.749/3600) * 15
+	wantDec := -(7 + 47.0/60 + 1.74/3600)
+	if math.Abs(got.RA-wantRA) > 2*arcsecToDeg {
+		t.Errorf("SunApparent() RA = %v, want %v", got.RA, wantRA)
+	}
+	if math.Abs(got.Dec-wantDec) > 2*arcsecToDeg {
+		t.Errorf("SunApparent() Dec = %v, want %v", got.Dec, wantDec)
+	}
+}
+
+func TestMoonGeocentric(t *testing.T) {
+	// Example 47.a: 1992 April 12, 0h TD
+	pos, dist := MoonGeocentric(2448724.5)
+	if math.Abs(pos.Longitude-133.162655) > 1e-6 {
+		t.Errorf("MoonGeocentric() longitude = %v, want %v", pos.Longitude, 133.162655)
+	}
+	if math.Abs(pos.Latitude-(-3.229126)) > 1e-6 {
+		t.Errorf("MoonGeocentric() latitude = %v, want %v", pos.Latitude, -3.229126)
+	}
+	if math.Abs(dist-368409.7) > 0.1 {
+		t.Errorf("MoonGeocentric() distance = %v, want %v", dist, 368409.7)
+	}
+
+	eq := MoonApparent(2448724.5)
+	if math.Abs(eq.RA-134.688470) > 2*arcsecToDeg {
+		t.Errorf("MoonApparent() RA = %v, want %v", eq.RA, 134.688470)
+	}
+	if math.Abs(eq.Dec-13.768368) > 2*arcsecToDeg {
+		t.Errorf("MoonApparent() Dec = %v, want %v", eq.Dec, 13.768368)
+	}
+}
+
+func TestHorizonEvents(t *testing.T) {
+	// Example 15.a: Venus at Boston on 1988 March 20, using the published
+	// apparent positions for 0h TD on March 19, 20 and 21
+	ra := [3]float64{40.68021, 41.73129, 42.78204}
+	dec := [3]float64{18.04761, 18.44092, 18.82742}
+	day := 2447240.5
+	venus := func(jde float64) Equatorial {
+		n := jde - day
+		interp := func(y [3]float64) float64 {
+			return y[1] + n/2*(y[2]-y[0]+n*(y[2]-2*y[1]+y[0]))
+		}
+		return Equatorial{RA: interp(ra), Dec: interp(dec)}
+	}
+	boston := Site{Latitude: 42.3333, Longitude: -71.0833}
+
+	want := map[HorizonEventKind]time.Time{
+		Setting:      time.Date(1988, 3, 20, 2, 54, 40, 0, time.UTC),
+		Rising:       time.Date(1988, 3, 20, 12, 25, 26, 0, time.UTC),
+		UpperTransit: time.Date(1988, 3, 20, 19, 40, 30, 0, time.UTC),
+	}
+
+	events := HorizonEvents(venus, StarStandardAltitude, boston, day, day+1)
+	if len(events) != len(want) {
+		t.Fatalf("HorizonEvents() got %v events, want %v", len(events), len(want))
+	}
+	for _, event := range events {
+		got := TimeFromJulianDay(event.JD)
+		if diff := got.Sub(want[event.Kind]).Abs(); diff > time.Minute {
+			t.Errorf("HorizonEvents() kind %v at %v, want %v", event.Kind, got, want[event.Kind])
+		}
+	}
+}
+
+func TestAngularSeparation(t *testing.T) {
+	// Example 17.a: Arcturus and Spica are 32°47.5' apart
+	arcturus := Equatorial{RA: 213.9154, Dec: 19.1825}
+	spica := Equatorial{RA: 201.2983, Dec: -11.1614}
+	if got := AngularSeparation(arcturus, spica); math.Abs(got-32.7930) > 1e-3 {
+		t.Errorf("AngularSeparation() = %v, want %v", got, 32.7930)
+	}
+}
+
+func TestMoonPhases(t *testing.T) {
+	// Example 49.a: new moon of 1977 February at JDE 2443192.65118
+	start := JulianDay(time.Date(1977, 2, 15, 0, 0, 0, 0, time.UTC))
+	phases := MoonPhases(start, start+5)
+	if len(phases) != 1 || phases[0].Quarter != NewMoon {
+		t.Fatalf("MoonPhases() = %v, want a single new moon", phases)
+	}
+	if got := JDE(phases[0].JD); math.Abs(got-2443192.65118) > 1.0/1440 {
+		t.Errorf("MoonPhases() new moon at JDE %v, want %v", got, 2443192.65118)
+	}
+	if got := MoonIllumination(JDE(phases[0].JD)); got > 0.01 {
+		t.Errorf("MoonIllumination() at new moon = %v, want 0", got)
+	}
+}
+
+func TestSolarEclipses(t *testing.T) {
+	start := JulianDay(time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC))
+	end := JulianDay(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	want := map[string]EclipseKind{
+		"2017-02-26": AnnularEclipse,
+		"2017-08-21": TotalEclipse,
+		"2020-06-21": AnnularEclipse,
+		"2020-12-14": TotalEclipse,
+		"2022-10-25": PartialEclipse,
+		"2023-04-20": HybridEclipse,
+		"2023-10-14": AnnularEclipse,
+		"2024-04-08": TotalEclipse,
+	}
+
+	found := map[string]EclipseKind{}
+	for _, eclipse := range SolarEclipses(start, end) {
+		found[TimeFromJulianDay(eclipse.JD).Format("2006-01-02")] = eclipse.Kind
+	}
+	if len(found) != 18 {
+		t.Errorf("SolarEclipses() found %v eclipses, want 18", len(found))
+	}
+	for date, kind := range want {
+		if got, ok := found[date]; !ok || got != kind {
+			t.Errorf("SolarEclipses() on %v = %v (found %v), want %v", date, got, ok, kind)
+		}
+	}
+}
+
+func TestSolarEclipse_Gamma(t *testing.T) {
+	// Example 54.a: the partial eclipse of 1993 May 21
+	start := JulianDay(time.Date(1993, 5, 1, 0, 0, 0, 0, time.UTC))
+	eclipses := SolarEclipses(start, start+30)
+	if len(eclipses) != 1 {
+		t.Fatalf("SolarEclipses() = %v, want one eclipse", eclipses)
+	}
+	if got := eclipses[0]; got.Kind != PartialEclipse || math.Abs(got.Gamma-1.1348) > 1e-3 || math.Abs(got.U-0.0097) > 1e-3 {
+		t.Errorf("SolarEclipses() = %+v, want partial with gamma 1.1348 and u 0.0097", got)
+	}
+}
+
+func TestSolarEclipse_LocalCircumstances(t *testing.T) {
+	// Totality at Dallas on 2024 April 8 ran from 18:40:44 to 18:44:35 UTC
+	start := JulianDay(time.Date(2024, 4, 8, 0, 0, 0, 0, time.UTC))
+	eclipses := SolarEclipses(start, start+1)
+	if len(eclipses) != 1 {
+		t.Fatalf("SolarEclipses() = %v, want one eclipse", eclipses)
+	}
+
+	local, ok := eclipses[0].LocalCircumstances(Site{Latitude: 32.7767, Longitude: -96.7970})
+	if !ok || local.Kind != TotalEclipse {
+		t.Fatalf("LocalCircumstances() = %+v, want a total eclipse", local)
+	}
+	wantC2 := time.Date(2024, 4, 8, 18, 40, 44, 0, time.UTC)
+	wantC3 := time.Date(2024, 4, 8, 18, 44, 35, 0, time.UTC)
+	if diff := TimeFromJulianDay(local.C2).Sub(wantC2).Abs(); diff > 15*time.Second {
+		t.Errorf("LocalCircumstances() C2 = %v, want %v", TimeFromJulianDay(local.C2), wantC2)
+	}
+	if diff := TimeFromJulianDay(local.C3).Sub(wantC3).Abs(); diff > 15*time.Second {
+		t.Errorf("LocalCircumstances() C3 = %v, want %v", TimeFromJulianDay(local.C3), wantC3)
+	}
+	if local.Obscuration != 1 || local.Magnitude < 1 {
+		t.Errorf("LocalCircumstances() magnitude %v obscuration %v, want total", local.Magnitude, local.Obscuration)
+	}
+}
+
+func TestLunarEclipses(t *testing.T) {
+	start := JulianDay(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	end := JulianDay(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	want := []struct {
+		date string
+		kind EclipseKind
+	}{
+		{"2022-05-16", TotalEclipse},
+		{"2022-11-08", TotalEclipse},
+		{"2023-05-05", PenumbralEclipse},
+		{"2023-10-28", PartialEclipse},
+		{"2024-03-25", PenumbralEclipse},
+		{"2024-09-18", PartialEclipse},
+		{"2025-03-14", TotalEclipse},
+		{"2025-09-07", TotalEclipse},
+	}
+
+	eclipses := LunarEclipses(start, end)
+	if len(eclipses) != len(want) {
+		t.Fatalf("LunarEclipses() found %v eclipses, want %v", len(eclipses), len(want))
+	}
+	for i, tt := range want {
+		got := eclipses[i]
+		if date := TimeFromJulianDay(got.JD).Format("2006-01-02"); date != tt.date || got.Kind != tt.kind {
+			t.Errorf("LunarEclipses()[%d] = %v %v, want %v %v", i, date, got.Kind, tt.date, tt.kind)
+		}
+		if !(got.P1 < got.JD && got.JD < got.P4) {
+			t.Errorf("LunarEclipses()[%d] contacts out of order", i)
+		}
+	}
+}
+
+func TestPlanetApparent(t *testing.T) {
+	// Example 33.a: Venus on 1992 December 20, 0h TD, at 21h04m41.454s and
+	// -18°53'16.84". The approximate JPL elements are good to about 30".
+	got := PlanetApparent(Venus, 2448976.5)
+	want := Equatorial{RA: 316.172725, Dec: -18.888011}
+	if sep := AngularSeparation(got, want); sep > 30*arcsecToDeg {
+		t.Errorf("PlanetApparent() = %v, want %v (off by %.1f\")", got, want, sep*3600)
+	}
+}
+
+func TestPrecess(t *testing.T) {
+	// Example 21.b: theta Persei from J2000 to 2028 November 13.19 TD
+	got := Precess(Equatorial{RA: 41.054063, Dec: 49.227750}, 2462088.69)
+	want := Equatorial{RA: 41.547214, Dec: 49.348483}
+	if sep := AngularSeparation(got, want); sep > 0.5*arcsecToDeg {
+		t.Errorf("Precess() = %v, want %v", got, want)
+	}
+}
+
+func TestSeasons(t *testing.T) {
+	// Example 27.a: June solstice of 1962 at JDE 2437837.39245
+	seasons := Seasons(2437830, 2437840)
+	if len(seasons) != 1 || seasons[0].Kind != JuneSolstice {
+		t.Fatalf("Seasons() = %v, want the June solstice", seasons)
+	}
+	if diff := math.Abs(JDE(seasons[0].JD) - 2437837.39245); diff > 1.0/(24*60) {
+		t.Errorf("Seasons() JDE = %v, want 2437837.39245", JDE(seasons[0].JD))
+	}
+}
+
+func TestSGP4(t *testing.T) {
+	// Verification cases of Vallado et al., "Revisiting Spacetrack Report #3"
+	// (SGP4-VER.TLE), with positions in km and velocities in km/s
+	tests := []struct {
+		name         string
+		line1, line2 string
+		minutes      float64
+		wantR, wantV Vector
+	}{
+		{
+			name:    "00005 at epoch",
+			line1:   "1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753",
+			line2:   "2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667",
+			minutes: 0,
+			wantR:   Vector{7022.46529266, -1400.08296755, 0.03995155},
+			wantV:   Vector{1.893841015, 6.405893759, 4.534807250},
+		},
+		{
+			name:    "00005 after six hours",
+			line1:   "1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753",
+			line2:   "2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667",
+			minutes: 360,
+			wantR:   Vector{-7154.03120202, -3783.17682504, -3536.19412294},
+			wantV:   Vector{4.741887409, -4.151817765, -2.093935425},
+		},
+		{
+			name:    "06251 low perigee with drag",
+			line1:   "1 06251U 62025E   06176.82412014  .00008885  00000-0  12808-3 0  3985",
+			line2:   "2 06251  58.0579  54.0425 0030035 139.1568 221.1854 15.56387291  6774",
+			minutes: 0,
+			wantR:   Vector{3988.31022699, 5498.96657235, 0.90055879},
+			wantV:   Vector{-3.290032738, 2.357652820, 6.496623475},
+		},
+		{
+			name:    "88888 Spacetrack Report #3 SGP4 test",
+			line1:   "1 88888U          80275.98708465  .00073094  13844-3  66816-4 0    8",
+			line2:   "2 88888  72.8435 115.9689 0086731  52.6988 110.5714 16.05824518  105",
+			minutes: 0,
+			wantR:   Vector{2328.96975262, -5995.22051338, 1719.97297192},
+			wantV:   Vector{2.912073281, -0.983417956, -7.090816210},
+		},
+		{
+			name:    "08195 Molniya, deep space with 12 hour resonance",
+			line1:   "1 08195U 75081A   06176.33215444  .00000099  00000-0  11873-3 0   813",
+			line2:   "2 08195  64.1586 279.0717 6877146 264.7651  20.2257  2.00491383225656",
+			minutes: 0,
+			wantR:   Vector{2349.89483350, -14785.93811562, 0.02119378},
+			wantV:   Vector{2.721488096, -3.256811655, 4.498416672},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tle, err := ParseTLE("", tt.line1, tt.line2)
+			if err != nil {
+				t.Fatalf("ParseTLE() error = %v", err)
+			}
+			sat, err := NewSatellite(tle)
+			if err != nil {
+				t.Fatalf("NewSatellite() error = %v", err)
+			}
+			r, v, err := sat.Propagate(tt.minutes)
+			if err != nil {
+				t.Fatalf("Propagate() error = %v", err)
+			}
+			if d := r.Sub(tt.wantR).Length(); d > 1e-5 {
+				t.Errorf("Propagate() r = %v, want %v", r, tt.wantR)
+			}
+			if d := v.Sub(tt.wantV).Length(); d > 1e-8 {
+				t.Errorf("Propagate() v = %v, want %v", v, tt.wantV)
+			}
+		})
+	}
+}
+
+func TestParseTLEs(t *testing.T) {
+	input := `ISS (ZARYA)
+1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927
+2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537
+1 00005U 58002B   00179.78495062  .00000023  00000-0  28098-4 0  4753
+2 00005  34.2682 348.7242 1859667 331.7664  19.3264 10.82419157413667
+`
+	tles, err := ParseTLEs(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseTLEs() error = %v", err)
+	}
+	if len(tles) != 2 {
+		t.Fatalf("ParseTLEs() returned %d element sets, want 2", len(tles))
+	}
+
+	iss := tles[0]
+	if iss.Name != "ISS (ZARYA)" || iss.CatalogNumber != 25544 {
+		t.Errorf("ParseTLEs()[0] = %v %v, want ISS (ZARYA) 25544", iss.Name, iss.CatalogNumber)
+	}
+	epoch := time.Date(2008, 9, 20, 12, 25, 40, 104000000, time.UTC)
+	if got := TimeFromJulianDay(iss.EpochJD); got.Sub(epoch).Abs() > time.Millisecond {
+		t.Errorf("ParseTLEs()[0] epoch = %v, want %v", got, epoch)
+	}
+	if math.Abs(iss.BStar-(-0.11606e-4)) > 1e-12 || math.Abs(iss.Eccentricity-0.0006703) > 1e-12 {
+		t.Errorf("ParseTLEs()[0] bstar, eccentricity = %v, %v", iss.BStar, iss.Eccentricity)
+	}
+	if tles[1].Name != "5" {
+		t.Errorf("ParseTLEs()[1] name = %q, want the catalog number", tles[1].Name)
+	}
+
+	if _, err := ParseTLEs(strings.NewReader("ISS\n1 25544U 98067A   08264.51782528\n")); err == nil {
+		t.Error("ParseTLEs() with a truncated element set, want error")
+	}
+}
+
+func TestSatellite_Passes(t *testing.T) {
+	tle, err := ParseTLE("ISS (ZARYA)",
+		"1 25544U 98067A   08264.51782528 -.00002182  00000-0 -11606-4 0  2927",
+		"2 25544  51.6416 247.4627 0006703 130.5360 325.0288 15.72125391563537")
+	if err != nil {
+		t.Fatalf("ParseTLE() error = %v", err)
+	}
+	sat, err := NewSatellite(tle)
+	if err != nil {
+		t.Fatalf("NewSatellite() error = %v", err)
+	}
+
+	site := Site{Latitude: 40.7128, Longitude: -74.0060}
+	passes, err := sat.Passes(site, tle.EpochJD, tle.EpochJD+1, 10)
+	if err != nil {
+		t.Fatalf("Passes() error = %v", err)
+	}
+	if len(passes) == 0 {
+		t.Fatal("Passes() found no passes")
+	}
+
+	var visible int
+	for _, p := range passes {
+		if !(p.Rise < p.Culmination && p.Culmination < p.Set) {
+			t.Errorf("pass at %v out of order", TimeFromJulianDay(p.Rise))
+		}
+		if d := p.Set - p.Rise; d > 15.0/(24*60) {
+			t.Errorf("pass at %v lasts %.1f min", TimeFromJulianDay(p.Rise), d*24*60)
+		}
+		if p.CulminationPosition.Altitude < 10 || math.Abs(p.RisePosition.Altitude) > 0.01 || math.Abs(p.SetPosition.Altitude) > 0.01 {
+			t.Errorf("pass at %v altitudes %v", TimeFromJulianDay(p.Rise), p)
+		}
+		if p.Visible {
+			visible++
+			if sun := Altitude(SunApparent, p.Culmination, site); sun > 0 {
+				t.Errorf("pass at %v visible in daylight", TimeFromJulianDay(p.Rise))
+			}
+		}
+	}
+	// Late September evenings in New York bring sunlit passes after dusk,
+	// while passes after midnight are in the Earth's shadow
+	if visible == 0 || visible == len(passes) {
+		t.Errorf("Passes() found %d visible of %d, want some but not all", visible, len(passes))
+	}
+}
+
+func TestSmallBodyAt(t *testing.T) {
+	// Example 33.b: comet Encke on 1990 October 6.0 TD at 10h34m14.2s and
+	// +19°09'31"
+	encke := OrbitalElements{
+		PerihelionDistance: 2.2091404 * (1 - 0.8502196),
+		Eccentricity:       0.8502196,
+		Inclination:        11.94524,
+		Node:               334.75006,
+		ArgPerihelion:      186.23352,
+		PerihelionJDE:      2448192.5 + 0.54502,
+	}
+	got := SmallBodyAt(encke, CometMagnitude, 0, 0, 2448170.5)
+	want := Equatorial{RA: 158.559167, Dec: 19.158611}
+	if sep := AngularSeparation(got.Position, want); sep > 5*arcsecToDeg {
+		t.Errorf("SmallBodyAt() = %v, want %v (off by %.1f\")", got.Position, want, sep*3600)
+	}
+	if math.Abs(got.EarthDistance-0.82427) > 1e-4 {
+		t.Errorf("SmallBodyAt() distance = %v, want 0.82427", got.EarthDistance)
+	}
+	if math.Abs(encke.Period()-1199.3) > 0.1 {
+		t.Errorf("Period() = %v, want 1199.3", encke.Period())
+	}
+}
+
+func TestOccultations(t *testing.T) {
+	// The occultation of Aldebaran on the evening of 2016 January 19 seen
+	// from New York, with the waxing gibbous Moon's dark limb leading
+	site := Site{Latitude: 40.7128, Longitude: -74.0060}
+	aldebaran := func(jde float64) Equatorial { return ApparentPlace(Equatorial{RA: 68.98016, Dec: 16.50930}, jde) }
+	start := JulianDay(time.Date(2016, 1, 19, 0, 0, 0, 0, time.UTC))
+
+	got := Occultations([]PositionFunc{aldebaran}, site, start, start+2)
+	if len(got) != 1 {
+		t.Fatalf("Occultations() found %d, want 1", len(got))
+	}
+	o := got[0]
+	wantD := time.Date(2016, 1, 20, 2, 31, 0, 0, time.UTC)
+	wantR := time.Date(2016, 1, 20, 3, 43, 0, 0, time.UTC)
+	if d := TimeFromJulianDay(o.Disappearance).Sub(wantD).Abs(); d > 2*time.Minute {
+		t.Errorf("disappearance = %v, want %v", TimeFromJulianDay(o.Disappearance), wantD)
+	}
+	if d := TimeFromJulianDay(o.Reappearance).Sub(wantR).Abs(); d > 2*time.Minute {
+		t.Errorf("reappearance = %v, want %v", TimeFromJulianDay(o.Reappearance), wantR)
+	}
+	if o.DisappearanceLimb != DarkLimb || o.ReappearanceLimb != BrightLimb {
+		t.Errorf("limbs = %v, %v, want dark then bright", o.DisappearanceLimb, o.ReappearanceLimb)
+	}
+
+	// At the contacts the star lies on the Moon's limb
+	for _, jd := range []float64{o.Disappearance, o.Reappearance} {
+		moon, radius, _ := moonTopocentric(jd, site)
+		if sep := AngularSeparation(moon, aldebaran(JDE(jd))) - radius; math.Abs(sep) > 0.1*arcsecToDeg {
+			t.Errorf("separation from the limb at %v = %.2f\"", TimeFromJulianDay(jd), sep*3600)
+		}
+	}
+
+	// A lunation later the Moon misses the star as seen from New York
+	if got := Occultations([]PositionFunc{aldebaran}, site, start+26, start+29); len(got) != 0 {
+		t.Errorf("Occultations() = %v, want none", got)
+	}
+}
+
+func TestGalileanMoonPositions(t *testing.T) {
+	// Meeus example 44.b, 1992 December 16 at 0h UT. X is off by up to 0.02
+	// radii, Jupiter's place from the mean elements being a few arcminutes
+	// off the VSOP87 one used there.
+	want := [4]JovianOffset{
+		{X: -3.4502, Y: 0.2137},
+		{X: 7.4418, Y: 0.2752},
+		{X: 1.2011, Y: 0.5900},
+		{X: 7.0720, Y: 1.0291},
+	}
+	got := GalileanMoonPositions(2448972.50068)
+	for i, moon := range GalileanMoons {
+		if math.Abs(got[i].X-want[i].X) > 0.03 || math.Abs(got[i].Y-want[i].Y) > 0.002 {
+			t.Errorf("%v = (%.4f, %.4f), want (%.4f, %.4f)", moon, got[i].X, got[i].Y, want[i].X, want[i].Y)
+		}
+	}
+}
+
+func TestJovianPhenomena(t *testing.T) {
+	// A month after opposition Io disappears behind Jupiter and enters its
+	// shadow while still hidden, reappearing from eclipse beside the disk
+	start := JulianDay(time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC))
+	var io []JovianPhenomenon
+	for _, p := range JovianPhenomena(start, start+2) {
+		if p.Moon == Io {
+			io = append(io, p)
+		}
+	}
+
+	wantKinds := []JovianPhenomenonKind{MoonOccultation, MoonEclipse, MoonTransit, ShadowTransit}
+	if len(io) != len(wantKinds) {
+		t.Fatalf("JovianPhenomena() found %d phenomena of Io, want %d", len(io), len(wantKinds))
+	}
+	for i, p := range io {
+		if p.Kind != wantKinds[i] {
+			t.Errorf("phenomenon %d kind = %v, want %v", i, p.Kind, wantKinds[i])
+		}
+		if d := (p.End - p.Start) * 24; d < 2 || d > 2.5 {
+			t.Errorf("phenomenon %d lasts %.2f h, want about 2.3", i, d)
+		}
+	}
+	if io[0].StartHidden || !io[0].EndHidden || !io[1].StartHidden || io[1].EndHidden {
+		t.Errorf("hidden contacts = %+v, %+v", io[0], io[1])
+	}
+}
+
+func TestHeliocentricCorrection(t *testing.T) {
+	// Algol lies at ecliptic longitude 56°, latitude 22°: in mid-November the
+	// Earth is between it and the Sun and its light reaches us first, while
+	// in May it arrives late
+	algol := Equatorial{RA: 47.0422, Dec: 40.9556}
+	november := JulianDay(time.Date(2026, 11, 17, 0, 0, 0, 0, time.UTC))
+	may := JulianDay(time.Date(2026, 5, 17, 0, 0, 0, 0, time.UTC))
+
+	if got := HeliocentricCorrection(algol, november) * 24 * 60; got < 7.3 || got > 8 {
+		t.Errorf("HeliocentricCorrection(November) = %.2f min, want about 7.7", got)
+	}
+	if got := HeliocentricCorrection(algol, may) * 24 * 60; got > -7.3 || got < -8 {
+		t.Errorf("HeliocentricCorrection(May) = %.2f min, want about -7.7", got)
+	}
+}
+
+func TestEclipsingMinima(t *testing.T) {
+	algol := Equatorial{RA: 47.0422, Dec: 40.9556}
+	const epoch, period = 2445641.5135, 2.8673043
+
+	start := JulianDay(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	minima := EclipsingMinima(algol, epoch, period, start, start+30)
+	if len(minima) != 10 && len(minima) != 11 {
+		t.Fatalf("EclipsingMinima() found %d minima in 30 days, want 10 or 11", len(minima))
+	}
+	for i, jd := range minima {
+		cycles := (jd + HeliocentricCorrection(algol, jd) - epoch) / period
+		if math.Abs(cycles-math.Round(cycles)) > 1e-4 {
+			t.Errorf("minimum %d at JD %.5f is %.5f cycles from the epoch", i, jd, cycles)
+		}
+	}
+}
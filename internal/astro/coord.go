@@ -0,0 +1,126 @@
+package astro
+
+import "math"
+
+// Ecliptic holds ecliptic longitude and latitude in degrees
+type Ecliptic struct {
+	Longitude float64
+	Latitude  float64
+}
+
+// Equatorial holds right ascension and declination in degrees
+type Equatorial struct {
+	RA  float64
+	Dec float64
+}
+
+// Horizontal holds azimuth (measured from north through east) and altitude in degrees
+type Horizontal struct {
+	Azimuth  float64
+	Altitude float64
+}
+
+// Galactic holds galactic longitude and latitude in degrees
+type Galactic struct {
+	Longitude float64
+	Latitude  float64
+}
+
+// The north galactic pole and the galactic longitude of the north celestial
+// pole in the J2000 frame
+const (
+	galacticPoleRA  = 192.85948
+	galacticPoleDec = 27.12825
+	galacticNCP     = 122.93192
+)
+
+// Site is a geographic position on the Earth, longitudes positive east of
+// Greenwich and elevation in metres above sea level
+type Site struct {
+	Latitude  float64
+	Longitude float64
+	Elevation float64
+}
+
+// geocentric returns rho*sin(phi') and rho*cos(phi'), the observer's
+// position relative to the centre of the Earth in equatorial radii
+func (s Site) geocentric() (rhoSin, rhoCos float64) {
+	const ba = 0.99664719 // polar over equatorial radius
+	u := math.Atan(ba * tand(s.Latitude))
+	h := s.Elevation / (EarthRadiusKm * 1000)
+	return ba*math.Sin(u) + h*sind(s.Latitude), math.Cos(u) + h*cosd(s.Latitude)
+}
+
+// Topocentric converts an apparent geocentric position at distance km to the
+// position seen from the site, given the local sidereal time in degrees. It
+// returns the topocentric position and distance in kilometres.
+func (s Site) Topocentric(eq Equatorial, distance, lst float64) (Equatorial, float64) {
+	x := distance * cosd(eq.Dec) * cosd(eq.RA)
+	y := distance * cosd(eq.Dec) * sind(eq.RA)
+	z := distance * sind(eq.Dec)
+
+	rhoSin, rhoCos := s.geocentric()
+	x -= EarthRadiusKm * rhoCos * cosd(lst)
+	y -= EarthRadiusKm * rhoCos * sind(lst)
+	z -= EarthRadiusKm * rhoSin
+
+	d := math.Sqrt(x*x + y*y + z*z)
+	return Equatorial{RA: NormalizeDegrees(atan2d(y, x)), Dec: asind(z / d)}, d
+}
+
+// ToEquatorial converts ecliptic coordinates using the obliquity of the ecliptic eps
+func (e Ecliptic) ToEquatorial(eps float64) Equatorial {
+	ra := atan2d(sind(e.Longitude)*cosd(eps)-tand(e.Latitude)*sind(eps), cosd(e.Longitude))
+	dec := asind(sind(e.Latitude)*cosd(eps) + cosd(e.Latitude)*sind(eps)*sind(e.Longitude))
+	return Equatorial{RA: NormalizeDegrees(ra), Dec: dec}
+}
+
+// ToHorizontal converts equatorial coordinates for a site, given the local
+// apparent sidereal time in degrees
+func (e Equatorial) ToHorizontal(lst float64, site Site) Horizontal {
+	h := HourAngle(lst, e.RA)
+	alt := asind(sind(site.Latitude)*sind(e.Dec) + cosd(site.Latitude)*cosd(e.Dec)*cosd(h))
+	az := atan2d(sind(h), cosd(h)*sind(site.Latitude)-tand(e.Dec)*cosd(site.Latitude))
+	return Horizontal{Azimuth: NormalizeDegrees(az + 180), Altitude: alt}
+}
+
+// ToEquatorial converts horizontal coordinates for a site back to
+// equatorial ones, given the local apparent sidereal time in degrees
+func (h Horizontal) ToEquatorial(lst float64, site Site) Equatorial {
+	// Meeus measures azimuth from the south
+	az := h.Azimuth - 180
+	ha := atan2d(sind(az), cosd(az)*sind(site.Latitude)+tand(h.Altitude)*cosd(site.Latitude))
+	dec := asind(sind(site.Latitude)*sind(h.Altitude) - cosd(site.Latitude)*cosd(h.Altitude)*cosd(az))
+	return Equatorial{RA: NormalizeDegrees(lst - ha), Dec: dec}
+}
+
+// ToGalactic converts J2000 equatorial coordinates to galactic ones
+func (e Equatorial) ToGalactic() Galactic {
+	da := e.RA - galacticPoleRA
+	b := asind(sind(e.Dec)*sind(galacticPoleDec) + cosd(e.Dec)*cosd(galacticPoleDec)*cosd(da))
+	l := galacticNCP - atan2d(cosd(e.Dec)*sind(da), sind(e.Dec)*cosd(galacticPoleDec)-cosd(e.Dec)*sind(galacticPoleDec)*cosd(da))
+	return Galactic{Longitude: NormalizeDegrees(l), Latitude: b}
+}
+
+// ToEquatorial converts galactic coordinates to J2000 equatorial ones
+func (g Galactic) ToEquatorial() Equatorial {
+	dl := galacticNCP - g.Longitude
+	dec := asind(sind(g.Latitude)*sind(galacticPoleDec) + cosd(g.Latitude)*cosd(galacticPoleDec)*cosd(dl))
+	ra := galacticPoleRA + atan2d(cosd(g.Latitude)*sind(dl), sind(g.Latitude)*cosd(galacticPoleDec)-cosd(g.Latitude)*sind(galacticPoleDec)*cosd(dl))
+	return Equatorial{RA: NormalizeDegrees(ra), Dec: dec}
+}
+
+// HourAngle returns the hour angle in degrees in the range (-180, 180]
+func HourAngle(lst, ra float64) float64 {
+	return normalizeSigned(lst - ra)
+}
+
+// AngularSeparation returns the angle in degrees between two equatorial positions
+func AngularSeparation(a, b Equatorial) float64 {
+	// Haversine form stays accurate for very small separations
+	dRA := (b.RA - a.RA) * degToRad
+	dDec := (b.Dec - a.Dec) * degToRad
+	h := math.Pow(math.Sin(dDec/2), 2) +
+		cosd(a.Dec)*cosd(b.Dec)*math.Pow(math.Sin(dRA/2), 2)
+	return 2 * math.Asin(math.Min(1, math.Sqrt(h))) * radToDeg
+}
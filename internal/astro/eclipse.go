@@ -0,0 +1,303 @@
+package astro
+
+import "math"
+
+// EclipseKind classifies a solar or lunar eclipse
+type EclipseKind int
+
+const (
+	PartialEclipse EclipseKind = iota
+	TotalEclipse
+	AnnularEclipse
+	HybridEclipse
+	PenumbralEclipse
+)
+
+// SolarEclipse is a solar eclipse found by the global search
+type SolarEclipse struct {
+	Kind EclipseKind
+	// JD is the Julian day of greatest eclipse on the UTC time scale
+	JD float64
+	// Gamma is the least distance of the shadow axis from the centre of the
+	// Earth in equatorial radii, and U the radius of the umbral cone in the
+	// fundamental plane
+	Gamma float64
+	U     float64
+}
+
+// LunarEclipse is a lunar eclipse with its geocentric circumstances
+type LunarEclipse struct {
+	Kind EclipseKind
+	// JD is the Julian day of greatest eclipse on the UTC time scale
+	JD                 float64
+	Gamma              float64
+	PenumbralMagnitude float64
+	UmbralMagnitude    float64
+	// Contacts holds the Julian days (UTC) of the penumbral (P1, P4),
+	// umbral (U1, U4) and total (U2, U3) contacts. Contacts that do not
+	// occur are zero.
+	P1, U1, U2, U3, U4, P4 float64
+}
+
+// LocalSolarEclipse holds the circumstances of a solar eclipse at a site
+type LocalSolarEclipse struct {
+	Kind EclipseKind
+	// C1 to C4 are the Julian days (UTC) of the contacts; C2 and C3 are zero
+	// unless the eclipse is total or annular at the site
+	C1, C2, C3, C4 float64
+	// Max is the Julian day of greatest eclipse at the site
+	Max float64
+	// Magnitude is the fraction of the Sun's diameter covered at maximum
+	// and Obscuration the fraction of its area
+	Magnitude   float64
+	Obscuration float64
+	// SunAltitude is the altitude of the Sun at maximum eclipse
+	SunAltitude float64
+}
+
+const (
+	meanLunation = 29.530588861
+	// moonEarthRadius is the ratio of the Moon's radius to the Earth's
+	moonEarthRadius = 0.272481
+)
+
+// lunation holds the Meeus chapter 54 quantities for a new or full moon
+type lunation struct {
+	jde, gamma, u float64
+	mp            float64
+}
+
+// lunationCircumstances evaluates a new moon (integer k) or full moon (k
+// ending in .5) counted from the new moon of 2000 January 6. ok is false
+// when the Moon is too far from a node for any eclipse.
+func lunationCircumstances(k float64) (lunation, bool) {
+	t := k / 1236.85
+	t2, t3, t4 := t*t, t*t*t, t*t*t*t
+
+	jde := 2451550.09766 + meanLunation*k + 0.00015437*t2 - 0.000000150*t3 + 0.00000000073*t4
+	m := 2.5534 + 29.10535670*k - 0.0000014*t2 - 0.00000011*t3
+	mp := 201.5643 + 385.81693528*k + 0.0107582*t2 + 0.00001238*t3 - 0.000000058*t4
+	f := 160.7108 + 390.67050284*k - 0.0016118*t2 - 0.00000227*t3 + 0.000000011*t4
+	omega := 124.7746 - 1.56375588*k + 0.0020672*t2 + 0.00000215*t3
+	e := 1 - 0.002516*t - 0.0000074*t2
+
+	if math.Abs(sind(f)) > 0.36 {
+		return lunation{}, false
+	}
+
+	f1 := f - 0.02665*sind(omega)
+	a1 := 299.77 + 0.107408*k - 0.009173*t2
+
+	newMoon := k == math.Floor(k)
+	if newMoon {
+		jde += -0.4075*sind(mp) + 0.1721*e*sind(m)
+	} else {
+		jde += -0.4065*sind(mp) + 0.1727*e*sind(m)
+	}
+	jde += 0.0161*sind(2*mp) - 0.0097*sind(2*f1) + 0.0073*e*sind(mp-m) -
+		0.0050*e*sind(mp+m) - 0.0023*sind(mp-2*f1) + 0.0021*e*sind(2*m) +
+		0.0012*sind(mp+2*f1) + 0.0006*e*sind(2*mp+m) - 0.0004*sind(3*mp) -
+		0.0003*e*sind(m+2*f1) + 0.0003*sind(a1) - 0.0002*e*sind(m-2*f1) -
+		0.0002*e*sind(2*mp-m) - 0.0002*sind(omega)
+
+	p := 0.2070*e*sind(m) + 0.0024*e*sind(2*m) - 0.0392*sind(mp) + 0.0116*sind(2*mp) -
+		0.0073*e*sind(mp+m) + 0.0067*e*sind(mp-m) + 0.0118*sind(2*f1)
+	q := 5.2207 - 0.0048*e*cosd(m) + 0.0020*e*cosd(2*m) - 0.3299*cosd(mp) -
+		0.0060*e*cosd(mp+m) + 0.0041*e*cosd(mp-m)
+	w := math.Abs(cosd(f1))
+	gamma := (p*cosd(f1) + q*sind(f1)) * (1 - 0.0048*w)
+	u := 0.0059 + 0.0046*e*cosd(m) - 0.0182*cosd(mp) + 0.0004*cosd(2*mp) - 0.0005*cosd(m+mp)
+
+	return lunation{jde: jde, gamma: gamma, u: u, mp: mp}, true
+}
+
+// lunationRange returns the first and last lunation numbers whose mean
+// instants bracket the interval
+func lunationRange(startJD, endJD float64) (float64, float64) {
+	first := math.Floor((startJD-2451550.09766)/meanLunation) - 1
+	last := math.Ceil((endJD-2451550.09766)/meanLunation) + 1
+	return first, last
+}
+
+// SolarEclipses finds every solar eclipse whose greatest eclipse falls
+// between two Julian days on the UTC time scale
+func SolarEclipses(startJD, endJD float64) []SolarEclipse {
+	var eclipses []SolarEclipse
+	first, last := lunationRange(startJD, endJD)
+	for k := first; k <= last; k++ {
+		l, ok := lunationCircumstances(k)
+		if !ok {
+			continue
+		}
+		g := math.Abs(l.gamma)
+		if g > 1.5433+l.u {
+			continue
+		}
+
+		kind := PartialEclipse
+		if g < 0.9972 {
+			switch {
+			case l.u < 0:
+				kind = TotalEclipse
+			case l.u > 0.0047:
+				kind = AnnularEclipse
+			case l.u < 0.00464*math.Sqrt(1-l.gamma*l.gamma):
+				kind = HybridEclipse
+			default:
+				kind = AnnularEclipse
+			}
+		}
+
+		jd := UniversalTime(l.jde)
+		if jd < startJD || jd >= endJD {
+			continue
+		}
+		eclipses = append(eclipses, SolarEclipse{Kind: kind, JD: jd, Gamma: l.gamma, U: l.u})
+	}
+	return eclipses
+}
+
+// LunarEclipses finds every lunar eclipse whose greatest eclipse falls
+// between two Julian days on the UTC time scale
+func LunarEclipses(startJD, endJD float64) []LunarEclipse {
+	var eclipses []LunarEclipse
+	first, last := lunationRange(startJD, endJD)
+	for k := first + 0.5; k <= last; k++ {
+		l, ok := lunationCircumstances(k)
+		if !ok {
+			continue
+		}
+		g := math.Abs(l.gamma)
+		penumbral := (1.5573 + l.u - g) / 0.5450
+		umbral := (1.0128 - l.u - g) / 0.5450
+		if penumbral <= 0 {
+			continue
+		}
+
+		jd := UniversalTime(l.jde)
+		if jd < startJD || jd >= endJD {
+			continue
+		}
+
+		// Semidurations in days of the penumbral, partial and total phases
+		n := 0.5458 + 0.0400*cosd(l.mp)
+		semi := func(radius float64) float64 {
+			if radius <= g {
+				return 0
+			}
+			return math.Sqrt(radius*radius-l.gamma*l.gamma) / n / 24
+		}
+		sp, su, st := semi(1.5573+l.u), semi(1.0128-l.u), semi(0.4678-l.u)
+
+		eclipse := LunarEclipse{
+			Kind:               PenumbralEclipse,
+			JD:                 jd,
+			Gamma:              l.gamma,
+			PenumbralMagnitude: penumbral,
+			UmbralMagnitude:    math.Max(umbral, 0),
+			P1:                 jd - sp,
+			P4:                 jd + sp,
+		}
+		if su > 0 {
+			eclipse.Kind = PartialEclipse
+			eclipse.U1, eclipse.U4 = jd-su, jd+su
+		}
+		if st > 0 {
+			eclipse.Kind = TotalEclipse
+			eclipse.U2, eclipse.U3 = jd-st, jd+st
+		}
+		eclipses = append(eclipses, eclipse)
+	}
+	return eclipses
+}
+
+// LocalCircumstances computes a solar eclipse as seen from a site. ok is
+// false when the Moon does not cover any part of the Sun there. The Sun may
+// be below the horizon during some or all of the eclipse.
+func (e SolarEclipse) LocalCircumstances(site Site) (LocalSolarEclipse, bool) {
+	// sep returns the topocentric separation of the centres less the sum of
+	// the semidiameters, together with both semidiameters
+	type geometry struct{ sep, sunRadius, moonRadius, sunAltitude float64 }
+	at := func(jd float64) geometry {
+		jde := JDE(jd)
+		lst := LocalSiderealTime(jd, site.Longitude)
+
+		sunEcl, r := SunApparentEcliptic(jde)
+		moonEcl, dist := MoonApparentEcliptic(jde)
+		eps := TrueObliquity(jde)
+		sun, sunDist := site.Topocentric(sunEcl.ToEquatorial(eps), r*AstronomicalUnitKm, lst)
+		moon, moonDist := site.Topocentric(moonEcl.ToEquatorial(eps), dist, lst)
+
+		return geometry{
+			sep:         AngularSeparation(sun, moon),
+			sunRadius:   959.63 * arcsecToDeg * AstronomicalUnitKm / sunDist,
+			moonRadius:  asind(moonEarthRadius * EarthRadiusKm / moonDist),
+			sunAltitude: sun.ToHorizontal(lst, site).Altitude,
+		}
+	}
+	outer := func(jd float64) float64 {
+		g := at(jd)
+		return g.sep - g.sunRadius - g.moonRadius
+	}
+	inner := func(jd float64) float64 {
+		g := at(jd)
+		return g.sep - math.Abs(g.moonRadius-g.sunRadius)
+	}
+	slope := func(jd float64) float64 {
+		return at(jd+1e-4).sep - at(jd-1e-4).sep
+	}
+
+	// The penumbra takes at most about three and a half hours to reach any
+	// given point after greatest eclipse on the Earth
+	const window, step = 4.0 / 24, 2.0 / 1440
+	from, to := e.JD-window, e.JD+window
+	maxJD := from
+	prev := slope(from)
+	for jd := from; jd < to; jd += step {
+		cur := slope(jd + step)
+		if prev < 0 && cur >= 0 {
+			maxJD = bisect(jd, jd+step, slope)
+			break
+		}
+		prev = cur
+	}
+
+	g := at(maxJD)
+	if g.sep >= g.sunRadius+g.moonRadius {
+		return LocalSolarEclipse{}, false
+	}
+
+	local := LocalSolarEclipse{
+		Kind:        PartialEclipse,
+		Max:         maxJD,
+		C1:          bisect(from, maxJD, outer),
+		C4:          bisect(maxJD, to, outer),
+		Magnitude:   (g.sunRadius + g.moonRadius - g.sep) / (2 * g.sunRadius),
+		Obscuration: overlap(g.sunRadius, g.moonRadius, g.sep) / (math.Pi * g.sunRadius * g.sunRadius),
+		SunAltitude: g.sunAltitude,
+	}
+	if g.sep < math.Abs(g.moonRadius-g.sunRadius) {
+		local.Kind = AnnularEclipse
+		if g.moonRadius > g.sunRadius {
+			local.Kind = TotalEclipse
+		}
+		local.C2 = bisect(local.C1, maxJD, inner)
+		local.C3 = bisect(maxJD, local.C4, inner)
+	}
+	return local, true
+}
+
+// overlap returns the area common to two discs of radii r1 and r2 whose
+// centres are d apart
+func overlap(r1, r2, d float64) float64 {
+	switch {
+	case d >= r1+r2:
+		return 0
+	case d <= math.Abs(r1-r2):
+		r := math.Min(r1, r2)
+		return math.Pi * r * r
+	}
+	a1 := math.Acos((d*d + r1*r1 - r2*r2) / (2 * d * r1))
+	a2 := math.Acos((d*d + r2*r2 - r1*r1) / (2 * d * r2))
+	return r1*r1*(a1-math.Sin(2*a1)/2) + r2*r2*(a2-math.Sin(2*a2)/2)
+}
@@ -0,0 +1,513 @@
+package astro
+
+import (
+	"math"
+	"sort"
+)
+
+// GalileanMoon identifies one of the four large satellites of Jupiter
+type GalileanMoon int
+
+const (
+	Io GalileanMoon = iota
+	Europa
+	Ganymede
+	Callisto
+)
+
+// GalileanMoons lists the satellites in order of distance from Jupiter
+var GalileanMoons = []GalileanMoon{Io, Europa, Ganymede, Callisto}
+
+var galileanMoonNames = [...]string{"Io", "Europa", "Ganymede", "Callisto"}
+
+func (m GalileanMoon) String() string {
+	return galileanMoonNames[m]
+}
+
+// JovianOffset is the apparent position of a satellite relative to Jupiter
+// in equatorial radii of the planet. X is measured along Jupiter's equator,
+// positive to the west, Y towards Jupiter's north pole and Z along the line
+// of sight, positive when the satellite is farther than Jupiter.
+type JovianOffset struct {
+	X, Y, Z float64
+}
+
+// jupiterPolarRatio is the ratio of Jupiter's polar to equatorial radius
+const jupiterPolarRatio = 0.93513
+
+// differentialLightTime are the constants K of Meeus (44) correcting each
+// satellite for the light time across its distance from Jupiter
+var differentialLightTime = [...]float64{17295, 21819, 27558, 36548}
+
+// GalileanMoonPositions returns the apparent positions of the four satellites
+// seen from the Earth at a Julian ephemeris day
+func GalileanMoonPositions(jde float64) [4]JovianOffset {
+	ecl, delta := PlanetApparentEcliptic(Jupiter, jde)
+	return galileanProjection(jde-lightTimePerAU*delta, jde, ecl, delta)
+}
+
+// galileanShadowPositions returns the positions of the four satellites seen
+// from the Sun at the moment whose light reaches the Earth at jde: a
+// satellite in front of Jupiter casts its shadow on the disk, one behind it
+// is eclipsed
+func galileanShadowPositions(jde float64) [4]JovianOffset {
+	_, delta := PlanetApparentEcliptic(Jupiter, jde)
+	t := jde - lightTimePerAU*delta
+	helio := PlanetHeliocentric(Jupiter, t)
+	ecl := helio.ecliptic()
+	ecl.Longitude = PrecessLongitude(ecl.Longitude, jde)
+	return galileanProjection(t, jde, ecl, helio.Length())
+}
+
+// galileanProjection evaluates the theory of the satellites of E. Lieske
+// (E5) as abridged by Meeus (Astronomical Algorithms, chapter 44) for the
+// light-time corrected instant t, and projects the positions onto the sky
+// of a viewer who sees Jupiter at ecliptic coordinates of date ecl and
+// distance delta in AU
+func galileanProjection(t, jde float64, ecl Ecliptic, delta float64) [4]JovianOffset {
+	t -= 2443000.5
+
+	l1 := 106.07719 + 203.488955790*t
+	l2 := 175.73161 + 101.374724735*t
+	l3 := 120.55883 + 50.317609207*t
+	l4 := 84.44459 + 21.571071177*t
+
+	pi1 := 97.0881 + 0.16138586*t
+	pi2 := 154.8663 + 0.04726307*t
+	pi3 := 188.1840 + 0.00712734*t
+	pi4 := 335.2868 + 0.00184000*t
+
+	w1 := 312.3346 - 0.13279386*t
+	w2 := 100.4411 - 0.03263064*t
+	w3 := 119.1942 - 0.00717703*t
+	w4 := 322.6186 - 0.00175934*t
+
+	gamma := 0.33033*sind(163.679+0.0010512*t) + 0.03439*sind(34.486-0.0161731*t)
+	phi := 199.6766 + 0.17379190*t
+	psi := 316.5182 - 0.00000208*t
+	g := 30.23756 + 0.0830925701*t + gamma
+	gp := 31.97853 + 0.0334597339*t
+	bigPi := 13.469942
+
+	s1 := 0.47259*sind(2*(l1-l2)) -
+		0.03478*sind(pi3-pi4) +
+		0.01081*sind(l2-2*l3+pi3) +
+		0.00738*sind(phi) +
+		0.00713*sind(l2-2*l3+pi2) -
+		0.00674*sind(pi1+pi3-2*bigPi-2*g) +
+		0.00666*sind(l2-2*l3+pi4) +
+		0.00445*sind(l1-pi3) -
+		0.00354*sind(l1-l2) -
+		0.00317*sind(2*psi-2*bigPi) +
+		0.00265*sind(l1-pi4) -
+		0.00186*sind(g) +
+		0.00162*sind(pi2-pi3) +
+		0.00158*sind(4*(l1-l2)) -
+		0.00155*sind(l1-l3) -
+		0.00138*sind(psi+w3-2*bigPi-2*g) -
+		0.00115*sind(2*(l1-2*l2+w2)) +
+		0.00089*sind(pi2-pi4) +
+		0.00085*sind(l1+pi3-2*bigPi-2*g) +
+		0.00083*sind(w2-w3) +
+		0.00053*sind(psi-w2)
+
+	s2 := 1.06476*sind(2*(l2-l3)) +
+		0.04256*sind(l1-2*l2+pi3) +
+		0.03581*sind(l2-pi3) +
+		0.02395*sind(l1-2*l2+pi4) +
+		0.01984*sind(l2-pi4) -
+		0.01778*sind(phi) +
+		0.01654*sind(l2-pi2) +
+		0.01334*sind(l2-2*l3+pi2) +
+		0.01294*sind(pi3-pi4) -
+		0.01142*sind(l2-l3) -
+		0.01057*sind(g) -
+		0.00775*sind(2*(psi-bigPi)) +
+		0.00524*sind(2*(l1-l2)) -
+		0.00460*sind(l1-l3) +
+		0.00316*sind(psi-2*g+w3-2*bigPi) -
+		0.00203*sind(pi1+pi3-2*bigPi-2*g) +
+		0.00146*sind(psi-w3) -
+		0.00145*sind(2*g) +
+		0.00125*sind(psi-w4) -
+		0.00115*sind(l1-2*l3+pi3) -
+		0.00094*sind(2*(l2-w2)) +
+		0.00086*sind(2*(l1-2*l2+w2)) -
+		0.00086*sind(5*gp-2*g+52.225) -
+		0.00078*sind(l2-l4) -
+		0.00064*sind(3*l3-7*l4+4*pi4) +
+		0.00064*sind(pi1-pi4) -
+		0.00063*sind(l1-2*l3+pi4) +
+		0.00058*sind(w3-w4) +
+		0.00056*sind(2*(psi-bigPi-g)) +
+		0.00056*sind(2*(l2-l4)) +
+		0.00055*sind(2*(l1-l3)) +
+		0.00052*sind(3*l3-7*l4+pi3+3*pi4) -
+		0.00043*sind(l1-pi3) +
+		0.00041*sind(5*(l2-l3)) +
+		0.00041*sind(pi4-bigPi) +
+		0.00032*sind(w2-w3) +
+		0.00032*sind(2*(l3-g-bigPi))
+
+	s3 := 0.16490*sind(l3-pi3) +
+		0.09081*sind(l3-pi4) -
+		0.06907*sind(l2-l3) +
+		0.03784*sind(pi3-pi4) +
+		0.01846*sind(2*(l3-l4)) -
+		0.01340*sind(g) -
+		0.01014*sind(2*(psi-bigPi)) +
+		0.00704*sind(l2-2*l3+pi3) -
+		0.00620*sind(l2-2*l3+pi2) -
+		0.00541*sind(l3-l4) +
+		0.00381*sind(l2-2*l3+pi4) +
+		0.00235*sind(psi-w3) +
+		0.00198*sind(psi-w4) +
+		0.00176*sind(phi) +
+		0.00130*sind(3*(l3-l4)) +
+		0.00125*sind(l1-l3) -
+		0.00119*sind(5*gp-2*g+52.225) +
+		0.00109*sind(l1-l2) -
+		0.00100*sind(3*l3-7*l4+4*pi4) +
+		0.00091*sind(w3-w4) +
+		0.00080*sind(3*l3-7*l4+pi3+3*pi4) -
+		0.00075*sind(2*l2-3*l3+pi3) +
+		0.00072*sind(pi1+pi3-2*bigPi-2*g) +
+		0.00069*sind(pi4-bigPi) -
+		0.00058*sind(2*l3-3*l4+pi4) -
+		0.00057*sind(l3-2*l4+pi4) +
+		0.00056*sind(l3+pi3-2*bigPi-2*g) -
+		0.00052*sind(l2-2*l3+pi1) -
+		0.00050*sind(pi2-pi3) +
+		0.00048*sind(l3-2*l4+pi3) -
+		0.00045*sind(2*l2-3*l3+pi4) -
+		0.00041*sind(pi2-pi4) -
+		0.00038*sind(2*g) -
+		0.00037*sind(pi3-pi4+w3-w4) -
+		0.00032*sind(3*l3-7*l4+2*pi3+2*pi4) +
+		0.00030*sind(4*(l3-l4)) +
+		0.00029*sind(l3+pi4-2*bigPi-2*g) -
+		0.00028*sind(w3+psi-2*bigPi-2*g) +
+		0.00026*sind(l3-bigPi-g) +
+		0.00024*sind(l2-3*l3+2*l4) +
+		0.00021*sind(2*(l3-bigPi-g)) -
+		0.00021*sind(l3-pi2) +
+		0.00017*sind(2*(l3-pi3))
+
+	s4 := 0.84287*sind(l4-pi4) +
+		0.03431*sind(pi4-pi3) -
+		0.03305*sind(2*(psi-bigPi)) -
+		0.03211*sind(g) -
+		0.01862*sind(l4-pi3) +
+		0.01186*sind(psi-w4) +
+		0.00623*sind(l4+pi4-2*g-2*bigPi) +
+		0.00387*sind(2*(l4-pi4)) -
+		0.00284*sind(5*gp-2*g+52.225) -
+		0.00234*sind(2*(psi-pi4)) -
+		0.00223*sind(l3-l4) -
+		0.00208*sind(l4-bigPi) +
+		0.00178*sind(psi+w4-2*pi4) +
+		0.00134*sind(pi4-bigPi) +
+		0.00125*sind(2*(l4-g-bigPi)) -
+		0.00117*sind(2*g) -
+		0.00112*sind(2*(l3-l4)) +
+		0.00107*sind(3*l3-7*l4+4*pi4) +
+		0.00102*sind(l4-g-bigPi) +
+		0.00096*sind(2*l4-psi-w4) +
+		0.00087*sind(2*(psi-w4)) -
+		0.00085*sind(3*l3-7*l4+pi3+3*pi4) +
+		0.00085*sind(l3-2*l4+pi4) -
+		0.00081*sind(2*(l4-psi)) +
+		0.00071*sind(l4+pi4-2*bigPi-3*g) +
+		0.00061*sind(l1-l4) -
+		0.00056*sind(psi-w3) -
+		0.00054*sind(l3-2*l4+pi3) +
+		0.00051*sind(l2-l4) +
+		0.00042*sind(2*(psi-g-bigPi)) +
+		0.00039*sind(2*(pi4-w4)) +
+		0.00036*sind(psi+bigPi-pi4-w4) +
+		0.00035*sind(2*gp-g+188.37) -
+		0.00035*sind(l4-pi4+2*bigPi-2*psi) -
+		0.00032*sind(l4+pi4-2*bigPi-g) +
+		0.00030*sind(2*gp-2*g+149.15) +
+		0.00029*sind(3*l3-7*l4+2*pi3+2*pi4) +
+		0.00028*sind(l4-pi4+2*psi-2*bigPi) -
+		0.00028*sind(2*(l4-w4)) -
+		0.00027*sind(pi3-pi4+w3-w4) -
+		0.00026*sind(5*gp-3*g+188.37) +
+		0.00025*sind(w4-w3) -
+		0.00025*sind(l2-3*l3+2*l4) -
+		0.00023*sind(3*(l3-l4)) +
+		0.00021*sind(2*l4-2*bigPi-3*g) -
+		0.00021*sind(2*l3-3*l4+pi4) +
+		0.00019*sind(l4-pi4-g) -
+		0.00019*sind(2*l4-pi3-pi4) -
+		0.00018*sind(l4-pi4+g) -
+		0.00016*sind(l4+pi3-2*bigPi-2*g)
+
+	// True longitudes
+	L1, L2, L3, L4 := l1+s1, l2+s2, l3+s3, l4+s4
+
+	// Latitudes with respect to Jupiter's equator
+	b1 := math.Atan(0.0006393*sind(L1-w1)+
+		0.0001825*sind(L1-w2)+
+		0.0000329*sind(L1-w3)-
+		0.0000311*sind(L1-psi)+
+		0.0000093*sind(L1-w4)+
+		0.0000075*sind(3*L1-4*l2-1.9927*s1+w2)+
+		0.0000046*sind(L1+psi-2*bigPi-2*g)) * radToDeg
+	b2 := math.Atan(0.0081004*sind(L2-w2)+
+		0.0004512*sind(L2-w3)-
+		0.0003284*sind(L2-psi)+
+		0.0001160*sind(L2-w4)+
+		0.0000272*sind(l1-2*l3+1.0146*s2+w2)-
+		0.0000144*sind(L2-w1)+
+		0.0000143*sind(L2+psi-2*bigPi-2*g)+
+		0.0000035*sind(L2-psi+g)-
+		0.0000028*sind(l1-2*l3+1.0146*s2+w3)) * radToDeg
+	b3 := math.Atan(0.0032402*sind(L3-w3)-
+		0.0016911*sind(L3-psi)+
+		0.0006847*sind(L3-w4)-
+		0.0002797*sind(L3-w2)+
+		0.0000321*sind(L3+psi-2*bigPi-2*g)+
+		0.0000051*sind(L3-psi+g)-
+		0.0000045*sind(L3-psi-g)-
+		0.0000045*sind(L3+psi-2*bigPi)+
+		0.0000037*sind(L3+psi-2*bigPi-3*g)+
+		0.0000030*sind(2*l2-3*L3+4.03*s3+w2)-
+		0.0000021*sind(2*l2-3*L3+4.03*s3+w3)) * radToDeg
+	b4 := math.Atan(-0.0076579*sind(L4-psi)+
+		0.0044134*sind(L4-w4)-
+		0.0005112*sind(L4-w3)+
+		0.0000773*sind(L4+psi-2*bigPi-2*g)+
+		0.0000104*sind(L4-psi+g)-
+		0.0000102*sind(L4-psi-g)+
+		0.0000088*sind(L4+psi-2*bigPi-3*g)-
+		0.0000038*sind(L4+psi-2*bigPi-g)) * radToDeg
+
+	// Radius vectors in equatorial radii of Jupiter
+	r1 := 5.90569 * (1 - 0.0041339*cosd(2*(l1-l2)) -
+		0.0000387*cosd(l1-pi3) -
+		0.0000214*cosd(l1-pi4) +
+		0.0000170*cosd(l1-l2) -
+		0.0000131*cosd(4*(l1-l2)) +
+		0.0000106*cosd(l1-l3) -
+		0.0000066*cosd(l1+pi3-2*bigPi-2*g))
+	r2 := 9.39657 * (1 + 0.0093848*cosd(l1-l2) -
+		0.0003116*cosd(l2-pi3) -
+		0.0001744*cosd(l2-pi4) -
+		0.0001442*cosd(l2-pi2) +
+		0.0000553*cosd(l2-l3) +
+		0.0000523*cosd(l1-l3) -
+		0.0000290*cosd(2*(l1-l2)) +
+		0.0000164*cosd(2*(l2-w2)) +
+		0.0000107*cosd(l1-2*l3+pi3) -
+		0.0000102*cosd(l2-pi1) -
+		0.0000091*cosd(2*(l1-l3)))
+	r3 := 14.98832 * (1 - 0.0014388*cosd(l3-pi3) -
+		0.0007919*cosd(l3-pi4) +
+		0.0006342*cosd(l2-l3) -
+		0.0001761*cosd(2*(l3-l4)) +
+		0.0000294*cosd(l3-l4) -
+		0.0000156*cosd(3*(l3-l4)) +
+		0.0000156*cosd(l1-l3) -
+		0.0000153*cosd(l1-l2) +
+		0.0000070*cosd(2*l2-3*l3+pi3) -
+		0.0000051*cosd(l3+pi3-2*bigPi-2*g))
+	r4 := 26.36273 * (1 - 0.0073546*cosd(l4-pi4) +
+		0.0001621*cosd(l4-pi3) +
+		0.0000974*cosd(l3-l4) -
+		0.0000543*cosd(l4+pi4-2*bigPi-2*g) -
+		0.0000271*cosd(2*(l4-pi4)) +
+		0.0000182*cosd(l4-bigPi) +
+		0.0000177*cosd(2*(l3-l4)) -
+		0.0000167*cosd(2*l4-psi-w4) +
+		0.0000167*cosd(psi-w4) -
+		0.0000155*cosd(2*(l4-bigPi-g)) +
+		0.0000142*cosd(2*(l4-psi)) +
+		0.0000105*cosd(l1-l4) +
+		0.0000092*cosd(l2-l4) -
+		0.0000089*cosd(l4-bigPi-g) -
+		0.0000062*cosd(l4+pi4-2*bigPi-3*g) +
+		0.0000048*cosd(2*(l4-w4)))
+
+	// Precession from the B1950 equinox of the theory to the equinox of date
+	t0 := (jde - 2433282.423) / 36525
+	p := 1.3966626*t0 + 0.0003088*t0*t0
+	L1, L2, L3, L4 = L1+p, L2+p, L3+p, L4+p
+	psi += p
+
+	// Inclination of Jupiter's equator on its orbit, and the node and
+	// inclination of the orbit on the ecliptic of date
+	incl := 3.120262 + 0.0006*(jde-2415020.5)/36525
+	tc := JulianCenturies(jde)
+	node := 100.464407 + 1.0209774*tc + 0.00040315*tc*tc + 0.000000404*tc*tc*tc
+	orbitIncl := 1.303267 - 0.0054965*tc + 0.00000466*tc*tc - 0.000000002*tc*tc*tc
+
+	// rotate carries rectangular coordinates in Jupiter's equatorial frame to
+	// the viewer's sky, with the line of sight along the third axis
+	rotate := func(x, y, z float64) (float64, float64, float64) {
+		a1, b1, c1 := x, y*cosd(incl)-z*sind(incl), y*sind(incl)+z*cosd(incl)
+		f := psi - node
+		a2, b2, c2 := a1*cosd(f)-b1*sind(f), a1*sind(f)+b1*cosd(f), c1
+		a3, b3, c3 := a2, b2*cosd(orbitIncl)-c2*sind(orbitIncl), b2*sind(orbitIncl)+c2*cosd(orbitIncl)
+		a4, b4, c4 := a3*cosd(node)-b3*sind(node), a3*sind(node)+b3*cosd(node), c3
+		a5 := a4*sind(ecl.Longitude) - b4*cosd(ecl.Longitude)
+		b5 := a4*cosd(ecl.Longitude) + b4*sind(ecl.Longitude)
+		c5 := c4
+		b6 := c5*sind(ecl.Latitude) + b5*cosd(ecl.Latitude)
+		c6 := c5*cosd(ecl.Latitude) - b5*sind(ecl.Latitude)
+		return a5, b6, c6
+	}
+
+	// The projection of Jupiter's pole fixes the orientation of the equator
+	poleA, _, poleC := rotate(0, 0, 1)
+	d := math.Atan2(poleA, poleC) * radToDeg
+
+	var offsets [4]JovianOffset
+	for i, moon := range [4]struct{ l, b, r float64 }{{L1, b1, r1}, {L2, b2, r2}, {L3, b3, r3}, {L4, b4, r4}} {
+		a, b, c := rotate(
+			moon.r*cosd(moon.l-psi)*cosd(moon.b),
+			moon.r*sind(moon.l-psi)*cosd(moon.b),
+			moon.r*sind(moon.b),
+		)
+		x := a*cosd(d) - c*sind(d)
+		y := a*sind(d) + c*cosd(d)
+		z := b
+
+		// Light time across the satellite's distance from Jupiter, then
+		// perspective
+		x += math.Abs(z) / differentialLightTime[i] * math.Sqrt(math.Max(0, 1-(x/moon.r)*(x/moon.r)))
+		w := delta / (delta + z/2095)
+		offsets[i] = JovianOffset{X: x * w, Y: y * w, Z: z}
+	}
+	return offsets
+}
+
+// JovianPhenomenonKind is the kind of a phenomenon of a Galilean satellite
+type JovianPhenomenonKind int
+
+const (
+	// MoonTransit is the passage of a satellite in front of Jupiter's disk
+	MoonTransit JovianPhenomenonKind = iota
+	// ShadowTransit is the passage of a satellite's shadow across the disk
+	ShadowTransit
+	// MoonEclipse is the passage of a satellite through Jupiter's shadow
+	MoonEclipse
+	// MoonOccultation is the passage of a satellite behind Jupiter's disk
+	MoonOccultation
+)
+
+// JovianPhenomenon is a transit, shadow transit, eclipse or occultation of a
+// Galilean satellite. Start and End are Julian days on the UTC time scale of
+// the moments the centre of the satellite, or of its shadow, crosses the limb
+// of Jupiter or the edge of its shadow, as seen from the Earth.
+type JovianPhenomenon struct {
+	Moon       GalileanMoon
+	Kind       JovianPhenomenonKind
+	Start, End float64
+	// StartHidden and EndHidden report that the contact cannot be seen: an
+	// eclipse contact while the satellite is behind the disk, or an
+	// occultation contact while it is eclipsed
+	StartHidden, EndHidden bool
+}
+
+const (
+	// jovianStep is the sampling interval of the search; no phenomenon lasts
+	// less than about an hour except grazing ones of Callisto
+	jovianStep = 10.0 / 1440
+
+	// jovianLongest bounds the duration of a phenomenon, so that one starting
+	// before the end of the range is followed to its end
+	jovianLongest = 0.5
+)
+
+// JovianPhenomena finds the phenomena of the Galilean satellites that begin
+// between two Julian days on the UTC time scale, in order of beginning. The
+// positions of the satellites follow the theory of Lieske as abridged by
+// Meeus, good to about a minute of time.
+func JovianPhenomena(startJD, endJD float64) []JovianPhenomenon {
+	// disk returns, for each satellite and for the Earth and then the Sun as
+	// viewers, the distance from the centre of Jupiter's disk in units of its
+	// radius along the same direction, less one, negative inside the disk
+	disk := func(jd float64) [2][4]float64 {
+		jde := JDE(jd)
+		var inside [2][4]float64
+		for v, offsets := range [2][4]JovianOffset{GalileanMoonPositions(jde), galileanShadowPositions(jde)} {
+			for i, o := range offsets {
+				inside[v][i] = math.Hypot(o.X, o.Y/jupiterPolarRatio) - 1
+			}
+		}
+		return inside
+	}
+	// behind reports whether a satellite is farther than Jupiter from the
+	// viewer
+	behind := func(jd float64, viewer int, moon GalileanMoon) bool {
+		jde := JDE(jd)
+		if viewer == 0 {
+			return GalileanMoonPositions(jde)[moon].Z > 0
+		}
+		return galileanShadowPositions(jde)[moon].Z > 0
+	}
+	// hidden reports whether a contact of an eclipse or occultation happens
+	// while the satellite is concealed by the other phenomenon
+	hidden := func(jd float64, p *JovianPhenomenon) bool {
+		other := -1
+		switch p.Kind {
+		case MoonEclipse:
+			other = 0
+		case MoonOccultation:
+			other = 1
+		}
+		return other >= 0 && disk(jd)[other][p.Moon] < 0 && behind(jd, other, p.Moon)
+	}
+
+	var phenomena []JovianPhenomenon
+	var open [2][4]*JovianPhenomenon
+	prev := disk(startJD)
+	for jd := startJD; jd < endJD+jovianLongest; jd += jovianStep {
+		cur := disk(jd + jovianStep)
+		for v := range cur {
+			for i, moon := range GalileanMoons {
+				crossing := func() float64 {
+					return bisect(jd, jd+jovianStep, func(t float64) float64 { return disk(t)[v][i] })
+				}
+				switch {
+				case prev[v][i] >= 0 && cur[v][i] < 0:
+					start := crossing()
+					if start >= endJD {
+						continue
+					}
+					p := &JovianPhenomenon{Moon: moon, Kind: jovianKind(v, behind(start, v, moon)), Start: start}
+					p.StartHidden = hidden(start, p)
+					open[v][i] = p
+				case prev[v][i] < 0 && cur[v][i] >= 0 && open[v][i] != nil:
+					open[v][i].End = crossing()
+					open[v][i].EndHidden = hidden(open[v][i].End, open[v][i])
+					phenomena = append(phenomena, *open[v][i])
+					open[v][i] = nil
+				}
+			}
+		}
+		prev = cur
+	}
+
+	sort.Slice(phenomena, func(i, j int) bool {
+		return phenomena[i].Start < phenomena[j].Start
+	})
+	return phenomena
+}
+
+// jovianKind names a phenomenon from the viewer, the Earth (0) or the Sun
+// (1), and the side of Jupiter the satellite is on
+func jovianKind(viewer int, behind bool) JovianPhenomenonKind {
+	switch {
+	case viewer == 0 && behind:
+		return MoonOccultation
+	case viewer == 0:
+		return MoonTransit
+	case behind:
+		return MoonEclipse
+	default:
+		return ShadowTransit
+	}
+}
@@ -0,0 +1,15 @@
+package astro
+
+// The north geomagnetic pole of the IGRF-13 dipole at epoch 2020. The pole
+// drifts by less than a tenth of a degree a year.
+const (
+	geomagneticPoleLatitude  = 80.65
+	geomagneticPoleLongitude = -72.68
+)
+
+// GeomagneticLatitude returns the latitude of a site in degrees in the frame
+// of the centred dipole of the Earth's magnetic field
+func GeomagneticLatitude(site Site) float64 {
+	return asind(sind(site.Latitude)*sind(geomagneticPoleLatitude) +
+		cosd(site.Latitude)*cosd(geomagneticPoleLatitude)*cosd(site.Longitude-geomagneticPoleLongitude))
+}
@@ -0,0 +1,205 @@
+package astro
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// J2000 is the Julian day of the standard epoch 2000 January 1.5 TT
+	J2000 = 2451545.0
+
+	unixEpochJD   = 2440587.5
+	secondsPerDay = 86400.0
+)
+
+// JulianDay returns the Julian day of the given instant on the UTC time scale
+func JulianDay(t time.Time) float64 {
+	return unixEpochJD + float64(t.Unix())/secondsPerDay + float64(t.Nanosecond())/(secondsPerDay*1e9)
+}
+
+// TimeFromJulianDay converts a Julian day on the UTC time scale back to a time.Time
+func TimeFromJulianDay(jd float64) time.Time {
+	seconds := (jd - unixEpochJD) * secondsPerDay
+	whole := math.Floor(seconds)
+	return time.Unix(int64(whole), int64((seconds-whole)*1e9)).UTC()
+}
+
+// EventTime converts a Julian day on the UTC time scale to the instant an
+// event computed for it is reported at, rounded to the second
+func EventTime(jd float64) time.Time {
+	return TimeFromJulianDay(jd).Round(time.Second)
+}
+
+// JulianCenturies returns the number of Julian centuries elapsed since J2000
+func JulianCenturies(jde float64) float64 {
+	return (jde - J2000) / 36525
+}
+
+// ttMinusTAI is the constant offset TT - TAI in seconds
+const ttMinusTAI = 32.184
+
+// leapSeconds lists the dates from which TAI - UTC took each value in
+// seconds, from the start of the current UTC system in 1972 to the leap
+// second of 2016 December 31
+var leapSeconds = []struct {
+	year   int
+	month  time.Month
+	offset float64
+}{
+	{1972, time.January, 10}, {1972, time.July, 11}, {1973, time.January, 12},
+	{1974, time.January, 13}, {1975, time.January, 14}, {1976, time.January, 15},
+	{1977, time.January, 16}, {1978, time.January, 17}, {1979, time.January, 18},
+	{1980, time.January, 19}, {1981, time.July, 20}, {1982, time.July, 21},
+	{1983, time.July, 22}, {1985, time.July, 23}, {1988, time.January, 24},
+	{1990, time.January, 25}, {1991, time.January, 26}, {1992, time.July, 27},
+	{1993, time.July, 28}, {1994, time.July, 29}, {1996, time.January, 30},
+	{1997, time.July, 31}, {1999, time.January, 32}, {2006, time.January, 33},
+	{2009, time.January, 34}, {2012, time.July, 35}, {2015, time.July, 36},
+	{2017, time.January, 37},
+}
+
+// leapSecondJDs holds the Julian days of the entries of leapSeconds
+var leapSecondJDs = func() []float64 {
+	jds := make([]float64, len(leapSeconds))
+	for i, l := range leapSeconds {
+		jds[i] = JulianDay(time.Date(l.year, l.month, 1, 0, 0, 0, 0, time.UTC))
+	}
+	return jds
+}()
+
+// TAIMinusUTC returns the number of leap seconds TAI - UTC in force on a
+// Julian day on the UTC time scale, and false before 1972 when UTC was not
+// yet kept in whole seconds from TAI. No leap seconds are assumed after the
+// last one announced.
+func TAIMinusUTC(jd float64) (float64, bool) {
+	i := sort.SearchFloat64s(leapSecondJDs, jd)
+	if i < len(leapSecondJDs) && leapSecondJDs[i] == jd {
+		i++
+	}
+	if i == 0 {
+		return 0, false
+	}
+	return leapSeconds[i-1].offset, true
+}
+
+// observedDeltaT holds TT - UT1 in seconds at the start of each year from
+// 1975 to 2025, from the IERS Earth orientation series
+var observedDeltaT = []float64{
+	45.48, 46.46, 47.52, 48.53, 49.59, 50.54, 51.38, 52.17, 52.96, 53.79,
+	54.34, 54.87, 55.32, 55.82, 56.30, 56.86, 57.57, 58.31, 59.12, 59.98,
+	60.79, 61.63, 62.30, 62.97, 63.47, 63.83, 64.09, 64.30, 64.47, 64.57,
+	64.69, 64.85, 65.15, 65.46, 65.78, 66.07, 66.32, 66.60, 66.91, 67.28,
+	67.64, 68.10, 68.59, 68.97, 69.22, 69.36, 69.36, 69.29, 69.20, 69.18,
+	69.14,
+}
+
+const (
+	observedDeltaTStart = 1975.0
+	observedDeltaTEnd   = 2025.0
+)
+
+// DeltaT returns the difference TT - UT1 in seconds for a decimal year. It
+// interpolates the observed values from 1975 to 2025, and otherwise uses the
+// Espenak and Meeus polynomial fits to the historical record and their long
+// term parabola, joined linearly to the last observed value until 2050.
+func DeltaT(year float64) float64 {
+	switch {
+	case year >= observedDeltaTStart && year < observedDeltaTEnd:
+		f := year - observedDeltaTStart
+		i := int(f)
+		return observedDeltaT[i] + (f-float64(i))*(observedDeltaT[i+1]-observedDeltaT[i])
+	case year >= observedDeltaTEnd && year < 2050:
+		last := observedDeltaT[len(observedDeltaT)-1]
+		return last + (DeltaT(2050)-last)*(year-observedDeltaTEnd)/(2050-observedDeltaTEnd)
+	case year >= 2050 && year < 2150:
+		u := (year - 1820) / 100
+		return -20 + 32*u*u - 0.5628*(2150-year)
+	case year >= 1961:
+		t := year - 1975
+		return 45.45 + 1.067*t - t*t/260 - t*t*t/718
+	case year >= 1941:
+		t := year - 1950
+		return 29.07 + 0.407*t - t*t/233 + t*t*t/2547
+	case year >= 1920:
+		t := year - 1920
+		return 21.20 + 0.84493*t - 0.076100*t*t + 0.0020936*t*t*t
+	case year >= 1900:
+		t := year - 1900
+		return -2.79 + 1.494119*t - 0.0598939*t*t + 0.0061966*t*t*t - 0.000197*t*t*t*t
+	case year >= 1860:
+		t := year - 1860
+		return 7.62 + 0.5737*t - 0.251754*t*t + 0.01680668*t*t*t -
+			0.0004473624*t*t*t*t + t*t*t*t*t/233174
+	case year >= 1800:
+		t := year - 1800
+		return 13.72 - 0.332447*t + 0.0068612*t*t + 0.0041116*t*t*t - 0.00037436*t*t*t*t +
+			0.0000121272*t*t*t*t*t - 0.0000001699*t*t*t*t*t*t + 0.000000000875*t*t*t*t*t*t*t
+	case year >= 1700:
+		t := year - 1700
+		return 8.83 + 0.1603*t - 0.0059285*t*t + 0.00013336*t*t*t - t*t*t*t/1174000
+	case year >= 1600:
+		t := year - 1600
+		return 120 - 0.9808*t - 0.01532*t*t + t*t*t/7129
+	case year >= 500:
+		u := (year - 1000) / 100
+		return 1574.2 - 556.01*u + 71.23472*u*u + 0.319781*u*u*u - 0.8503463*u*u*u*u -
+			0.005050998*u*u*u*u*u + 0.0083572073*u*u*u*u*u*u
+	case year >= -500:
+		u := year / 100
+		return 10583.6 - 1014.41*u + 33.78311*u*u - 5.952053*u*u*u - 0.1798452*u*u*u*u +
+			0.022174192*u*u*u*u*u + 0.0090316521*u*u*u*u*u*u
+	default:
+		u := (year - 1820) / 100
+		return -20 + 32*u*u
+	}
+}
+
+// JDE converts a Julian day on the UTC time scale to Terrestrial Time. Since
+// 1972 TT - UTC follows from the leap seconds; before then UTC is taken to be
+// UT1 and the modelled Delta T is used.
+func JDE(jd float64) float64 {
+	return jd + ttMinusUTC(jd)/secondsPerDay
+}
+
+// ttMinusUTC returns TT - UTC in seconds on a Julian day on the UTC time scale
+func ttMinusUTC(jd float64) float64 {
+	if offset, ok := TAIMinusUTC(jd); ok {
+		return offset + ttMinusTAI
+	}
+	return DeltaT(decimalYear(jd))
+}
+
+// decimalYear returns an approximate decimal year for a Julian day
+func decimalYear(jd float64) float64 {
+	return 2000 + (jd-J2000)/365.25
+}
+
+// UniversalTime converts a Julian ephemeris day (TT) back to the UTC time scale
+func UniversalTime(jde float64) float64 {
+	// The offset depends on the UTC instant, so it is looked up again once
+	// that is roughly known to settle it around leap seconds
+	jd := jde - ttMinusUTC(jde)/secondsPerDay
+	return jde - ttMinusUTC(jd)/secondsPerDay
+}
+
+// UT1 converts a Julian day on the UTC time scale to UT1, the time scale of
+// the rotation of the Earth, from TT and the modelled Delta T. Since 1972
+// leap seconds keep the two within 0.9 s. After the last observed Delta T the
+// model drifts away from the leap seconds announced, so UT1 - UTC is held at
+// its last observed value instead, within the same bound.
+func UT1(jd float64) float64 {
+	if _, ok := TAIMinusUTC(jd); !ok {
+		return JDE(jd) - DeltaT(decimalYear(jd))/secondsPerDay
+	}
+	ut1MinusUTC := ttMinusUTC(jd) - DeltaT(min(decimalYear(jd), observedDeltaTEnd))
+	return jd + max(-0.9, min(0.9, ut1MinusUTC))/secondsPerDay
+}
+
+// TDB converts a Julian ephemeris day (TT) to Barycentric Dynamical Time,
+// which differs from TT by less than 2 milliseconds over the year
+func TDB(jde float64) float64 {
+	g := 357.53 + 0.98560028*(jde-J2000)
+	return jde + (0.001657*sind(g)+0.000014*sind(2*g))/secondsPerDay
+}
@@ -0,0 +1,146 @@
+package astro
+
+import "math"
+
+// EarthRadiusKm is the equatorial radius of the Earth in kilometres
+const EarthRadiusKm = 6378.14
+
+// moonTerm is a periodic term of the lunar theory: multiples of the
+// fundamental arguments D, M, M' and F, and coefficients for the sine
+// (longitude or latitude) and cosine (distance) series
+type moonTerm struct {
+	D, M, Mp, F float64
+	Sin, Cos    float64
+}
+
+// Periodic terms of the ELP-2000/82 lunar theory as truncated by Meeus
+// (Astronomical Algorithms, tables 47.A and 47.B). Units are 1e-6 degree
+// for longitude and latitude and 1e-3 km for distance.
+var moonLongitudeDistance = []moonTerm{
+	{0, 0, 1, 0, 6288774, -20905355}, {2, 0, -1, 0, 1274027, -3699111},
+	{2, 0, 0, 0, 658314, -2955968}, {0, 0, 2, 0, 213618, -569925},
+	{0, 1, 0, 0, -185116, 48888}, {0, 0, 0, 2, -114332, -3149},
+	{2, 0, -2, 0, 58793, 246158}, {2, -1, -1, 0, 57066, -152138},
+	{2, 0, 1, 0, 53322, -170733}, {2, -1, 0, 0, 45758, -204586},
+	{0, 1, -1, 0, -40923, -129620}, {1, 0, 0, 0, -34720, 108743},
+	{0, 1, 1, 0, -30383, 104755}, {2, 0, 0, -2, 15327, 10321},
+	{0, 0, 1, 2, -12528, 0}, {0, 0, 1, -2, 10980, 79661},
+	{4, 0, -1, 0, 10675, -34782}, {0, 0, 3, 0, 10034, -23210},
+	{4, 0, -2, 0, 8548, -21636}, {2, 1, -1, 0, -7888, 24208},
+	{2, 1, 0, 0, -6766, 30824}, {1, 0, -1, 0, -5163, -8379},
+	{1, 1, 0, 0, 4987, -16675}, {2, -1, 1, 0, 4036, -12831},
+	{2, 0, 2, 0, 3994, -10445}, {4, 0, 0, 0, 3861, -11650},
+	{2, 0, -3, 0, 3665, 14403}, {0, 1, -2, 0, -2689, -7003},
+	{2, 0, -1, 2, -2602, 0}, {2, -1, -2, 0, 2390, 10056},
+	{1, 0, 1, 0, -2348, 6322}, {2, -2, 0, 0, 2236, -9884},
+	{0, 1, 2, 0, -2120, 5751}, {0, 2, 0, 0, -2069, 0},
+	{2, -2, -1, 0, 2048, -4950}, {2, 0, 1, -2, -1773, 4130},
+	{2, 0, 0, 2, -1595, 0}, {4, -1, -1, 0, 1215, -3958},
+	{0, 0, 2, 2, -1110, 0}, {3, 0, -1, 0, -892, 3258},
+	{2, 1, 1, 0, -810, 2616}, {4, -1, -2, 0, 759, -1897},
+	{0, 2, -1, 0, -713, -2117}, {2, 2, -1, 0, -700, 2354},
+	{2, 1, -2, 0, 691, 0}, {2, -1, 0, -2, 596, 0},
+	{4, 0, 1, 0, 549, -1423}, {0, 0, 4, 0, 537, -1117},
+	{4, -1, 0, 0, 520, -1571}, {1, 0, -2, 0, -487, -1739},
+	{2, 1, 0, -2, -399, 0}, {0, 0, 2, -2, -381, -4421},
+	{1, 1, 1, 0, 351, 0}, {3, 0, -2, 0, -340, 0},
+	{4, 0, -3, 0, 330, 0}, {2, -1, 2, 0, 327, 0},
+	{0, 2, 1, 0, -323, 1165}, {1, 1, -1, 0, 299, 0},
+	{2, 0, 3, 0, 294, 0}, {2, 0, -1, -2, 0, 8752},
+}
+
+var moonLatitude = []moonTerm{
+	{0, 0, 0, 1, 5128122, 0}, {0, 0, 1, 1, 280602, 0}, {0, 0, 1, -1, 277693, 0},
+	{2, 0, 0, -1, 173237, 0}, {2, 0, -1, 1, 55413, 0}, {2, 0, -1, -1, 46271, 0},
+	{2, 0, 0, 1, 32573, 0}, {0, 0, 2, 1, 17198, 0}, {2, 0, 1, -1, 9266, 0},
+	{0, 0, 2, -1, 8822, 0}, {2, -1, 0, -1, 8216, 0}, {2, 0, -2, -1, 4324, 0},
+	{2, 0, 1, 1, 4200, 0}, {2, 1, 0, -1, -3359, 0}, {2, -1, -1, 1, 2463, 0},
+	{2, -1, 0, 1, 2211, 0}, {2, -1, -1, -1, 2065, 0}, {0, 1, -1, -1, -1870, 0},
+	{4, 0, -1, -1, 1828, 0}, {0, 1, 0, 1, -1794, 0}, {0, 0, 0, 3, -1749, 0},
+	{0, 1, -1, 1, -1565, 0}, {1, 0, 0, 1, -1491, 0}, {0, 1, 1, 1, -1475, 0},
+	{0, 1, 1, -1, -1410, 0}, {0, 1, 0, -1, -1344, 0}, {1, 0, 0, -1, -1335, 0},
+	{0, 0, 3, 1, 1107, 0}, {4, 0, 0, -1, 1021, 0}, {4, 0, -1, 1, 833, 0},
+	{0, 0, 1, -3, 777, 0}, {4, 0, -2, 1, 671, 0}, {2, 0, 0, -3, 607, 0},
+	{2, 0, 2, -1, 596, 0}, {2, -1, 1, -1, 491, 0}, {2, 0, -2, 1, -451, 0},
+	{0, 0, 3, -1, 439, 0}, {2, 0, 2, 1, 422, 0}, {2, 0, -3, -1, 421, 0},
+	{2, 1, -1, 1, -366, 0}, {2, 1, 0, 1, -351, 0}, {4, 0, 0, 1, 331, 0},
+	{2, -1, 1, 1, 315, 0}, {2, -2, 0, -1, 302, 0}, {0, 0, 1, 3, -283, 0},
+	{2, 1, 1, -1, -229, 0}, {1, 1, 0, -1, 223, 0}, {1, 1, 0, 1, 223, 0},
+	{0, 1, -2, -1, -220, 0}, {2, 1, -1, -1, -220, 0}, {1, 0, 1, 1, -185, 0},
+	{2, -1, -2, -1, 181, 0}, {0, 1, 2, 1, -177, 0}, {4, 0, -2, -1, 176, 0},
+	{4, -1, -1, -1, 166, 0}, {1, 0, 1, -1, -164, 0}, {4, 0, 1, -1, 132, 0},
+	{1, 0, -1, -1, -119, 0}, {4, -1, 0, -1, 115, 0}, {2, -2, 0, 1, 107, 0},
+}
+
+// MoonGeocentric returns the geometric geocentric ecliptic position of the
+// Moon referred to the mean equinox of date, and its distance in kilometres
+func MoonGeocentric(jde float64) (Ecliptic, float64) {
+	t := JulianCenturies(jde)
+	t2, t3, t4 := t*t, t*t*t, t*t*t*t
+
+	lp := 218.3164477 + 481267.88123421*t - 0.0015786*t2 + t3/538841 - t4/65194000
+	d := 297.8501921 + 445267.1114034*t - 0.0018819*t2 + t3/545868 - t4/113065000
+	m := 357.5291092 + 35999.0502909*t - 0.0001536*t2 + t3/24490000
+	mp := 134.9633964 + 477198.8675055*t + 0.0087414*t2 + t3/69699 - t4/14712000
+	f := 93.2720950 + 483202.0175233*t - 0.0036539*t2 - t3/3526000 + t4/863310000
+
+	a1 := 119.75 + 131.849*t
+	a2 := 53.09 + 479264.290*t
+	a3 := 313.45 + 481266.484*t
+	e := 1 - 0.002516*t - 0.0000074*t2
+
+	// Terms involving the Sun's anomaly are scaled by the decreasing
+	// eccentricity of the Earth's orbit
+	eccentricity := func(term moonTerm) float64 {
+		switch math.Abs(term.M) {
+		case 1:
+			return e
+		case 2:
+			return e * e
+		}
+		return 1
+	}
+
+	var sumL, sumR, sumB float64
+	for _, term := range moonLongitudeDistance {
+		arg := term.D*d + term.M*m + term.Mp*mp + term.F*f
+		k := eccentricity(term)
+		sumL += term.Sin * k * sind(arg)
+		sumR += term.Cos * k * cosd(arg)
+	}
+	for _, term := range moonLatitude {
+		arg := term.D*d + term.M*m + term.Mp*mp + term.F*f
+		sumB += term.Sin * eccentricity(term) * sind(arg)
+	}
+
+	sumL += 3958*sind(a1) + 1962*sind(lp-f) + 318*sind(a2)
+	sumB += -2235*sind(lp) + 382*sind(a3) + 175*sind(a1-f) + 175*sind(a1+f) +
+		127*sind(lp-mp) - 115*sind(lp+mp)
+
+	pos := Ecliptic{
+		Longitude: NormalizeDegrees(lp + sumL/1e6),
+		Latitude:  sumB / 1e6,
+	}
+	return pos, 385000.56 + sumR/1000
+}
+
+// MoonApparentEcliptic returns the apparent ecliptic position of the Moon,
+// corrected for nutation, and its distance in kilometres
+func MoonApparentEcliptic(jde float64) (Ecliptic, float64) {
+	pos, dist := MoonGeocentric(jde)
+	deltaPsi, _ := Nutation(jde)
+	pos.Longitude = NormalizeDegrees(pos.Longitude + deltaPsi)
+	return pos, dist
+}
+
+// MoonApparent returns the apparent geocentric equatorial position of the Moon
+func MoonApparent(jde float64) Equatorial {
+	pos, _ := MoonApparentEcliptic(jde)
+	return pos.ToEquatorial(TrueObliquity(jde))
+}
+
+// MoonParallax returns the equatorial horizontal parallax of the Moon in
+// degrees for a distance in kilometres
+func MoonParallax(distance float64) float64 {
+	return asind(EarthRadiusKm / distance)
+}
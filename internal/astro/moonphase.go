@@ -0,0 +1,109 @@
+package astro
+
+import "math"
+
+// AstronomicalUnitKm is the length of the astronomical unit in kilometres
+const AstronomicalUnitKm = 149597870.7
+
+// Quarter identifies one of the four principal lunar phases
+type Quarter int
+
+const (
+	NewMoon Quarter = iota
+	FirstQuarter
+	FullMoon
+	LastQuarter
+)
+
+// Phase is the instant of a principal lunar phase
+type Phase struct {
+	Quarter Quarter
+	// JD is the Julian day of the phase on the UTC time scale
+	JD float64
+}
+
+// Apsis is a lunar perigee or apogee
+type Apsis struct {
+	Perigee bool
+	// JD is the Julian day of the apsis on the UTC time scale
+	JD float64
+	// Distance is the Earth-Moon distance in kilometres
+	Distance float64
+}
+
+// MoonElongation returns the excess of the apparent geocentric longitude of
+// the Moon over that of the Sun, in degrees in the range [0, 360)
+func MoonElongation(jde float64) float64 {
+	moon, _ := MoonApparentEcliptic(jde)
+	sun, _ := SunApparentEcliptic(jde)
+	return NormalizeDegrees(moon.Longitude - sun.Longitude)
+}
+
+// MoonIllumination returns the illuminated fraction of the Moon's disk
+func MoonIllumination(jde float64) float64 {
+	moon, dist := MoonApparentEcliptic(jde)
+	sun, r := SunApparentEcliptic(jde)
+
+	// Geocentric elongation, then the phase angle seen from the Moon
+	psi := math.Acos(cosd(moon.Latitude) * cosd(moon.Longitude-sun.Longitude))
+	rKm := r * AstronomicalUnitKm
+	i := math.Atan2(rKm*math.Sin(psi), dist-rKm*math.Cos(psi))
+	return (1 + math.Cos(i)) / 2
+}
+
+// MoonPhases finds every principal lunar phase between two Julian days on
+// the UTC time scale
+func MoonPhases(startJD, endJD float64) []Phase {
+	// The elongation grows by 11 to 15 degrees a day, so daily samples
+	// never step over a quarter
+	const step = 1.0
+	quarter := func(jd float64) Quarter {
+		return Quarter(MoonElongation(JDE(jd)) / 90)
+	}
+
+	var phases []Phase
+	prev := quarter(startJD)
+	for jd := startJD; jd < endJD; {
+		next := math.Min(jd+step, endJD)
+		cur := quarter(next)
+		if cur != prev {
+			target := float64(cur) * 90
+			t := bisect(jd, next, func(x float64) float64 {
+				return normalizeSigned(MoonElongation(JDE(x)) - target)
+			})
+			phases = append(phases, Phase{Quarter: cur, JD: t})
+		}
+		jd, prev = next, cur
+	}
+	return phases
+}
+
+// MoonApsides finds every lunar perigee and apogee between two Julian days
+// on the UTC time scale
+func MoonApsides(startJD, endJD float64) []Apsis {
+	const step = 0.25
+	distance := func(jd float64) float64 {
+		_, d := MoonGeocentric(JDE(jd))
+		return d
+	}
+	slope := func(jd float64) float64 {
+		return distance(jd+1e-3) - distance(jd-1e-3)
+	}
+
+	var apsides []Apsis
+	prevSlope := slope(startJD)
+	for jd := startJD; jd < endJD; {
+		next := math.Min(jd+step, endJD)
+		curSlope := slope(next)
+		if (prevSlope < 0) != (curSlope < 0) {
+			t := bisect(jd, next, slope)
+			apsides = append(apsides, Apsis{
+				Perigee:  prevSlope < 0,
+				JD:       t,
+				Distance: distance(t),
+			})
+		}
+		jd, prevSlope = next, curSlope
+	}
+	return apsides
+}
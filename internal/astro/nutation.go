@@ -0,0 +1,26 @@
+package astro
+
+// Nutation returns the nutation in longitude and in obliquity in degrees,
+// using the abridged series from Meeus (accurate to about 0.5")
+func Nutation(jde float64) (deltaPsi, deltaEps float64) {
+	t := JulianCenturies(jde)
+	omega := 125.04452 - 1934.136261*t + 0.0020708*t*t + t*t*t/450000
+	l := 280.4665 + 36000.7698*t
+	lp := 218.3165 + 481267.8813*t
+
+	deltaPsi = -17.20*sind(omega) - 1.32*sind(2*l) - 0.23*sind(2*lp) + 0.21*sind(2*omega)
+	deltaEps = 9.20*cosd(omega) + 0.57*cosd(2*l) + 0.10*cosd(2*lp) - 0.09*cosd(2*omega)
+	return deltaPsi * arcsecToDeg, deltaEps * arcsecToDeg
+}
+
+// MeanObliquity returns the mean obliquity of the ecliptic in degrees
+func MeanObliquity(jde float64) float64 {
+	t := JulianCenturies(jde)
+	return 23.43929111 + (-46.8150*t-0.00059*t*t+0.001813*t*t*t)*arcsecToDeg
+}
+
+// TrueObliquity returns the obliquity of the ecliptic including nutation
+func TrueObliquity(jde float64) float64 {
+	_, deltaEps := Nutation(jde)
+	return MeanObliquity(jde) + deltaEps
+}
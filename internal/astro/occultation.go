@@ -0,0 +1,155 @@
+package astro
+
+import (
+	"math"
+	"sort"
+)
+
+// Limb identifies the side of the Moon at which a body disappears or reappears
+type Limb int
+
+const (
+	DarkLimb Limb = iota
+	BrightLimb
+)
+
+// Occultation is the passage of the Moon in front of a star or planet seen
+// from a site. Times are Julian days on the UTC time scale and refer to the
+// centre of the occulted body.
+type Occultation struct {
+	// Target is the index of the occulted body in the list searched
+	Target                              int
+	Disappearance, Reappearance         float64
+	DisappearanceLimb, ReappearanceLimb Limb
+	// DisappearanceAngle and ReappearanceAngle are the position angles of
+	// the contacts on the Moon's limb, from north through east, in degrees
+	DisappearanceAngle, ReappearanceAngle float64
+	// DisappearanceAltitude and ReappearanceAltitude are the geometric
+	// altitudes of the Moon at the contacts
+	DisappearanceAltitude, ReappearanceAltitude float64
+}
+
+const (
+	// occultationStep is the sampling interval of the geocentric search; the
+	// Moon moves about half a degree an hour against the stars
+	occultationStep = 1.0 / 24
+
+	// occultationReach is the largest geocentric separation at which the
+	// Moon can cover a body seen from somewhere on the Earth: its greatest
+	// horizontal parallax and semidiameter, with a margin for the sampling
+	occultationReach = 1.6
+
+	// occultationWindow bounds the time from the topocentric conjunction to
+	// either contact, and the shift of that conjunction from the geocentric
+	// one
+	occultationWindow = 3.0 / 24
+)
+
+// Occultations finds the occultations by the Moon of a list of bodies seen
+// from a site between two Julian days on the UTC time scale, in order of
+// disappearance. The bodies' positions are taken as geocentric, which suits
+// stars and, to within about a minute, the planets. Occultations that happen
+// while the Moon is below the horizon are included.
+func Occultations(targets []PositionFunc, site Site, startJD, endJD float64) []Occultation {
+	// The Moon is computed once per sample for all the bodies
+	geocentric := func(jd float64) []float64 {
+		jde := JDE(jd)
+		moon := MoonApparent(jde)
+		separations := make([]float64, len(targets))
+		for i, target := range targets {
+			separations[i] = AngularSeparation(moon, target(jde))
+		}
+		return separations
+	}
+
+	var occultations []Occultation
+	prev, cur := geocentric(startJD-occultationWindow-occultationStep), geocentric(startJD-occultationWindow)
+	for jd := startJD - occultationWindow; jd < endJD+occultationWindow; jd += occultationStep {
+		next := geocentric(jd + occultationStep)
+		for i, target := range targets {
+			if cur[i] <= prev[i] && cur[i] < next[i] && cur[i] < occultationReach {
+				if o, ok := occultation(target, site, jd); ok && o.Disappearance >= startJD && o.Disappearance < endJD {
+					o.Target = i
+					occultations = append(occultations, o)
+				}
+			}
+		}
+		prev, cur = cur, next
+	}
+
+	sort.Slice(occultations, func(i, j int) bool {
+		return occultations[i].Disappearance < occultations[j].Disappearance
+	})
+	return occultations
+}
+
+// occultation refines a close geocentric approach of the Moon to a body at
+// jd into an occultation seen from the site. ok is false when the Moon
+// misses the body there.
+func occultation(target PositionFunc, site Site, jd float64) (Occultation, bool) {
+	// excess is the topocentric separation from the Moon's centre less its
+	// topocentric semidiameter, negative while the body is covered
+	excess := func(jd float64) float64 {
+		moon, radius, _ := moonTopocentric(jd, site)
+		return AngularSeparation(moon, target(JDE(jd))) - radius
+	}
+
+	mid := goldenMinimum(jd-occultationWindow, jd+occultationWindow, excess)
+	if excess(mid) >= 0 {
+		return Occultation{}, false
+	}
+
+	o := Occultation{
+		Disappearance: bisect(mid-occultationWindow, mid, excess),
+		Reappearance:  bisect(mid, mid+occultationWindow, excess),
+	}
+	o.DisappearanceLimb, o.DisappearanceAngle, o.DisappearanceAltitude = contact(target, o.Disappearance, site)
+	o.ReappearanceLimb, o.ReappearanceAngle, o.ReappearanceAltitude = contact(target, o.Reappearance, site)
+	return o, true
+}
+
+// moonTopocentric returns the apparent position and semidiameter of the Moon
+// seen from a site, with the local sidereal time
+func moonTopocentric(jd float64, site Site) (Equatorial, float64, float64) {
+	jde := JDE(jd)
+	lst := LocalSiderealTime(jd, site.Longitude)
+	ecl, dist := MoonApparentEcliptic(jde)
+	moon, d := site.Topocentric(ecl.ToEquatorial(TrueObliquity(jde)), dist, lst)
+	return moon, asind(moonEarthRadius * EarthRadiusKm / d), lst
+}
+
+// contact returns the limb, position angle and Moon altitude of a contact.
+// A point on the limb is sunlit when it lies within 90° of the position angle
+// of the Sun seen from the Moon's centre.
+func contact(target PositionFunc, jd float64, site Site) (Limb, float64, float64) {
+	moon, _, lst := moonTopocentric(jd, site)
+	angle := PositionAngle(moon, target(JDE(jd)))
+	limb := DarkLimb
+	if math.Abs(normalizeSigned(angle-PositionAngle(moon, SunApparent(JDE(jd))))) < 90 {
+		limb = BrightLimb
+	}
+	return limb, angle, moon.ToHorizontal(lst, site).Altitude
+}
+
+// PositionAngle returns the direction of b seen from a, measured from north
+// through east, in degrees
+func PositionAngle(a, b Equatorial) float64 {
+	return NormalizeDegrees(atan2d(cosd(b.Dec)*sind(b.RA-a.RA),
+		sind(b.Dec)*cosd(a.Dec)-cosd(b.Dec)*sind(a.Dec)*cosd(b.RA-a.RA)))
+}
+
+// goldenMinimum finds the minimum of f between a and b by golden-section
+// search
+func goldenMinimum(a, b float64, f func(float64) float64) float64 {
+	const invPhi = 0.6180339887498949
+	for b-a > 1e-6 {
+		c := b - (b-a)*invPhi
+		d := a + (b-a)*invPhi
+		if f(c) < f(d) {
+			b = d
+		} else {
+			a = c
+		}
+	}
+	return (a + b) / 2
+}
@@ -0,0 +1,158 @@
+package astro
+
+import "math"
+
+// PlanetPhenomenonKind identifies an opposition, greatest elongation or station
+type PlanetPhenomenonKind int
+
+const (
+	Opposition PlanetPhenomenonKind = iota
+	GreatestElongationEast
+	GreatestElongationWest
+	StationRetrograde
+	StationDirect
+)
+
+// PlanetPhenomenon is the instant of an opposition, greatest elongation or
+// stationary point of a planet
+type PlanetPhenomenon struct {
+	Planet Planet
+	Kind   PlanetPhenomenonKind
+	// JD is the Julian day of the phenomenon on the UTC time scale
+	JD float64
+}
+
+// PlanetAppearance describes how a planet looks from the Earth
+type PlanetAppearance struct {
+	Magnitude float64
+	// AngularDiameter is the apparent equatorial diameter in arcseconds
+	AngularDiameter float64
+	// Distance is the distance from the Earth in AU
+	Distance float64
+	// Elongation is the angular distance from the Sun in degrees
+	Elongation float64
+	// PhaseAngle is the Sun-planet-Earth angle in degrees
+	PhaseAngle float64
+}
+
+// Equatorial semidiameters at a distance of 1 AU, in arcseconds
+var planetSemidiameters = [...]float64{
+	Mercury: 3.36, Venus: 8.41, Mars: 4.68, Jupiter: 98.44,
+	Saturn: 82.73, Uranus: 35.02, Neptune: 33.50,
+}
+
+// PlanetApparentEcliptic returns the apparent geocentric ecliptic position of
+// a planet referred to the true equinox of date, and its distance in AU
+func PlanetApparentEcliptic(p Planet, jde float64) (Ecliptic, float64) {
+	pos, dist := Geocentric(func(t float64) Vector { return PlanetHeliocentric(p, t) }, jde)
+	return ApparentPlace(pos, jde).ToEcliptic(TrueObliquity(jde)), dist
+}
+
+// PlanetAppearanceAt returns the magnitude, size and elongation of a planet
+func PlanetAppearanceAt(p Planet, jde float64) PlanetAppearance {
+	ecl, delta := PlanetApparentEcliptic(p, jde)
+	sun, bigR := SunApparentEcliptic(jde)
+	r := PlanetHeliocentric(p, jde).Length()
+
+	i := math.Acos(math.Max(-1, math.Min(1, (r*r+delta*delta-bigR*bigR)/(2*r*delta)))) * radToDeg
+	elongation := math.Acos(cosd(ecl.Latitude)*cosd(ecl.Longitude-sun.Longitude)) * radToDeg
+
+	return PlanetAppearance{
+		Magnitude:       planetMagnitude(p, r, delta, i, ecl, jde),
+		AngularDiameter: 2 * planetSemidiameters[p] / delta,
+		Distance:        delta,
+		Elongation:      elongation,
+		PhaseAngle:      i,
+	}
+}
+
+// planetMagnitude uses the expressions of the Astronomical Almanac for 1984
+// (Meeus 41.x). Saturn's ring contribution depends on the tilt of the rings
+// towards the Earth; the small term in the Sun-Earth difference of the ring
+// longitudes is neglected.
+func planetMagnitude(p Planet, r, delta, i float64, ecl Ecliptic, jde float64) float64 {
+	base := 5 * math.Log10(r*delta)
+	switch p {
+	case Mercury:
+		return -0.42 + base + 0.0380*i - 0.000273*i*i + 0.000002*i*i*i
+	case Venus:
+		return -4.40 + base + 0.0009*i + 0.000239*i*i - 0.00000065*i*i*i
+	case Mars:
+		return -1.52 + base + 0.016*i
+	case Jupiter:
+		return -9.40 + base + 0.005*i
+	case Saturn:
+		t := JulianCenturies(jde)
+		inc := 28.075216 - 0.012998*t + 0.000004*t*t
+		node := 169.508470 + 1.394681*t + 0.000412*t*t
+		sinB := sind(inc)*cosd(ecl.Latitude)*sind(ecl.Longitude-node) - cosd(inc)*sind(ecl.Latitude)
+		return -8.88 + base - 2.60*math.Abs(sinB) + 1.25*sinB*sinB
+	case Uranus:
+		return -7.19 + base
+	default:
+		return -6.87 + base
+	}
+}
+
+// PlanetPhenomena finds the oppositions (outer planets), greatest
+// elongations (Mercury and Venus) and stationary points of a planet between
+// two Julian days on the UTC time scale
+func PlanetPhenomena(p Planet, startJD, endJD float64) []PlanetPhenomenon {
+	// Daily samples are fine enough: Mercury, the fastest, takes about
+	// three weeks between a station and the next greatest elongation
+	const step, delta = 1.0, 1e-3
+
+	longitude := func(jd float64) float64 {
+		ecl, _ := PlanetApparentEcliptic(p, JDE(jd))
+		return ecl.Longitude
+	}
+	fromSun := func(jd float64) float64 {
+		sun, _ := SunApparentEcliptic(JDE(jd))
+		return normalizeSigned(longitude(jd) - sun.Longitude)
+	}
+	motion := func(jd float64) float64 {
+		return normalizeSigned(longitude(jd+delta) - longitude(jd-delta))
+	}
+	elongationRate := func(jd float64) float64 {
+		return PlanetAppearanceAt(p, JDE(jd+delta)).Elongation - PlanetAppearanceAt(p, JDE(jd-delta)).Elongation
+	}
+	opposition := func(jd float64) float64 {
+		return normalizeSigned(fromSun(jd) - 180)
+	}
+	inner := p == Mercury || p == Venus
+
+	var phenomena []PlanetPhenomenon
+	add := func(kind PlanetPhenomenonKind, jd float64) {
+		phenomena = append(phenomena, PlanetPhenomenon{Planet: p, Kind: kind, JD: jd})
+	}
+
+	prevMotion, prevOpp, prevRate := motion(startJD), opposition(startJD), elongationRate(startJD)
+	for jd := startJD; jd < endJD; {
+		next := math.Min(jd+step, endJD)
+		curMotion, curOpp, curRate := motion(next), opposition(next), elongationRate(next)
+
+		if (prevMotion < 0) != (curMotion < 0) {
+			kind := StationDirect
+			if prevMotion > 0 {
+				kind = StationRetrograde
+			}
+			add(kind, bisect(jd, next, motion))
+		}
+
+		if !inner && prevOpp > 0 && curOpp <= 0 && prevOpp < 90 {
+			add(Opposition, bisect(jd, next, opposition))
+		}
+
+		if inner && prevRate > 0 && curRate <= 0 {
+			t := bisect(jd, next, elongationRate)
+			kind := GreatestElongationEast
+			if fromSun(t) < 0 {
+				kind = GreatestElongationWest
+			}
+			add(kind, t)
+		}
+
+		jd, prevMotion, prevOpp, prevRate = next, curMotion, curOpp, curRate
+	}
+	return phenomena
+}
@@ -0,0 +1,151 @@
+package astro
+
+import "math"
+
+// Planet identifies one of the major planets other than the Earth
+type Planet int
+
+const (
+	Mercury Planet = iota
+	Venus
+	Mars
+	Jupiter
+	Saturn
+	Uranus
+	Neptune
+)
+
+// Planets lists every planet in order of distance from the Sun
+var Planets = []Planet{Mercury, Venus, Mars, Jupiter, Saturn, Uranus, Neptune}
+
+var planetNames = [...]string{"Mercury", "Venus", "Mars", "Jupiter", "Saturn", "Uranus", "Neptune"}
+
+func (p Planet) String() string {
+	return planetNames[p]
+}
+
+// orbitalElements holds Keplerian elements and their rates per Julian
+// century: semi-major axis (AU), eccentricity, inclination, mean longitude,
+// longitude of perihelion and longitude of the ascending node (degrees)
+type orbitalElements struct {
+	a, e, i, l, peri, node                         float64
+	aRate, eRate, iRate, lRate, periRate, nodeRate float64
+}
+
+// Approximate mean elements referred to the J2000 ecliptic and equinox, valid
+// from 1800 to 2050 (Standish, "Keplerian Elements for Approximate Positions
+// of the Major Planets", JPL)
+var planetElements = [...]orbitalElements{
+	Mercury: {0.38709927, 0.20563593, 7.00497902, 252.25032350, 77.45779628, 48.33076593,
+		0.00000037, 0.00001906, -0.00594749, 149472.67411175, 0.16047689, -0.12534081},
+	Venus: {0.72333566, 0.00677672, 3.39467605, 181.97909950, 131.60246718, 76.67984255,
+		0.00000390, -0.00004107, -0.00078890, 58517.81538729, 0.00268329, -0.27769418},
+	Mars: {1.52371034, 0.09339410, 1.84969142, -4.55343205, -23.94362959, 49.55953891,
+		0.00001847, 0.00007882, -0.00813131, 19140.30268499, 0.44441088, -0.29257343},
+	Jupiter: {5.20288700, 0.04838624, 1.30439695, 34.39644051, 14.72847983, 100.47390909,
+		-0.00011607, -0.00013253, -0.00183714, 3034.74612775, 0.21252668, 0.20469106},
+	Saturn: {9.53667594, 0.05386179, 2.48599187, 49.95424423, 92.59887831, 113.66242448,
+		-0.00125060, -0.00050991, 0.00193609, 1222.49362201, -0.41897216, -0.28867794},
+	Uranus: {19.18916464, 0.04725744, 0.77263783, 313.23810451, 170.95427630, 74.01692503,
+		-0.00196176, -0.00004397, -0.00242939, 428.48202785, 0.40805281, 0.04240589},
+	Neptune: {30.06992276, 0.00859048, 1.77004347, -55.12002969, 44.96476227, 131.78422574,
+		0.00026291, 0.00005105, 0.00035372, 218.45945325, -0.32241464, -0.00508664},
+}
+
+// Vector is a rectangular position in AU
+type Vector struct {
+	X, Y, Z float64
+}
+
+func (v Vector) Sub(o Vector) Vector { return Vector{v.X - o.X, v.Y - o.Y, v.Z - o.Z} }
+
+func (v Vector) Length() float64 { return math.Sqrt(v.X*v.X + v.Y*v.Y + v.Z*v.Z) }
+
+// ecliptic returns the spherical coordinates of an ecliptic vector
+func (v Vector) ecliptic() Ecliptic {
+	return Ecliptic{
+		Longitude: NormalizeDegrees(atan2d(v.Y, v.X)),
+		Latitude:  atan2d(v.Z, math.Hypot(v.X, v.Y)),
+	}
+}
+
+// orbitPosition returns the heliocentric position of a body on a Keplerian
+// orbit in the frame of its elements, given the semi-major axis a (AU),
+// eccentricity e, inclination i, argument of perihelion w, longitude of the
+// ascending node node and mean anomaly m (degrees)
+func orbitPosition(a, e, i, w, node, m float64) Vector {
+	ea := solveKepler(e, m)
+	xp := a * (cosd(ea) - e)
+	yp := a * math.Sqrt(1-e*e) * sind(ea)
+
+	cw, sw := cosd(w), sind(w)
+	cn, sn := cosd(node), sind(node)
+	ci, si := cosd(i), sind(i)
+	return Vector{
+		X: (cw*cn-sw*sn*ci)*xp + (-sw*cn-cw*sn*ci)*yp,
+		Y: (cw*sn+sw*cn*ci)*xp + (-sw*sn+cw*cn*ci)*yp,
+		Z: sw*si*xp + cw*si*yp,
+	}
+}
+
+// solveKepler returns the eccentric anomaly in degrees for a mean anomaly m
+// in degrees on an elliptical orbit of eccentricity e
+func solveKepler(e, m float64) float64 {
+	m = normalizeSigned(m)
+	ea := m + e*radToDeg*sind(m)
+	for n := 0; n < 30; n++ {
+		delta := (m - (ea - e*radToDeg*sind(ea))) / (1 - e*cosd(ea))
+		ea += delta
+		if math.Abs(delta) < 1e-9 {
+			break
+		}
+	}
+	return ea
+}
+
+// PlanetHeliocentric returns the heliocentric position of a planet referred
+// to the J2000 ecliptic and equinox
+func PlanetHeliocentric(p Planet, jde float64) Vector {
+	el := planetElements[p]
+	t := JulianCenturies(jde)
+
+	a := el.a + el.aRate*t
+	e := el.e + el.eRate*t
+	i := el.i + el.iRate*t
+	l := el.l + el.lRate*t
+	peri := el.peri + el.periRate*t
+	node := el.node + el.nodeRate*t
+
+	return orbitPosition(a, e, i, peri-node, node, l-peri)
+}
+
+// EarthHeliocentricJ2000 returns the heliocentric position of the Earth
+// referred to the J2000 ecliptic and equinox
+func EarthHeliocentricJ2000(jde float64) Vector {
+	l, b, r := EarthHeliocentric(jde)
+	l -= 1.396971 * JulianCenturies(jde)
+	return Vector{
+		X: r * cosd(b) * cosd(l),
+		Y: r * cosd(b) * sind(l),
+		Z: r * sind(b),
+	}
+}
+
+// lightTimePerAU is the light travel time over one astronomical unit in days
+const lightTimePerAU = 0.0057755183
+
+// Geocentric returns the astrometric geocentric position of a body given
+// its heliocentric J2000 position function, corrected for light time, as
+// J2000 equatorial coordinates with the distance in AU
+func Geocentric(helio func(jde float64) Vector, jde float64) (Equatorial, float64) {
+	earth := EarthHeliocentricJ2000(jde)
+	geo := helio(jde).Sub(earth)
+	geo = helio(jde - lightTimePerAU*geo.Length()).Sub(earth)
+	return geo.ecliptic().ToEquatorial(J2000Obliquity), geo.Length()
+}
+
+// PlanetApparent returns the apparent geocentric position of a planet
+func PlanetApparent(p Planet, jde float64) Equatorial {
+	pos, _ := Geocentric(func(t float64) Vector { return PlanetHeliocentric(p, t) }, jde)
+	return ApparentPlace(pos, jde)
+}
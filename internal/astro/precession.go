@@ -0,0 +1,45 @@
+package astro
+
+// J2000Obliquity is the mean obliquity of the ecliptic at J2000 in degrees
+const J2000Obliquity = 23.4392911
+
+// Precess converts mean equatorial coordinates referred to the J2000 equinox
+// to the mean equinox of the given Julian ephemeris day (Meeus 21.3)
+func Precess(eq Equatorial, jde float64) Equatorial {
+	return PrecessBetween(eq, J2000, jde)
+}
+
+// PrecessBetween converts mean equatorial coordinates referred to the
+// equinox of one Julian ephemeris day to the equinox of another (Meeus 21.2)
+func PrecessBetween(eq Equatorial, fromJDE, toJDE float64) Equatorial {
+	T := JulianCenturies(fromJDE)
+	t := (toJDE - fromJDE) / 36525
+	zeta := ((2306.2181+1.39656*T-0.000139*T*T)*t + (0.30188-0.000344*T)*t*t + 0.017998*t*t*t) * arcsecToDeg
+	z := ((2306.2181+1.39656*T-0.000139*T*T)*t + (1.09468+0.000066*T)*t*t + 0.018203*t*t*t) * arcsecToDeg
+	theta := ((2004.3109-0.85330*T-0.000217*T*T)*t - (0.42665+0.000217*T)*t*t - 0.041833*t*t*t) * arcsecToDeg
+
+	a := cosd(eq.Dec) * sind(eq.RA+zeta)
+	b := cosd(theta)*cosd(eq.Dec)*cosd(eq.RA+zeta) - sind(theta)*sind(eq.Dec)
+	c := sind(theta)*cosd(eq.Dec)*cosd(eq.RA+zeta) + cosd(theta)*sind(eq.Dec)
+	return Equatorial{RA: NormalizeDegrees(atan2d(a, b) + z), Dec: asind(c)}
+}
+
+// ApparentPlace converts J2000 mean equatorial coordinates of a distant
+// object to apparent coordinates of date, applying precession and nutation
+func ApparentPlace(eq Equatorial, jde float64) Equatorial {
+	mean := Precess(eq, jde)
+
+	// Nutation is applied along the ecliptic of date
+	eps := MeanObliquity(jde)
+	ecl := mean.ToEcliptic(eps)
+	deltaPsi, deltaEps := Nutation(jde)
+	ecl.Longitude = NormalizeDegrees(ecl.Longitude + deltaPsi)
+	return ecl.ToEquatorial(eps + deltaEps)
+}
+
+// ToEcliptic converts equatorial coordinates using the obliquity eps
+func (e Equatorial) ToEcliptic(eps float64) Ecliptic {
+	lon := atan2d(sind(e.RA)*cosd(eps)+tand(e.Dec)*sind(eps), cosd(e.RA))
+	lat := asind(sind(e.Dec)*cosd(eps) - cosd(e.Dec)*sind(eps)*sind(e.RA))
+	return Ecliptic{Longitude: NormalizeDegrees(lon), Latitude: lat}
+}
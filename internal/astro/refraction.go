@@ -0,0 +1,33 @@
+package astro
+
+// Refraction returns the atmospheric refraction in degrees for an apparent
+// altitude in degrees, for a pressure of 1010 mbar and a temperature of
+// 10°C (Bennett's formula, Meeus 16.3)
+func Refraction(apparent float64) float64 {
+	if apparent < -1 {
+		return 0
+	}
+	return 1 / tand(apparent+7.31/(apparent+4.4)) / 60
+}
+
+// RefractionFromTrue returns the atmospheric refraction in degrees for an
+// altitude in degrees free of refraction, in the same conditions
+// (Saemundsson's formula, Meeus 16.4)
+func RefractionFromTrue(altitude float64) float64 {
+	if altitude < -1.5 {
+		return 0
+	}
+	return 1.02 / tand(altitude+10.3/(altitude+5.11)) / 60
+}
+
+// ScaleRefraction adjusts a refraction computed for the standard conditions
+// to a pressure in millibars and a temperature in degrees Celsius
+func ScaleRefraction(refraction, pressure, temperature float64) float64 {
+	return refraction * pressure / 1010 * 283 / (273 + temperature)
+}
+
+// Refracted returns the position of a body as it appears through the
+// atmosphere in the standard conditions
+func (h Horizontal) Refracted() Horizontal {
+	return Horizontal{Azimuth: h.Azimuth, Altitude: h.Altitude + RefractionFromTrue(h.Altitude)}
+}
@@ -0,0 +1,115 @@
+package astro
+
+import "math"
+
+// Standard altitudes of the centre of a body at the instant of rising or
+// setting, allowing for refraction and semidiameter
+const (
+	SunStandardAltitude  = -0.8333
+	StarStandardAltitude = -0.5667
+)
+
+// Altitudes of the centre of the Sun at the end of evening civil, nautical
+// and astronomical twilight
+const (
+	CivilTwilightAltitude        = -6.0
+	NauticalTwilightAltitude     = -12.0
+	AstronomicalTwilightAltitude = -18.0
+)
+
+// HorizonEventKind identifies a rising, setting or upper meridian transit
+type HorizonEventKind int
+
+const (
+	Rising HorizonEventKind = iota
+	Setting
+	UpperTransit
+)
+
+// HorizonEvent is a rising, setting or transit found for a site
+type HorizonEvent struct {
+	Kind HorizonEventKind
+	// JD is the Julian day of the event on the UTC time scale
+	JD       float64
+	Position Horizontal
+}
+
+// PositionFunc returns the apparent geocentric position of a body at a
+// Julian ephemeris day
+type PositionFunc func(jde float64) Equatorial
+
+// searchStep is the sampling interval for horizon searches: ten minutes is
+// short enough that no body rises and sets again between two samples
+const searchStep = 10.0 / (24 * 60)
+
+// MoonStandardAltitude returns the standard altitude for moonrise and
+// moonset at a given distance in kilometres
+func MoonStandardAltitude(distance float64) float64 {
+	return 0.7275*MoonParallax(distance) + StarStandardAltitude
+}
+
+// HorizonEvents finds every rising, setting and upper transit of a body
+// at a site between two Julian days on the UTC time scale. h0 is the
+// standard altitude of the body in degrees.
+func HorizonEvents(pos PositionFunc, h0 float64, site Site, startJD, endJD float64) []HorizonEvent {
+	horizontal := func(jd float64) (Horizontal, float64) {
+		eq := pos(JDE(jd))
+		lst := LocalSiderealTime(jd, site.Longitude)
+		return eq.ToHorizontal(lst, site), HourAngle(lst, eq.RA)
+	}
+
+	var events []HorizonEvent
+	prev, prevHA := horizontal(startJD)
+	for jd := startJD; jd < endJD; {
+		next := math.Min(jd+searchStep, endJD)
+		cur, curHA := horizontal(next)
+
+		if (prev.Altitude < h0) != (cur.Altitude < h0) {
+			kind := Rising
+			if cur.Altitude < h0 {
+				kind = Setting
+			}
+			t := bisect(jd, next, func(x float64) float64 {
+				p, _ := horizontal(x)
+				return p.Altitude - h0
+			})
+			p, _ := horizontal(t)
+			events = append(events, HorizonEvent{Kind: kind, JD: t, Position: p})
+		}
+
+		// Upper transit: the hour angle passes through zero while
+		// increasing, as opposed to the jump at the lower meridian
+		if prevHA < 0 && curHA >= 0 && curHA-prevHA < 90 {
+			t := bisect(jd, next, func(x float64) float64 {
+				_, ha := horizontal(x)
+				return ha
+			})
+			p, _ := horizontal(t)
+			events = append(events, HorizonEvent{Kind: UpperTransit, JD: t, Position: p})
+		}
+
+		jd, prev, prevHA = next, cur, curHA
+	}
+	return events
+}
+
+// bisect refines a sign change of f between a and b to about a tenth of a second
+func bisect(a, b float64, f func(float64) float64) float64 {
+	fa := f(a)
+	for b-a > 1e-6 {
+		mid := (a + b) / 2
+		fm := f(mid)
+		if (fa < 0) == (fm < 0) {
+			a, fa = mid, fm
+		} else {
+			b = mid
+		}
+	}
+	return (a + b) / 2
+}
+
+// Altitude returns the geometric altitude in degrees of a body at a site for
+// a Julian day on the UTC time scale
+func Altitude(pos PositionFunc, jd float64, site Site) float64 {
+	return pos(JDE(jd)).ToHorizontal(LocalSiderealTime(jd, site.Longitude), site).Altitude
+}
@@ -0,0 +1,171 @@
+package astro
+
+import "math"
+
+// SatellitePass is a passage of a satellite above a site's horizon. Times
+// are Julian days on the UTC time scale and positions are geometric.
+type SatellitePass struct {
+	Rise, Culmination, Set float64
+	RisePosition           Horizontal
+	CulminationPosition    Horizontal
+	SetPosition            Horizontal
+	// Visible is true when the satellite is sunlit at some point of the pass
+	// while the Sun is below the civil twilight altitude at the site
+	Visible bool
+}
+
+// passStep is the sampling interval of the pass search; a low-orbit pass
+// that stays above the horizon for less than a minute is missed
+const passStep = 1.0 / (24 * 60)
+
+// Look returns the geometric azimuth and altitude of the satellite seen from
+// a site, and its range in km, at a Julian day on the UTC time scale
+func (s *Satellite) Look(jd float64, site Site) (Horizontal, float64, error) {
+	r, _, err := s.PropagateJD(jd)
+	if err != nil {
+		return Horizontal{}, 0, err
+	}
+
+	// TEME and the Earth-fixed frame differ by the Greenwich mean sidereal time
+	theta := MeanSiderealTime(jd) + site.Longitude
+	rhoSin, rhoCos := site.geocentric()
+	d := r.Sub(Vector{
+		EarthRadiusKm * rhoCos * cosd(theta),
+		EarthRadiusKm * rhoCos * sind(theta),
+		EarthRadiusKm * rhoSin,
+	})
+
+	south := sind(site.Latitude)*cosd(theta)*d.X + sind(site.Latitude)*sind(theta)*d.Y - cosd(site.Latitude)*d.Z
+	east := -sind(theta)*d.X + cosd(theta)*d.Y
+	zenith := cosd(site.Latitude)*cosd(theta)*d.X + cosd(site.Latitude)*sind(theta)*d.Y + sind(site.Latitude)*d.Z
+
+	rng := d.Length()
+	return Horizontal{
+		Azimuth:  NormalizeDegrees(atan2d(east, -south)),
+		Altitude: asind(zenith / rng),
+	}, rng, nil
+}
+
+// Sunlit reports whether the satellite is outside the Earth's shadow, taken
+// as a cylinder of one Earth radius behind the Earth
+func (s *Satellite) Sunlit(jd float64) (bool, error) {
+	r, _, err := s.PropagateJD(jd)
+	if err != nil {
+		return false, err
+	}
+
+	sun := SunApparent(JDE(jd))
+	u := Vector{cosd(sun.Dec) * cosd(sun.RA), cosd(sun.Dec) * sind(sun.RA), sind(sun.Dec)}
+	along := r.X*u.X + r.Y*u.Y + r.Z*u.Z
+	if along >= 0 {
+		return true, nil
+	}
+	perpendicular := r.Sub(Vector{along * u.X, along * u.Y, along * u.Z})
+	return perpendicular.Length() > EarthRadiusKm, nil
+}
+
+// Passes finds the passes of the satellite over a site between two Julian
+// days on the UTC time scale that culminate at least minAltitude degrees
+// above the horizon. Passes in progress at either end of the range are
+// skipped. An error is returned if the satellite decays within the range.
+func (s *Satellite) Passes(site Site, startJD, endJD, minAltitude float64) ([]SatellitePass, error) {
+	var err error
+	altitude := func(jd float64) float64 {
+		h, _, e := s.Look(jd, site)
+		if e != nil && err == nil {
+			err = e
+		}
+		return h.Altitude
+	}
+
+	// Samples fall on whole minutes, so overlapping searches find the same
+	// instants
+	first := math.Ceil(startJD / passStep)
+
+	var passes []SatellitePass
+	prev := altitude(first * passStep)
+	rise := math.NaN()
+	best, bestAlt := 0.0, math.Inf(-1)
+	for k := first + 1; k*passStep <= endJD && err == nil; k++ {
+		jd := k * passStep
+		cur := altitude(jd)
+		switch {
+		case prev < 0 && cur >= 0:
+			rise = bisect(jd-passStep, jd, altitude)
+			best, bestAlt = jd, cur
+		case prev >= 0 && cur < 0 && !math.IsNaN(rise):
+			set := bisect(jd-passStep, jd, altitude)
+			culmination := s.culmination(best, site)
+			if p, e := s.pass(site, rise, culmination, set); e != nil {
+				err = e
+			} else if p.CulminationPosition.Altitude >= minAltitude {
+				passes = append(passes, p)
+			}
+			rise = math.NaN()
+		case cur > bestAlt:
+			best, bestAlt = jd, cur
+		}
+		prev = cur
+	}
+	if err != nil {
+		return nil, err
+	}
+	return passes, nil
+}
+
+// culmination refines the highest point of a pass near the sample jd by
+// golden-section search
+func (s *Satellite) culmination(jd float64, site Site) float64 {
+	const invPhi = 0.6180339887498949
+	altitude := func(jd float64) float64 {
+		h, _, _ := s.Look(jd, site)
+		return h.Altitude
+	}
+
+	a, b := jd-passStep, jd+passStep
+	for b-a > 1e-6 {
+		c := b - (b-a)*invPhi
+		d := a + (b-a)*invPhi
+		if altitude(c) > altitude(d) {
+			b = d
+		} else {
+			a = c
+		}
+	}
+	return (a + b) / 2
+}
+
+func (s *Satellite) pass(site Site, rise, culmination, set float64) (SatellitePass, error) {
+	p := SatellitePass{Rise: rise, Culmination: culmination, Set: set}
+	var err error
+	if p.RisePosition, _, err = s.Look(rise, site); err != nil {
+		return p, err
+	}
+	if p.CulminationPosition, _, err = s.Look(culmination, site); err != nil {
+		return p, err
+	}
+	if p.SetPosition, _, err = s.Look(set, site); err != nil {
+		return p, err
+	}
+
+	// Sample the pass for a moment when the satellite catches sunlight
+	// against a dark sky
+	samples := append([]float64{culmination}, rise, set)
+	for jd := rise + passStep/2; jd < set; jd += passStep / 2 {
+		samples = append(samples, jd)
+	}
+	for _, jd := range samples {
+		if Altitude(SunApparent, jd, site) >= CivilTwilightAltitude {
+			continue
+		}
+		sunlit, err := s.Sunlit(jd)
+		if err != nil {
+			return p, err
+		}
+		if sunlit {
+			p.Visible = true
+			break
+		}
+	}
+	return p, nil
+}
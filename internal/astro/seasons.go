@@ -0,0 +1,89 @@
+package astro
+
+import "math"
+
+// SeasonKind identifies an equinox or a solstice
+type SeasonKind int
+
+const (
+	MarchEquinox SeasonKind = iota
+	JuneSolstice
+	SeptemberEquinox
+	DecemberSolstice
+)
+
+// Season is the instant the Sun's apparent longitude reaches a multiple of 90°
+type Season struct {
+	Kind SeasonKind
+	// JD is the Julian day of the season marker on the UTC time scale
+	JD float64
+}
+
+// EarthApsis is a perihelion or aphelion of the Earth
+type EarthApsis struct {
+	Perihelion bool
+	// JD is the Julian day of the apsis on the UTC time scale
+	JD float64
+	// Distance is the Sun-Earth distance in AU
+	Distance float64
+}
+
+// Seasons finds the equinoxes and solstices between two Julian days on the
+// UTC time scale
+func Seasons(startJD, endJD float64) []Season {
+	var seasons []Season
+	for kind := MarchEquinox; kind <= DecemberSolstice; kind++ {
+		for _, jd := range SolarLongitudeCrossings(float64(kind)*90, startJD, endJD) {
+			seasons = append(seasons, Season{Kind: kind, JD: jd})
+		}
+	}
+	return seasons
+}
+
+// EarthApsides finds the perihelia and aphelia of the Earth between two
+// Julian days on the UTC time scale. The distance is that of the centre of
+// the Earth, whose monthly wobble around the Earth-Moon barycentre can move
+// an apsis by a day or more from that of the barycentre's smooth orbit.
+func EarthApsides(startJD, endJD float64) []EarthApsis {
+	// Daily samples resolve the lunar wobble, which makes the distance
+	// flat for several days around each apsis
+	const step, delta = 1.0, 1e-3
+	distance := func(jd float64) float64 {
+		_, r := SunGeometric(JDE(jd))
+		return r
+	}
+	rate := func(jd float64) float64 {
+		return distance(jd+delta) - distance(jd-delta)
+	}
+
+	var apsides []EarthApsis
+	prev := rate(startJD)
+	for jd := startJD; jd < endJD; {
+		next := math.Min(jd+step, endJD)
+		cur := rate(next)
+		if (prev < 0) != (cur < 0) {
+			t := bisect(jd, next, rate)
+			apsides = append(apsides, EarthApsis{Perihelion: prev < 0, JD: t, Distance: distance(t)})
+		}
+		jd, prev = next, cur
+	}
+	return filterApsides(apsides)
+}
+
+// filterApsides keeps the extreme distance of each half year, discarding the
+// minor extrema the lunar wobble creates next to a shallow apsis
+func filterApsides(apsides []EarthApsis) []EarthApsis {
+	var result []EarthApsis
+	for _, a := range apsides {
+		if n := len(result); n > 0 && a.JD-result[n-1].JD < 90 {
+			last := &result[n-1]
+			if a.Perihelion == last.Perihelion &&
+				(a.Perihelion && a.Distance < last.Distance || !a.Perihelion && a.Distance > last.Distance) {
+				*last = a
+			}
+			continue
+		}
+		result = append(result, a)
+	}
+	return result
+}
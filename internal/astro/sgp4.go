@@ -0,0 +1,357 @@
+package astro
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// WGS-72 constants used by SGP4, as in the element sets themselves
+const (
+	sgp4EarthRadius = 6378.135 // km
+	sgp4Mu          = 398600.8 // km³/s²
+	sgp4J2          = 0.001082616
+	sgp4J3          = -0.00000253881
+	sgp4J4          = -0.00000165597
+	sgp4J3OverJ2    = sgp4J3 / sgp4J2
+
+	twoPi         = 2 * math.Pi
+	minutesPerDay = 1440.0
+)
+
+// sgp4Ke is the square root of the gravitational parameter in Earth radii
+// per minute
+var sgp4Ke = 60 / math.Sqrt(sgp4EarthRadius*sgp4EarthRadius*sgp4EarthRadius/sgp4Mu)
+
+// ErrSatelliteDecayed is returned when the elements no longer describe an
+// orbit above the Earth's surface
+var ErrSatelliteDecayed = errors.New("satellite has decayed")
+
+// Satellite holds an element set initialised for the SGP4 propagator, or its
+// SDP4 deep-space extension for orbits with periods of 225 minutes or more.
+// The implementation follows Vallado, Crawford, Hujsak and Kelso, "Revisiting
+// Spacetrack Report #3" (AIAA 2006-6753).
+type Satellite struct {
+	TLE TLE
+
+	// Mean elements at epoch, angles in radians, mean motion in radians per
+	// minute after removal of the Kozai correction
+	ecco, inclo, nodeo, argpo, mo, no, bstar float64
+	gsto                                     float64
+
+	isimp, deepSpace bool
+
+	// Near-Earth secular and drag coefficients
+	aycof, con41, cc1, cc4, cc5, d2, d3, d4, delmo, eta float64
+	argpdot, omgcof, sinmao, t2cof, t3cof, t4cof, t5cof float64
+	x1mth2, x7thm1, mdot, nodedot, xlcof, xmcof, nodecf float64
+
+	ds deepSpaceTerms
+}
+
+// NewSatellite initialises the propagator for an element set
+func NewSatellite(tle TLE) (*Satellite, error) {
+	const (
+		deg   = math.Pi / 180
+		temp4 = 1.5e-12
+		x2o3  = 2.0 / 3.0
+	)
+	s := &Satellite{
+		TLE:   tle,
+		ecco:  tle.Eccentricity,
+		inclo: tle.Inclination * deg,
+		nodeo: tle.RightAscension * deg,
+		argpo: tle.ArgPerigee * deg,
+		mo:    tle.MeanAnomaly * deg,
+		no:    tle.MeanMotion * twoPi / minutesPerDay,
+		bstar: tle.BStar,
+	}
+	if s.no <= 0 || s.ecco >= 1 {
+		return nil, fmt.Errorf("satellite %d: invalid mean motion or eccentricity", tle.CatalogNumber)
+	}
+
+	ss := 78/sgp4EarthRadius + 1
+	qzms2t := math.Pow((120-78)/sgp4EarthRadius, 4)
+
+	// Recover the original mean motion and semi-major axis from the Kozai
+	// mean motion of the element set
+	eccsq := s.ecco * s.ecco
+	omeosq := 1 - eccsq
+	rteosq := math.Sqrt(omeosq)
+	cosio := math.Cos(s.inclo)
+	cosio2 := cosio * cosio
+	ak := math.Pow(sgp4Ke/s.no, x2o3)
+	d1 := 0.75 * sgp4J2 * (3*cosio2 - 1) / (rteosq * omeosq)
+	del := d1 / (ak * ak)
+	adel := ak * (1 - del*del - del*(1.0/3+134*del*del/81))
+	del = d1 / (adel * adel)
+	s.no /= 1 + del
+
+	ao := math.Pow(sgp4Ke/s.no, x2o3)
+	sinio := math.Sin(s.inclo)
+	po := ao * omeosq
+	con42 := 1 - 5*cosio2
+	s.con41 = -con42 - cosio2 - cosio2
+	posq := po * po
+	rp := ao * (1 - s.ecco)
+	s.gsto = MeanSiderealTime(tle.EpochJD) * deg
+
+	if rp < 1 {
+		return nil, fmt.Errorf("satellite %d: %w", tle.CatalogNumber, ErrSatelliteDecayed)
+	}
+
+	// Perigees below 220 km use a simplified drag model
+	s.isimp = rp < 220/sgp4EarthRadius+1
+
+	sfour := ss
+	qzms24 := qzms2t
+	perige := (rp - 1) * sgp4EarthRadius
+	if perige < 156 {
+		sfour = perige - 78
+		if perige < 98 {
+			sfour = 20
+		}
+		qzms24 = math.Pow((120-sfour)/sgp4EarthRadius, 4)
+		sfour = sfour/sgp4EarthRadius + 1
+	}
+	pinvsq := 1 / posq
+
+	tsi := 1 / (ao - sfour)
+	s.eta = ao * s.ecco * tsi
+	etasq := s.eta * s.eta
+	eeta := s.ecco * s.eta
+	psisq := math.Abs(1 - etasq)
+	coef := qzms24 * math.Pow(tsi, 4)
+	coef1 := coef / math.Pow(psisq, 3.5)
+	cc2 := coef1 * s.no * (ao*(1+1.5*etasq+eeta*(4+etasq)) +
+		0.375*sgp4J2*tsi/psisq*s.con41*(8+3*etasq*(8+etasq)))
+	s.cc1 = s.bstar * cc2
+	cc3 := 0.0
+	if s.ecco > 1e-4 {
+		cc3 = -2 * coef * tsi * sgp4J3OverJ2 * s.no * sinio / s.ecco
+	}
+	s.x1mth2 = 1 - cosio2
+	s.cc4 = 2 * s.no * coef1 * ao * omeosq * (s.eta*(2+0.5*etasq) + s.ecco*(0.5+2*etasq) -
+		sgp4J2*tsi/(ao*psisq)*(-3*s.con41*(1-2*eeta+etasq*(1.5-0.5*eeta))+
+			0.75*s.x1mth2*(2*etasq-eeta*(1+etasq))*math.Cos(2*s.argpo)))
+	s.cc5 = 2 * coef1 * ao * omeosq * (1 + 2.75*(etasq+eeta) + eeta*etasq)
+
+	cosio4 := cosio2 * cosio2
+	temp1 := 1.5 * sgp4J2 * pinvsq * s.no
+	temp2 := 0.5 * temp1 * sgp4J2 * pinvsq
+	temp3 := -0.46875 * sgp4J4 * pinvsq * pinvsq * s.no
+	s.mdot = s.no + 0.5*temp1*rteosq*s.con41 + 0.0625*temp2*rteosq*(13-78*cosio2+137*cosio4)
+	s.argpdot = -0.5*temp1*con42 + 0.0625*temp2*(7-114*cosio2+395*cosio4) +
+		temp3*(3-36*cosio2+49*cosio4)
+	xhdot1 := -temp1 * cosio
+	s.nodedot = xhdot1 + (0.5*temp2*(4-19*cosio2)+2*temp3*(3-7*cosio2))*cosio
+	xpidot := s.argpdot + s.nodedot
+	s.omgcof = s.bstar * cc3 * math.Cos(s.argpo)
+	if s.ecco > 1e-4 {
+		s.xmcof = -x2o3 * coef * s.bstar / eeta
+	}
+	s.nodecf = 3.5 * omeosq * xhdot1 * s.cc1
+	s.t2cof = 1.5 * s.cc1
+	if math.Abs(cosio+1) > 1.5e-12 {
+		s.xlcof = -0.25 * sgp4J3OverJ2 * sinio * (3 + 5*cosio) / (1 + cosio)
+	} else {
+		s.xlcof = -0.25 * sgp4J3OverJ2 * sinio * (3 + 5*cosio) / temp4
+	}
+	s.aycof = -0.5 * sgp4J3OverJ2 * sinio
+	s.delmo = math.Pow(1+s.eta*math.Cos(s.mo), 3)
+	s.sinmao = math.Sin(s.mo)
+	s.x7thm1 = 7*cosio2 - 1
+
+	if twoPi/s.no >= 225 {
+		s.deepSpace = true
+		s.isimp = true
+		s.initDeepSpace(tle.EpochJD-2433281.5, eccsq, xpidot)
+	}
+
+	if !s.isimp {
+		cc1sq := s.cc1 * s.cc1
+		s.d2 = 4 * ao * tsi * cc1sq
+		temp := s.d2 * tsi * s.cc1 / 3
+		s.d3 = (17*ao + sfour) * temp
+		s.d4 = 0.5 * temp * ao * tsi * (221*ao + 31*sfour) * s.cc1
+		s.t3cof = s.d2 + 2*cc1sq
+		s.t4cof = 0.25 * (3*s.d3 + s.cc1*(12*s.d2+10*cc1sq))
+		s.t5cof = 0.2 * (3*s.d4 + 12*s.cc1*s.d3 + 6*s.d2*s.d2 + 15*cc1sq*(2*s.d2+cc1sq))
+	}
+
+	if _, _, err := s.Propagate(0); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Propagate returns the position in km and velocity in km/s of the satellite
+// a number of minutes after the element set epoch, in the true equator, mean
+// equinox (TEME) frame of the element set
+func (s *Satellite) Propagate(minutes float64) (r, v Vector, err error) {
+	const (
+		temp4 = 1.5e-12
+		x2o3  = 2.0 / 3.0
+	)
+	t := minutes
+	vkmpersec := sgp4EarthRadius * sgp4Ke / 60
+
+	// Secular gravity and atmospheric drag
+	xmdf := s.mo + s.mdot*t
+	argpdf := s.argpo + s.argpdot*t
+	nodedf := s.nodeo + s.nodedot*t
+	argpm := argpdf
+	mm := xmdf
+	t2 := t * t
+	nodem := nodedf + s.nodecf*t2
+	tempa := 1 - s.cc1*t
+	tempe := s.bstar * s.cc4 * t
+	templ := s.t2cof * t2
+
+	if !s.isimp {
+		delomg := s.omgcof * t
+		delm := s.xmcof * (math.Pow(1+s.eta*math.Cos(xmdf), 3) - s.delmo)
+		temp := delomg + delm
+		mm = xmdf + temp
+		argpm = argpdf - temp
+		t3 := t2 * t
+		t4 := t3 * t
+		tempa -= s.d2*t2 + s.d3*t3 + s.d4*t4
+		tempe += s.bstar * s.cc5 * (math.Sin(mm) - s.sinmao)
+		templ += s.t3cof*t3 + t4*(s.t4cof+t*s.t5cof)
+	}
+
+	nm := s.no
+	em := s.ecco
+	inclm := s.inclo
+	if s.deepSpace {
+		em, argpm, inclm, mm, nodem, nm = s.ds.secular(s, t, em, argpm, inclm, mm, nodem)
+	}
+	if nm <= 0 {
+		return r, v, fmt.Errorf("satellite %d: mean motion below zero", s.TLE.CatalogNumber)
+	}
+
+	am := math.Pow(sgp4Ke/nm, x2o3) * tempa * tempa
+	nm = sgp4Ke / math.Pow(am, 1.5)
+	em -= tempe
+	if em >= 1 || em < -0.001 {
+		return r, v, fmt.Errorf("satellite %d: eccentricity out of range", s.TLE.CatalogNumber)
+	}
+	em = math.Max(em, 1e-6)
+	mm += s.no * templ
+	xlm := mm + argpm + nodem
+
+	nodem = math.Mod(nodem, twoPi)
+	argpm = math.Mod(argpm, twoPi)
+	xlm = math.Mod(xlm, twoPi)
+	mm = math.Mod(xlm-argpm-nodem, twoPi)
+
+	// Lunar-solar periodics
+	ep, xincp, argpp, nodep, mp := em, inclm, argpm, nodem, mm
+	sinip, cosip := math.Sin(inclm), math.Cos(inclm)
+	aycof, xlcof := s.aycof, s.xlcof
+	if s.deepSpace {
+		ep, xincp, nodep, argpp, mp = s.ds.periodics(t, ep, xincp, nodep, argpp, mp)
+		if xincp < 0 {
+			xincp = -xincp
+			nodep += math.Pi
+			argpp -= math.Pi
+		}
+		if ep < 0 || ep > 1 {
+			return r, v, fmt.Errorf("satellite %d: eccentricity out of range", s.TLE.CatalogNumber)
+		}
+		sinip, cosip = math.Sin(xincp), math.Cos(xincp)
+		aycof = -0.5 * sgp4J3OverJ2 * sinip
+		if math.Abs(cosip+1) > 1.5e-12 {
+			xlcof = -0.25 * sgp4J3OverJ2 * sinip * (3 + 5*cosip) / (1 + cosip)
+		} else {
+			xlcof = -0.25 * sgp4J3OverJ2 * sinip * (3 + 5*cosip) / temp4
+		}
+	}
+
+	// Long period periodics
+	axnl := ep * math.Cos(argpp)
+	temp := 1 / (am * (1 - ep*ep))
+	aynl := ep*math.Sin(argpp) + temp*aycof
+	xl := mp + argpp + nodep + temp*xlcof*axnl
+
+	// Kepler's equation
+	u := math.Mod(xl-nodep, twoPi)
+	eo1 := u
+	tem5 := 9999.9
+	var sineo1, coseo1 float64
+	for ktr := 1; math.Abs(tem5) >= 1e-12 && ktr <= 10; ktr++ {
+		sineo1, coseo1 = math.Sin(eo1), math.Cos(eo1)
+		tem5 = 1 - coseo1*axnl - sineo1*aynl
+		tem5 = (u - aynl*coseo1 + axnl*sineo1 - eo1) / tem5
+		tem5 = math.Max(-0.95, math.Min(0.95, tem5))
+		eo1 += tem5
+	}
+
+	// Short period periodics
+	ecose := axnl*coseo1 + aynl*sineo1
+	esine := axnl*sineo1 - aynl*coseo1
+	el2 := axnl*axnl + aynl*aynl
+	pl := am * (1 - el2)
+	if pl < 0 {
+		return r, v, fmt.Errorf("satellite %d: semi-latus rectum below zero", s.TLE.CatalogNumber)
+	}
+	rl := am * (1 - ecose)
+	rdotl := math.Sqrt(am) * esine / rl
+	rvdotl := math.Sqrt(pl) / rl
+	betal := math.Sqrt(1 - el2)
+	temp = esine / (1 + betal)
+	sinu := am / rl * (sineo1 - aynl - axnl*temp)
+	cosu := am / rl * (coseo1 - axnl + aynl*temp)
+	su := math.Atan2(sinu, cosu)
+	sin2u := (cosu + cosu) * sinu
+	cos2u := 1 - 2*sinu*sinu
+	temp = 1 / pl
+	temp1 := 0.5 * sgp4J2 * temp
+	temp2 := temp1 * temp
+
+	con41, x1mth2, x7thm1 := s.con41, s.x1mth2, s.x7thm1
+	if s.deepSpace {
+		cosisq := cosip * cosip
+		con41 = 3*cosisq - 1
+		x1mth2 = 1 - cosisq
+		x7thm1 = 7*cosisq - 1
+	}
+
+	mrt := rl*(1-1.5*temp2*betal*con41) + 0.5*temp1*x1mth2*cos2u
+	su -= 0.25 * temp2 * x7thm1 * sin2u
+	xnode := nodep + 1.5*temp2*cosip*sin2u
+	xinc := xincp + 1.5*temp2*cosip*sinip*cos2u
+	mvt := rdotl - nm*temp1*x1mth2*sin2u/sgp4Ke
+	rvdot := rvdotl + nm*temp1*(x1mth2*cos2u+1.5*con41)/sgp4Ke
+
+	// Orientation vectors
+	sinsu, cossu := math.Sin(su), math.Cos(su)
+	snod, cnod := math.Sin(xnode), math.Cos(xnode)
+	sini, cosi := math.Sin(xinc), math.Cos(xinc)
+	xmx := -snod * cosi
+	xmy := cnod * cosi
+	ux := xmx*sinsu + cnod*cossu
+	uy := xmy*sinsu + snod*cossu
+	uz := sini * sinsu
+	vx := xmx*cossu - cnod*sinsu
+	vy := xmy*cossu - snod*sinsu
+	vz := sini * cossu
+
+	r = Vector{mrt * ux * sgp4EarthRadius, mrt * uy * sgp4EarthRadius, mrt * uz * sgp4EarthRadius}
+	v = Vector{
+		(mvt*ux + rvdot*vx) * vkmpersec,
+		(mvt*uy + rvdot*vy) * vkmpersec,
+		(mvt*uz + rvdot*vz) * vkmpersec,
+	}
+	if mrt < 1 {
+		return r, v, fmt.Errorf("satellite %d: %w", s.TLE.CatalogNumber, ErrSatelliteDecayed)
+	}
+	return r, v, nil
+}
+
+// PropagateJD returns the TEME position and velocity at a Julian day on the
+// UTC time scale
+func (s *Satellite) PropagateJD(jd float64) (r, v Vector, err error) {
+	return s.Propagate((jd - s.TLE.EpochJD) * minutesPerDay)
+}
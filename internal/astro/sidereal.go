@@ -0,0 +1,23 @@
+package astro
+
+// MeanSiderealTime returns the Greenwich mean sidereal time in degrees
+// for a Julian day on the UT time scale
+func MeanSiderealTime(jd float64) float64 {
+	t := JulianCenturies(jd)
+	return NormalizeDegrees(280.46061837 + 360.98564736629*(jd-J2000) +
+		0.000387933*t*t - t*t*t/38710000)
+}
+
+// ApparentSiderealTime returns the Greenwich apparent sidereal time in degrees,
+// the mean sidereal time corrected for the equation of the equinoxes
+func ApparentSiderealTime(jd float64) float64 {
+	jde := JDE(jd)
+	deltaPsi, _ := Nutation(jde)
+	return NormalizeDegrees(MeanSiderealTime(jd) + deltaPsi*cosd(TrueObliquity(jde)))
+}
+
+// LocalSiderealTime returns the local apparent sidereal time in degrees
+// for a longitude measured positive east
+func LocalSiderealTime(jd, longitude float64) float64 {
+	return NormalizeDegrees(ApparentSiderealTime(jd) + longitude)
+}
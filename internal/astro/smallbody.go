@@ -0,0 +1,163 @@
+package astro
+
+import "math"
+
+// gaussK is the Gaussian gravitational constant, the square root of the
+// Sun's gravitational parameter in AU^1.5 per day
+const gaussK = 0.01720209895
+
+// OrbitalElements are heliocentric osculating elements of a comet or
+// asteroid referred to the J2000 ecliptic and equinox. Angles are in
+// degrees; the orbit may be elliptic, parabolic or hyperbolic.
+type OrbitalElements struct {
+	PerihelionDistance float64 // AU
+	Eccentricity       float64
+	Inclination        float64
+	Node               float64
+	ArgPerihelion      float64
+	// PerihelionJDE is the Julian ephemeris day of perihelion passage
+	PerihelionJDE float64
+}
+
+// MagnitudeLaw selects how the brightness of a small body is predicted
+type MagnitudeLaw int
+
+const (
+	// AsteroidMagnitude is the IAU H, G system of Bowell et al. (1989)
+	AsteroidMagnitude MagnitudeLaw = iota
+	// CometMagnitude is the total magnitude H + 5 log Δ + 2.5 K log r
+	CometMagnitude
+)
+
+// SmallBodyPlace is the geocentric position and brightness of a small body
+type SmallBodyPlace struct {
+	// Position is astrometric, referred to the J2000 equator and equinox
+	Position Equatorial
+	// SunDistance and EarthDistance are in AU
+	SunDistance   float64
+	EarthDistance float64
+	Elongation    float64
+	PhaseAngle    float64
+	Magnitude     float64
+}
+
+// Period returns the orbital period in days, or +Inf for open orbits
+func (o OrbitalElements) Period() float64 {
+	if o.Eccentricity >= 1 {
+		return math.Inf(1)
+	}
+	a := o.PerihelionDistance / (1 - o.Eccentricity)
+	return 2 * math.Pi / gaussK * math.Pow(a, 1.5)
+}
+
+// Heliocentric returns the position referred to the J2000 ecliptic in AU
+func (o OrbitalElements) Heliocentric(jde float64) Vector {
+	t := jde - o.PerihelionJDE
+	if p := o.Period(); !math.IsInf(p, 1) {
+		// Solve within the revolution nearest perihelion
+		t -= p * math.Round(t/p)
+	}
+	xp, yp := o.perifocal(t)
+
+	cw, sw := cosd(o.ArgPerihelion), sind(o.ArgPerihelion)
+	cn, sn := cosd(o.Node), sind(o.Node)
+	ci, si := cosd(o.Inclination), sind(o.Inclination)
+	return Vector{
+		X: (cw*cn-sw*sn*ci)*xp + (-sw*cn-cw*sn*ci)*yp,
+		Y: (cw*sn+sw*cn*ci)*xp + (-sw*sn+cw*cn*ci)*yp,
+		Z: sw*si*xp + cw*si*yp,
+	}
+}
+
+// perifocal returns the position in the orbital plane, x towards perihelion,
+// t days after perihelion. It solves the universal form of Kepler's equation
+// with the Laguerre-Conway iteration, which converges for every eccentricity.
+func (o OrbitalElements) perifocal(t float64) (x, y float64) {
+	q := o.PerihelionDistance
+	alpha := (1 - o.Eccentricity) / q // reciprocal semi-major axis
+	v0 := gaussK * math.Sqrt((1+o.Eccentricity)/q)
+	target := gaussK * t
+
+	// f(chi) is the time equation, and its first and second derivatives are
+	// the radius and its rate of change with respect to chi
+	f := func(chi float64) (f, r, dr float64) {
+		z := alpha * chi * chi
+		c, s := stumpff(z)
+		f = q*chi + (1-alpha*q)*chi*chi*chi*s - target
+		r = q + (1-alpha*q)*chi*chi*c
+		dr = (1 - alpha*q) * chi * (1 - z*s)
+		return f, r, dr
+	}
+
+	const n = 5.0
+	chi := target / q
+	if alpha > 0 {
+		chi = target * alpha
+	}
+	for i := 0; i < 100; i++ {
+		fv, r, dr := f(chi)
+		root := math.Sqrt(math.Abs((n-1)*(n-1)*r*r - n*(n-1)*fv*dr))
+		if r < 0 {
+			root = -root
+		}
+		delta := n * fv / (r + root)
+		chi -= delta
+		if math.Abs(delta) < 1e-12*math.Max(1, math.Abs(chi)) {
+			break
+		}
+	}
+
+	z := alpha * chi * chi
+	c, s := stumpff(z)
+	fLagrange := 1 - chi*chi*c/q
+	gLagrange := t - chi*chi*chi*s/gaussK
+	return fLagrange * q, gLagrange * v0
+}
+
+// stumpff returns the Stumpff functions C(z) and S(z)
+func stumpff(z float64) (c, s float64) {
+	switch {
+	case z > 1e-3:
+		sz := math.Sqrt(z)
+		return (1 - math.Cos(sz)) / z, (sz - math.Sin(sz)) / (sz * z)
+	case z < -1e-3:
+		sz := math.Sqrt(-z)
+		return (math.Cosh(sz) - 1) / -z, (math.Sinh(sz) - sz) / (sz * -z)
+	default:
+		// Series expansions avoid cancellation near the parabola
+		return 1.0/2 - z/24 + z*z/720 - z*z*z/40320,
+			1.0/6 - z/120 + z*z/5040 - z*z*z/362880
+	}
+}
+
+// SmallBodyAt returns the astrometric geocentric place of a small body at a
+// Julian ephemeris day, with the magnitude predicted by the given law from
+// the absolute magnitude h and slope parameter (G for asteroids, K for comets)
+func SmallBodyAt(o OrbitalElements, law MagnitudeLaw, h, slope, jde float64) SmallBodyPlace {
+	pos, delta := Geocentric(o.Heliocentric, jde)
+	r := o.Heliocentric(jde - lightTimePerAU*delta).Length()
+	earth := EarthHeliocentricJ2000(jde).Length()
+
+	place := SmallBodyPlace{
+		Position:      pos,
+		SunDistance:   r,
+		EarthDistance: delta,
+		Elongation:    math.Acos(clamp((earth*earth+delta*delta-r*r)/(2*earth*delta))) * radToDeg,
+		PhaseAngle:    math.Acos(clamp((r*r+delta*delta-earth*earth)/(2*r*delta))) * radToDeg,
+	}
+
+	switch law {
+	case CometMagnitude:
+		place.Magnitude = h + 5*math.Log10(delta) + 2.5*slope*math.Log10(r)
+	default:
+		tanHalf := math.Tan(place.PhaseAngle * degToRad / 2)
+		phi1 := math.Exp(-3.33 * math.Pow(tanHalf, 0.63))
+		phi2 := math.Exp(-1.87 * math.Pow(tanHalf, 1.22))
+		place.Magnitude = h + 5*math.Log10(r*delta) - 2.5*math.Log10((1-slope)*phi1+slope*phi2)
+	}
+	return place
+}
+
+func clamp(x float64) float64 {
+	return math.Max(-1, math.Min(1, x))
+}
@@ -0,0 +1,154 @@
+package astro
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TLE is a NORAD two-line element set. Angles are in degrees and the mean
+// motion in revolutions per day, as written in the element set.
+type TLE struct {
+	Name          string
+	CatalogNumber int
+	// EpochJD is the Julian day of the element set epoch on the UTC time scale
+	EpochJD float64
+	// MeanMotionDot is half the first derivative of the mean motion in
+	// revolutions per day squared; it is not used by SGP4
+	MeanMotionDot  float64
+	BStar          float64
+	Inclination    float64
+	RightAscension float64
+	Eccentricity   float64
+	ArgPerigee     float64
+	MeanAnomaly    float64
+	MeanMotion     float64
+}
+
+// ParseTLEs reads element sets in the two- or three-line format, where an
+// optional line with the satellite name precedes each pair of element lines
+func ParseTLEs(r io.Reader) ([]TLE, error) {
+	var tles []TLE
+	var name, line1 string
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), " \r")
+		switch {
+		case strings.TrimSpace(line) == "":
+			continue
+		case strings.HasPrefix(line, "1 ") && line1 == "":
+			line1 = line
+		case strings.HasPrefix(line, "2 ") && line1 != "":
+			tle, err := ParseTLE(name, line1, line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			tles = append(tles, tle)
+			name, line1 = "", ""
+		case line1 == "":
+			name = strings.TrimSpace(strings.TrimPrefix(line, "0 "))
+		default:
+			return nil, fmt.Errorf("line %d: expected line 2 of %q", lineNo, name)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if line1 != "" {
+		return nil, fmt.Errorf("missing line 2 after %q", line1)
+	}
+	return tles, nil
+}
+
+// ParseTLE parses one element set. Columns follow the NORAD format; the
+// checksums are not verified.
+func ParseTLE(name, line1, line2 string) (TLE, error) {
+	if len(line1) < 61 || len(line2) < 63 {
+		return TLE{}, fmt.Errorf("element lines too short")
+	}
+
+	field := func(line string, from, to int) string {
+		return strings.TrimSpace(line[from-1 : min(to, len(line))])
+	}
+
+	var err error
+	number := func(s string) float64 {
+		if err != nil {
+			return 0
+		}
+		var v float64
+		v, err = strconv.ParseFloat(s, 64)
+		return v
+	}
+	exponential := func(s string) float64 {
+		if err != nil {
+			return 0
+		}
+		var v float64
+		v, err = parseExponential(s)
+		return v
+	}
+
+	tle := TLE{Name: name}
+	tle.CatalogNumber, err = strconv.Atoi(field(line1, 3, 7))
+	if err != nil {
+		return TLE{}, fmt.Errorf("catalog number: %w", err)
+	}
+	if tle.Name == "" {
+		tle.Name = strconv.Itoa(tle.CatalogNumber)
+	}
+
+	year := int(number(field(line1, 19, 20)))
+	day := number(field(line1, 21, 32))
+	tle.MeanMotionDot = number(field(line1, 34, 43))
+	tle.BStar = exponential(field(line1, 54, 61))
+
+	tle.Inclination = number(field(line2, 9, 16))
+	tle.RightAscension = number(field(line2, 18, 25))
+	tle.Eccentricity = number("0." + field(line2, 27, 33))
+	tle.ArgPerigee = number(field(line2, 35, 42))
+	tle.MeanAnomaly = number(field(line2, 44, 51))
+	tle.MeanMotion = number(field(line2, 53, 63))
+	if err != nil {
+		return TLE{}, fmt.Errorf("satellite %d: %w", tle.CatalogNumber, err)
+	}
+
+	// Two-digit years from 57 onwards are in the twentieth century
+	if year < 57 {
+		year += 2000
+	} else {
+		year += 1900
+	}
+	// Element set days count from 0.0 January
+	tle.EpochJD = JulianDay(time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)) - 1 + day
+	return tle, nil
+}
+
+// parseExponential reads the assumed-decimal notation of element sets, where
+// " 12345-3" stands for 0.12345e-3
+func parseExponential(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 {
+		return 0, fmt.Errorf("invalid exponential field %q", s)
+	}
+	mantissa, exponent := s[:len(s)-2], s[len(s)-2:]
+
+	sign := ""
+	if mantissa != "" && (mantissa[0] == '-' || mantissa[0] == '+') {
+		sign, mantissa = mantissa[:1], mantissa[1:]
+	}
+	m, err := strconv.ParseFloat(sign+"0."+mantissa, 64)
+	if err != nil {
+		return 0, err
+	}
+	e, err := strconv.Atoi(exponent)
+	if err != nil {
+		return 0, err
+	}
+	return m * math.Pow(10, float64(e)), nil
+}
@@ -0,0 +1,31 @@
+package astro
+
+import "math"
+
+// HeliocentricCorrection returns the light time in days to add to a Julian
+// day to refer an observation of a distant object at the J2000 position eq
+// to the centre of the Sun, giving the heliocentric Julian day
+func HeliocentricCorrection(eq Equatorial, jd float64) float64 {
+	jde := JDE(jd)
+	sun, r := SunGeometric(jde)
+	star := eq.ToEcliptic(J2000Obliquity)
+	return -lightTimePerAU * r * cosd(star.Latitude) * cosd(PrecessLongitude(star.Longitude, jde)-sun.Longitude)
+}
+
+// EclipsingMinima returns the Julian days on the UTC time scale of the
+// primary minima of an eclipsing binary at the J2000 position eq between two
+// Julian days. The ephemeris gives the heliocentric Julian day of one
+// minimum and the period in days.
+func EclipsingMinima(eq Equatorial, epoch, period, startJD, endJD float64) []float64 {
+	var minima []float64
+	// The heliocentric correction never exceeds ten minutes, so one extra
+	// cycle on each side catches minima near the ends of the range
+	for e := math.Floor((startJD-epoch)/period) - 1; epoch+e*period <= endJD+period; e++ {
+		hjd := epoch + e*period
+		jd := hjd - HeliocentricCorrection(eq, hjd)
+		if jd >= startJD && jd < endJD {
+			minima = append(minima, jd)
+		}
+	}
+	return minima
+}
@@ -0,0 +1,21 @@
+package domain
+
+// AuroraDetails describes a spell of darkness during which geomagnetic
+// activity may bring the aurora into view of the observer. Latitudes are
+// geomagnetic, in degrees.
+type AuroraDetails struct {
+	MaxKpIndex float64 `json:"max_kp_index"`
+	// Forecast is true when part of the spell relies on the Kp predicted for
+	// the arrival of a coronal mass ejection rather than on observations
+	Forecast            bool    `json:"forecast"`
+	GeomagneticLatitude float64 `json:"geomagnetic_latitude_deg"`
+	// BoundaryLatitude is the equatorward edge of the auroral oval at
+	// MaxKpIndex
+	BoundaryLatitude float64 `json:"boundary_latitude_deg"`
+	// Overhead is true when the observer is under the oval; otherwise the
+	// aurora may only be seen low over the poleward horizon
+	Overhead bool `json:"overhead"`
+	// ActivityIDs holds the DONKI storms and coronal mass ejections the
+	// Kp comes from
+	ActivityIDs []string `json:"activity_ids"`
+}
@@ -0,0 +1,16 @@
+package domain
+
+// CalendarDetails describes an event imported from an iCalendar feed
+type CalendarDetails struct {
+	// UID is the identifier the feed gives the event, shared by the
+	// occurrences of recurring events
+	UID string `json:"uid"`
+	// Calendar is the name of the feed, when it gives one
+	Calendar   string   `json:"calendar,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	URL        string   `json:"url,omitempty"`
+	// AllDay is true for events given by date rather than time, which
+	// follow the observer's time zone
+	AllDay    bool `json:"all_day"`
+	Recurring bool `json:"recurring"`
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// ConjunctionDetails describes the close approach of two bodies in the sky
+type ConjunctionDetails struct {
+	Bodies []string `json:"bodies"`
+	// SeparationDeg is the minimum angular separation in degrees, reached
+	// at MinimumTime
+	SeparationDeg float64   `json:"separation_deg"`
+	MinimumTime   time.Time `json:"minimum_time"`
+	// ElongationDeg is the angular distance of the pair from the Sun
+	ElongationDeg float64 `json:"elongation_deg"`
+}
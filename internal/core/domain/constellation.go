@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"astralis/internal/astro"
+)
+
+//go:embed constellations.dat
+var constellationsData []byte
+
+// b1875 is the Julian ephemeris day of the equinox of B1875.0, to which the
+// constellation boundaries are referred
+const b1875 = 2405889.258550475
+
+// Constellation is one of the 88 constellations defined by the IAU
+type Constellation struct {
+	// Abbreviation is the three-letter IAU abbreviation, such as "UMa"
+	Abbreviation string `json:"abbreviation"`
+	Name         string `json:"name"`
+}
+
+var constellationNames = map[string]string{
+	"And": "Andromeda", "Ant": "Antlia", "Aps": "Apus", "Aqr": "Aquarius",
+	"Aql": "Aquila", "Ara": "Ara", "Ari": "Aries", "Aur": "Auriga",
+	"Boo": "Boötes", "Cae": "Caelum", "Cam": "Camelopardalis", "Cnc": "Cancer",
+	"CVn": "Canes Venatici", "CMa": "Canis Major", "CMi": "Canis Minor", "Cap": "Capricornus",
+	"Car": "Carina", "Cas": "Cassiopeia", "Cen": "Centaurus", "Cep": "Cepheus",
+	"Cet": "Cetus", "Cha": "Chamaeleon", "Cir": "Circinus", "Col": "Columba",
+	"Com": "Coma Berenices", "CrA": "Corona Australis", "CrB": "Corona Borealis", "Crv": "Corvus",
+	"Crt": "Crater", "Cru": "Crux", "Cyg": "Cygnus", "Del": "Delphinus",
+	"Dor": "Dorado", "Dra": "Draco", "Equ": "Equuleus", "Eri": "Eridanus",
+	"For": "Fornax", "Gem": "Gemini", "Gru": "Grus", "Her": "Hercules",
+	"Hor": "Horologium", "Hya": "Hydra", "Hyi": "Hydrus", "Ind": "Indus",
+	"Lac": "Lacerta", "Leo": "Leo", "LMi": "Leo Minor", "Lep": "Lepus",
+	"Lib": "Libra", "Lup": "Lupus", "Lyn": "Lynx", "Lyr": "Lyra",
+	"Men": "Mensa", "Mic": "Microscopium", "Mon": "Monoceros", "Mus": "Musca",
+	"Nor": "Norma", "Oct": "Octans", "Oph": "Ophiuchus", "Ori": "Orion",
+	"Pav": "Pavo", "Peg": "Pegasus", "Per": "Perseus", "Phe": "Phoenix",
+	"Pic": "Pictor", "Psc": "Pisces", "PsA": "Piscis Austrinus", "Pup": "Puppis",
+	"Pyx": "Pyxis", "Ret": "Reticulum", "Sge": "Sagitta", "Sgr": "Sagittarius",
+	"Sco": "Scorpius", "Scl": "Sculptor", "Sct": "Scutum", "Ser": "Serpens",
+	"Sex": "Sextans", "Tau": "Taurus", "Tel": "Telescopium", "Tri": "Triangulum",
+	"TrA": "Triangulum Australe", "Tuc": "Tucana", "UMa": "Ursa Major", "UMi": "Ursa Minor",
+	"Vel": "Vela", "Vir": "Virgo", "Vol": "Volans", "Vul": "Vulpecula",
+}
+
+// boundary is a row of the boundary table, in degrees
+type boundary struct {
+	raLow, raHigh, decLow float64
+	abbreviation          string
+}
+
+var (
+	boundariesOnce sync.Once
+	boundaries     []boundary
+)
+
+func parseBoundaries(data []byte) ([]boundary, error) {
+	var rows []boundary
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("boundary %q: want 4 fields", line)
+		}
+		var values [3]float64
+		for i := range values {
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("boundary %q: %w", line, err)
+			}
+			values[i] = v
+		}
+		if _, ok := constellationNames[fields[3]]; !ok {
+			return nil, fmt.Errorf("boundary %q: unknown constellation", line)
+		}
+		rows = append(rows, boundary{
+			raLow:        values[0] * 15,
+			raHigh:       values[1] * 15,
+			decLow:       values[2],
+			abbreviation: fields[3],
+		})
+	}
+	return rows, scanner.Err()
+}
+
+// ConstellationAt returns the constellation containing a position given in
+// degrees and referred to the mean equinox of the Julian ephemeris day jde.
+// Use astro.J2000 for catalog positions.
+func ConstellationAt(eq astro.Equatorial, jde float64) Constellation {
+	boundariesOnce.Do(func() {
+		var err error
+		if boundaries, err = parseBoundaries(constellationsData); err != nil {
+			panic(fmt.Sprintf("constellation boundaries: %v", err))
+		}
+	})
+
+	pos := astro.PrecessBetween(eq, jde, b1875)
+	for _, b := range boundaries {
+		if pos.Dec >= b.decLow && pos.RA >= b.raLow && pos.RA < b.raHigh {
+			return Constellation{Abbreviation: b.abbreviation, Name: constellationNames[b.abbreviation]}
+		}
+	}
+	// The last row covers the whole sky south of the pole
+	return Constellation{Abbreviation: "Oct", Name: constellationNames["Oct"]}
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+
+	"astralis/internal/astro"
+)
+
+func TestConstellationAt(t *testing.T) {
+	// The sample positions published with the boundary table, referred to
+	// the equinox of B1950
+	const b1950 = 2433282.4235
+
+	tests := []struct {
+		raHours, dec float64
+		jde          float64
+		want         string
+	}{
+		{9.0, 65.0, b1950, "UMa"},
+		{23.0, -20.0, b1950, "Aqr"},
+		{5.12, 9.12, b1950, "Ori"},
+		{9.4555, -19.9, b1950, "Hya"},
+		{12.8888, 22.0, b1950, "Com"},
+		{15.6687, -12.1234, b1950, "Lib"},
+		{19.0, -40.0, b1950, "CrA"},
+		{6.2222, -81.1234, b1950, "Men"},
+		// Polaris and Sigma Octantis, either side of the poles
+		{2.5303, 89.2641, astro.J2000, "UMi"},
+		{21.1464, -88.9565, astro.J2000, "Oct"},
+		// Betelgeuse and Rigel
+		{5.9195, 7.4071, astro.J2000, "Ori"},
+		{5.2423, -8.2016, astro.J2000, "Ori"},
+	}
+
+	for _, tt := range tests {
+		got := ConstellationAt(astro.Equatorial{RA: tt.raHours * 15, Dec: tt.dec}, tt.jde)
+		if got.Abbreviation != tt.want || got.Name != constellationNames[tt.want] {
+			t.Errorf("ConstellationAt(%vh, %v°) = %+v, want %v", tt.raHours, tt.dec, got, tt.want)
+		}
+	}
+}
+
+func TestParseBoundaries(t *testing.T) {
+	rows, err := parseBoundaries(constellationsData)
+	if err != nil {
+		t.Fatalf("parseBoundaries() error = %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		seen[row.abbreviation] = true
+	}
+	if len(rows) != 357 || len(seen) != 88 {
+		t.Errorf("parseBoundaries() = %d rows covering %d constellations, want 357 covering 88", len(rows), len(seen))
+	}
+}
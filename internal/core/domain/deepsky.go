@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// DeepSkyKind is the nature of a deep-sky object
+type DeepSkyKind string
+
+const (
+	Galaxy           DeepSkyKind = "GALAXY"
+	GlobularCluster  DeepSkyKind = "GLOBULAR_CLUSTER"
+	OpenCluster      DeepSkyKind = "OPEN_CLUSTER"
+	EmissionNebula   DeepSkyKind = "EMISSION_NEBULA"
+	ReflectionNebula DeepSkyKind = "REFLECTION_NEBULA"
+	PlanetaryNebula  DeepSkyKind = "PLANETARY_NEBULA"
+	SupernovaRemnant DeepSkyKind = "SUPERNOVA_REMNANT"
+	StarCloud        DeepSkyKind = "STAR_CLOUD"
+	DoubleStar       DeepSkyKind = "DOUBLE_STAR"
+	Asterism         DeepSkyKind = "ASTERISM"
+)
+
+// DeepSkyDetails describes the season during which a deep-sky object
+// culminates in astronomical darkness. The event's start and end times are
+// the culminations on the first and last nights of the season.
+type DeepSkyDetails struct {
+	// Designation is the catalog number, such as "M31" or "NGC 869"
+	Designation string `json:"designation"`
+	Name        string `json:"name,omitempty"`
+	// Catalog is "Messier", "NGC" or "IC"
+	Catalog   string      `json:"catalog"`
+	Kind      DeepSkyKind `json:"kind"`
+	Magnitude float64     `json:"magnitude"`
+	// MajorAxisArcmin and MinorAxisArcmin are the apparent size of the object
+	MajorAxisArcmin float64 `json:"major_axis_arcmin"`
+	MinorAxisArcmin float64 `json:"minor_axis_arcmin"`
+	Constellation   string  `json:"constellation"`
+	// RightAscension and Declination are the J2000 position in degrees
+	RightAscension float64 `json:"right_ascension_deg"`
+	Declination    float64 `json:"declination_deg"`
+	// CulminationAltitudeDeg is the altitude of the object on the meridian
+	CulminationAltitudeDeg float64 `json:"culmination_altitude_deg"`
+	// Nights is the number of nights of the season
+	Nights int `json:"nights"`
+	// BestCulmination is the culmination nearest the middle of the night
+	BestCulmination time.Time `json:"best_culmination"`
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// EclipseKind classifies an eclipse
+type EclipseKind string
+
+const (
+	TotalEclipse     EclipseKind = "TOTAL"
+	AnnularEclipse   EclipseKind = "ANNULAR"
+	HybridEclipse    EclipseKind = "HYBRID"
+	PartialEclipse   EclipseKind = "PARTIAL"
+	PenumbralEclipse EclipseKind = "PENUMBRAL"
+)
+
+// EclipseBody identifies the body being eclipsed
+type EclipseBody string
+
+const (
+	SolarEclipse EclipseBody = "SUN"
+	LunarEclipse EclipseBody = "MOON"
+)
+
+// EclipseContact is a named contact of an eclipse, such as P1 or C2
+type EclipseContact struct {
+	Name string    `json:"name"`
+	Time time.Time `json:"time"`
+}
+
+// EclipseDetails describes the circumstances of a solar or lunar eclipse
+type EclipseDetails struct {
+	Body EclipseBody `json:"body"`
+	// Kind is the classification of the eclipse as a whole, LocalKind what
+	// the observer sees (solar eclipses only)
+	Kind      EclipseKind `json:"kind"`
+	LocalKind EclipseKind `json:"local_kind,omitempty"`
+	Greatest  time.Time   `json:"greatest"`
+	// Magnitude is the fraction of the Sun's diameter covered at the
+	// observer, or the umbral magnitude of a lunar eclipse (penumbral
+	// magnitude for penumbral eclipses)
+	Magnitude float64 `json:"magnitude"`
+	// Obscuration is the fraction of the Sun's area covered at the observer
+	Obscuration float64          `json:"obscuration,omitempty"`
+	Gamma       float64          `json:"gamma"`
+	Contacts    []EclipseContact `json:"contacts,omitempty"`
+	// Visible reports whether any part of the eclipse can be seen from the
+	// observer's location
+	Visible bool `json:"visible"`
+}
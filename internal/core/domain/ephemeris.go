@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// Ephemeris is a table of the positions of a body seen by an observer
+type Ephemeris struct {
+	// Body is the ID the body was requested with, and Name the name the
+	// ephemeris service gives it
+	Body     string         `json:"body"`
+	Name     string         `json:"name"`
+	Observer Observer       `json:"observer"`
+	Source   string         `json:"source"`
+	Rows     []EphemerisRow `json:"rows"`
+}
+
+// EphemerisRow is the position of a body at one instant. RA and Dec are
+// astrometric ICRF coordinates, Azimuth and Altitude apparent ones without
+// refraction, all in degrees.
+type EphemerisRow struct {
+	Time      time.Time `json:"time"`
+	RA        float64   `json:"ra_deg"`
+	Dec       float64   `json:"dec_deg"`
+	Azimuth   float64   `json:"azimuth_deg"`
+	Altitude  float64   `json:"altitude_deg"`
+	Magnitude *float64  `json:"magnitude,omitempty"`
+	// DistanceAU is the distance from the observer and RangeRateKmS its
+	// rate of change, positive when the body recedes
+	DistanceAU   float64 `json:"distance_au"`
+	RangeRateKmS float64 `json:"range_rate_km_s"`
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -12,9 +14,58 @@ const (
 	Eclipse      EventType = "ECLIPSE"
 	Conjunction  EventType = "CONJUNCTION"
 	Transit      EventType = "TRANSIT"
-	Other        EventType = "OTHER"
+	Rise         EventType = "RISE"
+	Set          EventType = "SET"
+	LunarPhase   EventType = "LUNAR_PHASE"
+	Perigee      EventType = "PERIGEE"
+	Apogee       EventType = "APOGEE"
+	Opposition   EventType = "OPPOSITION"
+	Elongation   EventType = "GREATEST_ELONGATION"
+	Station      EventType = "STATION"
+	Twilight     EventType = "TWILIGHT"
+	Darkness     EventType = "DARKNESS"
+	DarkWindow   EventType = "DARK_WINDOW"
+	Equinox      EventType = "EQUINOX"
+	Solstice     EventType = "SOLSTICE"
+	Perihelion   EventType = "PERIHELION"
+	Aphelion     EventType = "APHELION"
+	Satellite    EventType = "SATELLITE_PASS"
+	Brightening  EventType = "BRIGHTENING"
+	Occultation  EventType = "OCCULTATION"
+	DeepSky      EventType = "DEEP_SKY"
+	VariableStar EventType = "VARIABLE_STAR_MINIMUM"
+
+	CoronalMassEjection      EventType = "CORONAL_MASS_EJECTION"
+	SolarFlare               EventType = "SOLAR_FLARE"
+	GeomagneticStorm         EventType = "GEOMAGNETIC_STORM"
+	SolarEnergeticParticle   EventType = "SOLAR_ENERGETIC_PARTICLE"
+	InterplanetaryShock      EventType = "INTERPLANETARY_SHOCK"
+	HighSpeedStream          EventType = "HIGH_SPEED_STREAM"
+	RadiationBeltEnhancement EventType = "RADIATION_BELT_ENHANCEMENT"
+	Aurora                   EventType = "AURORA"
+
+	CloseApproach EventType = "CLOSE_APPROACH"
+	Fireball      EventType = "FIREBALL"
+	Launch        EventType = "LAUNCH"
+
+	Other EventType = "OTHER"
 )
 
+// eventTypes lists the event types above
+var eventTypes = []EventType{
+	MeteorShower, Eclipse, Conjunction, Transit, Rise, Set, LunarPhase, Perigee, Apogee,
+	Opposition, Elongation, Station, Twilight, Darkness, DarkWindow, Equinox, Solstice,
+	Perihelion, Aphelion, Satellite, Brightening, Occultation, DeepSky, VariableStar,
+	CoronalMassEjection, SolarFlare, GeomagneticStorm, SolarEnergeticParticle,
+	InterplanetaryShock, HighSpeedStream, RadiationBeltEnhancement, Aurora,
+	CloseApproach, Fireball, Launch, Other,
+}
+
+// IsValid checks if the event type is one of the known types
+func (t EventType) IsValid() bool {
+	return slices.Contains(eventTypes, t)
+}
+
 // Event represents an astronomical event
 type Event struct {
 	ID          string    `json:"id"`
@@ -26,6 +77,50 @@ type Event struct {
 	Visibility  string    `json:"visibility,omitempty"`
 	Location    string    `json:"location,omitempty"`
 	Source      string    `json:"source"`
+	// Related links the event to the events that caused it or followed
+	// from it
+	Related []EventLink `json:"related,omitempty"`
+
+	// Moon holds lunar phase and distance data for lunar events
+	Moon *MoonDetails `json:"moon,omitempty"`
+	// Shower holds the catalog data for meteor shower events
+	Shower *MeteorShowerDetails `json:"shower,omitempty"`
+	// Eclipse holds the global and local circumstances of eclipses
+	Eclipse *EclipseDetails `json:"eclipse,omitempty"`
+	// Conjunction holds the minimum separation of conjunction events
+	Conjunction *ConjunctionDetails `json:"conjunction,omitempty"`
+	// Planet holds the appearance of a planet at oppositions, elongations and stations
+	Planet *PlanetDetails `json:"planet,omitempty"`
+	// Twilight holds the twilight stage and moonlight of twilight and darkness events
+	Twilight *TwilightDetails `json:"twilight,omitempty"`
+	// Season holds the instant of equinoxes, solstices and the Earth's apsides
+	Season *SeasonDetails `json:"season,omitempty"`
+	// Satellite holds the rise, culmination and set of satellite passes
+	Satellite *SatellitePassDetails `json:"satellite,omitempty"`
+	// SmallBody holds the position and brightness of comets and asteroids
+	SmallBody *SmallBodyDetails `json:"small_body,omitempty"`
+	// Occultation holds the contacts of lunar occultations
+	Occultation *OccultationDetails `json:"occultation,omitempty"`
+	// GalileanMoon holds the contacts of phenomena of Jupiter's moons
+	GalileanMoon *GalileanMoonDetails `json:"galilean_moon,omitempty"`
+	// DeepSky holds the catalog data and viewing season of deep-sky objects
+	DeepSky *DeepSkyDetails `json:"deep_sky,omitempty"`
+	// VariableStar holds the light elements and circumstances of eclipsing
+	// binary minima
+	VariableStar *VariableStarDetails `json:"variable_star,omitempty"`
+	// SpaceWeather holds the DONKI data of solar and geomagnetic events
+	SpaceWeather *SpaceWeatherDetails `json:"space_weather,omitempty"`
+	// Aurora holds the geomagnetic activity behind aurora events
+	Aurora *AuroraDetails `json:"aurora,omitempty"`
+	// CloseApproach holds the miss distance, speed and size of near-Earth
+	// object close approaches
+	CloseApproach *CloseApproachDetails `json:"close_approach,omitempty"`
+	// Fireball holds the place, height, speed and energy of fireballs
+	Fireball *FireballDetails `json:"fireball,omitempty"`
+	// Launch holds the pad, window and mission of rocket launches
+	Launch *LaunchDetails `json:"launch,omitempty"`
+	// Calendar holds the feed data of events imported from iCalendar feeds
+	Calendar *CalendarDetails `json:"calendar,omitempty"`
 }
 
 // TimeRange represents a time period for filtering events
@@ -39,8 +134,29 @@ func (e *Event) IsValid() bool {
 	return e.Title != "" && e.Description != "" && !e.StartTime.IsZero()
 }
 
+// Position returns the latitude and longitude of events that happen at a
+// place on Earth, such as fireballs and launches
+func (e *Event) Position() (lat, lon float64, ok bool) {
+	if e.Fireball != nil {
+		return e.Fireball.Latitude, e.Fireball.Longitude, true
+	}
+	if e.Launch != nil {
+		return e.Launch.Latitude, e.Launch.Longitude, true
+	}
+	return 0, 0, false
+}
+
 // IsVisible checks if the event is visible at a given time
 func (e *Event) IsVisible(t time.Time) bool {
 	return (t.Equal(e.StartTime) || t.After(e.StartTime)) &&
 		(e.EndTime.IsZero() || t.Equal(e.EndTime) || t.Before(e.EndTime))
 }
+
+// TitleCase turns an upper-case name such as "CIVIL" or "SHADOW_TRANSIT"
+// into a word of a title, such as "Civil" or "Shadow transit"
+func TitleCase(name string) string {
+	if name == "" {
+		return ""
+	}
+	return name[:1] + strings.ToLower(strings.ReplaceAll(name[1:], "_", " "))
+}
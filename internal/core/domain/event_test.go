@@ -108,3 +108,52 @@ func TestEvent_IsVisible(t *testing.T) {
 		})
 	}
 }
+
+func TestEvent_Position(t *testing.T) {
+	tests := []struct {
+		name             string
+		event            Event
+		wantLat, wantLon float64
+		wantOK           bool
+	}{
+		{
+			name:    "fireball",
+			event:   Event{Fireball: &FireballDetails{Latitude: -24.3, Longitude: 147.1}},
+			wantLat: -24.3, wantLon: 147.1, wantOK: true,
+		},
+		{
+			name:    "launch",
+			event:   Event{Launch: &LaunchDetails{Latitude: 28.562, Longitude: -80.577}},
+			wantLat: 28.562, wantLon: -80.577, wantOK: true,
+		},
+		{
+			name:  "sky event",
+			event: Event{Eclipse: &EclipseDetails{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lat, lon, ok := tt.event.Position()
+			if lat != tt.wantLat || lon != tt.wantLon || ok != tt.wantOK {
+				t.Errorf("Event.Position() = %v, %v, %v, want %v, %v, %v", lat, lon, ok, tt.wantLat, tt.wantLon, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestEventType_IsValid(t *testing.T) {
+	for eventType, want := range map[EventType]bool{Launch: true, Other: true, "ECLIPSES": false, "eclipse": false, "": false} {
+		if got := eventType.IsValid(); got != want {
+			t.Errorf("%q.IsValid() = %v, want %v", eventType, got, want)
+		}
+	}
+}
+
+func TestTitleCase(t *testing.T) {
+	for name, want := range map[string]string{"CIVIL": "Civil", "SHADOW_TRANSIT": "Shadow transit", "": ""} {
+		if got := TitleCase(name); got != want {
+			t.Errorf("TitleCase(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package domain
+
+// FireballDetails describes a fireball or bolide detected by US Government
+// sensors, as reported by the CNEOS fireball dataset
+type FireballDetails struct {
+	// Latitude and Longitude are the place of peak brightness in degrees,
+	// north and east positive
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	// AltitudeKm and VelocityKmS are the height and speed at peak
+	// brightness, when known
+	AltitudeKm  *float64 `json:"altitude_km,omitempty"`
+	VelocityKmS *float64 `json:"velocity_km_s,omitempty"`
+	// RadiatedEnergyJ is the total optical energy radiated, in joules
+	RadiatedEnergyJ float64 `json:"radiated_energy_j"`
+	// ImpactEnergyKt is the total impact energy estimated from the radiated
+	// energy, in kilotons of TNT
+	ImpactEnergyKt float64 `json:"impact_energy_kt"`
+	// DistanceKm is the great-circle distance from the observer to the
+	// point below the fireball
+	DistanceKm float64 `json:"distance_km"`
+}
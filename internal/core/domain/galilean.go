@@ -0,0 +1,33 @@
+package domain
+
+// JovianPhenomenon is the kind of a phenomenon of a Galilean moon
+type JovianPhenomenon string
+
+const (
+	MoonTransit     JovianPhenomenon = "TRANSIT"
+	ShadowTransit   JovianPhenomenon = "SHADOW_TRANSIT"
+	MoonEclipse     JovianPhenomenon = "ECLIPSE"
+	MoonOccultation JovianPhenomenon = "OCCULTATION"
+)
+
+// JovianContact is the beginning or end of a phenomenon of a Galilean moon
+type JovianContact struct {
+	// Hidden is true when the moon itself cannot be seen at the contact,
+	// being behind Jupiter during an eclipse or in its shadow during an
+	// occultation
+	Hidden bool `json:"hidden"`
+	// JupiterAltitudeDeg is the altitude of Jupiter at the contact
+	JupiterAltitudeDeg float64 `json:"jupiter_altitude_deg"`
+	// SunAltitudeDeg is the altitude of the Sun at the contact
+	SunAltitudeDeg float64 `json:"sun_altitude_deg"`
+}
+
+// GalileanMoonDetails describes a transit, shadow transit, eclipse or
+// occultation of Io, Europa, Ganymede or Callisto. The event's start and end
+// times are those of the contacts.
+type GalileanMoonDetails struct {
+	Moon       string           `json:"moon"`
+	Phenomenon JovianPhenomenon `json:"phenomenon"`
+	Start      JovianContact    `json:"start"`
+	End        JovianContact    `json:"end"`
+}
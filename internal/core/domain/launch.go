@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// LaunchDetails describes a rocket launch, as listed by a Launch Library 2
+// feed
+type LaunchDetails struct {
+	// LaunchID is the Launch Library ID of the launch, a UUID
+	LaunchID string `json:"launch_id"`
+	// Status is the readiness of the launch, such as "Go for Launch" or
+	// "To Be Determined"
+	Status   string `json:"status"`
+	Provider string `json:"provider"`
+	Rocket   string `json:"rocket"`
+	Mission  string `json:"mission,omitempty"`
+	// MissionType is the purpose of the mission, such as "Communications"
+	MissionType        string `json:"mission_type,omitempty"`
+	MissionDescription string `json:"mission_description,omitempty"`
+	Orbit              string `json:"orbit,omitempty"`
+	// NET is the time the launch is currently planned for, no earlier than
+	// which it will take place
+	NET         time.Time `json:"net"`
+	WindowStart time.Time `json:"window_start"`
+	WindowEnd   time.Time `json:"window_end"`
+	Pad         string    `json:"pad"`
+	PadLocation string    `json:"pad_location"`
+	// Latitude and Longitude are the place of the pad in degrees, north and
+	// east positive
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	// DistanceKm is the great-circle distance from the observer to the pad
+	DistanceKm float64 `json:"distance_km"`
+}
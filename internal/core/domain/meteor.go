@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// MeteorShowerDetails describes an annual meteor shower
+type MeteorShowerDetails struct {
+	// Code is the three-letter IAU shower code
+	Code string    `json:"code"`
+	Peak time.Time `json:"peak"`
+	// ZHR is the zenithal hourly rate at the peak
+	ZHR int `json:"zhr"`
+	// RadiantRA and RadiantDec give the J2000 radiant position in degrees
+	RadiantRA   float64 `json:"radiant_ra"`
+	RadiantDec  float64 `json:"radiant_dec"`
+	VelocityKmS float64 `json:"velocity_km_s"`
+	ParentBody  string  `json:"parent_body,omitempty"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+// MoonPhase represents one of the principal phases of the Moon
+type MoonPhase string
+
+const (
+	NewMoon      MoonPhase = "NEW_MOON"
+	FirstQuarter MoonPhase = "FIRST_QUARTER"
+	FullMoon     MoonPhase = "FULL_MOON"
+	LastQuarter  MoonPhase = "LAST_QUARTER"
+)
+
+// MoonDetails describes the state of the Moon at the instant of an event
+type MoonDetails struct {
+	Phase MoonPhase `json:"phase,omitempty"`
+	// Illumination is the illuminated fraction of the disk, from 0 to 1
+	Illumination float64 `json:"illumination"`
+	// DistanceKm is the geocentric Earth-Moon distance in kilometres
+	DistanceKm float64 `json:"distance_km"`
+	// Supermoon is set for a new or full moon close to perigee
+	Supermoon bool `json:"supermoon,omitempty"`
+}
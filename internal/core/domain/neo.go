@@ -0,0 +1,25 @@
+package domain
+
+// CloseApproachDetails describes the pass of a near-Earth object by the
+// Earth, as reported by the NASA NeoWs feed
+type CloseApproachDetails struct {
+	// ReferenceID is the JPL small-body database ID, such as "3542519"
+	ReferenceID       string  `json:"reference_id"`
+	Name              string  `json:"name"`
+	AbsoluteMagnitude float64 `json:"absolute_magnitude"`
+	// DiameterMinM and DiameterMaxM bound the diameter estimated from the
+	// absolute magnitude for the range of likely albedos, in meters
+	DiameterMinM float64 `json:"diameter_min_m"`
+	DiameterMaxM float64 `json:"diameter_max_m"`
+	// PotentiallyHazardous flags objects that come within 0.05 AU of the
+	// Earth's orbit and are brighter than absolute magnitude 22
+	PotentiallyHazardous bool `json:"potentially_hazardous"`
+	// SentryObject flags objects with a non-zero impact probability on the
+	// JPL Sentry list
+	SentryObject        bool    `json:"sentry_object"`
+	MissDistanceKm      float64 `json:"miss_distance_km"`
+	MissDistanceLunar   float64 `json:"miss_distance_lunar"`
+	MissDistanceAU      float64 `json:"miss_distance_au"`
+	RelativeVelocityKmS float64 `json:"relative_velocity_km_s"`
+	Link                string  `json:"link,omitempty"`
+}
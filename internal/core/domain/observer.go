@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Observer represents the place on Earth events are computed for
+type Observer struct {
+	Name string `json:"name,omitempty"`
+	// Latitude and Longitude are in degrees, north and east positive
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	// Elevation is the height above sea level in meters
+	Elevation float64 `json:"elevation"`
+	// TimeZone is an IANA time zone name such as "America/New_York"
+	TimeZone string `json:"time_zone,omitempty"`
+	// loc is TimeZone as loaded by WithLocation
+	loc *time.Location
+}
+
+// IsValid checks if the coordinates and time zone of the observer are usable
+func (o Observer) IsValid() bool {
+	if o.Latitude < -90 || o.Latitude > 90 || o.Longitude < -180 || o.Longitude > 180 {
+		return false
+	}
+	_, err := time.LoadLocation(o.TimeZone)
+	return err == nil
+}
+
+// Location returns the time zone of the observer, defaulting to UTC. It is
+// only looked up when the observer was not built with WithLocation.
+func (o Observer) Location() *time.Location {
+	if o.loc != nil && o.loc.String() == o.TimeZone {
+		return o.loc
+	}
+	if o.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(o.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// WithLocation returns the observer with its time zone loaded, so that
+// formatting the local times of many events does not look it up each time
+func (o Observer) WithLocation() Observer {
+	o.loc = nil
+	o.loc = o.Location()
+	return o
+}
+
+// Clock formats an instant as the time of day in the observer's time zone,
+// such as "21:04 EDT"
+func (o Observer) Clock(t time.Time) string {
+	return t.In(o.Location()).Format("15:04 MST")
+}
+
+// ClockSeconds formats an instant as Clock does, to the second, for timings
+// such as occultation contacts that are given that finely
+func (o Observer) ClockSeconds(t time.Time) string {
+	return t.In(o.Location()).Format("15:04:05 MST")
+}
+
+// NightDate returns the date of the night an instant belongs to, at midnight
+// UTC. Nights run from local mean noon to local mean noon at the observer's
+// longitude and are dated by their evening, so the small hours of a morning
+// belong to the night of the day before. Mean solar time rather than the time
+// zone is used, so that nights split in daylight wherever the observer is.
+func (o Observer) NightDate(t time.Time) time.Time {
+	local := t.UTC().Add(time.Duration((o.Longitude/15 - 12) * float64(time.Hour)))
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0
+
+// DistanceKm returns the great-circle distance in kilometers from the
+// observer to a place given in degrees, north and east positive
+func (o Observer) DistanceKm(lat, lon float64) float64 {
+	phi1, phi2 := o.Latitude*math.Pi/180, lat*math.Pi/180
+	dPhi := phi2 - phi1
+	dLambda := (lon - o.Longitude) * math.Pi / 180
+
+	// Haversine form stays accurate for nearby places
+	h := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// ParseObserver parses an observer written as "lat,lon[,elevation[,timezone]]"
+func ParseObserver(s string) (Observer, error) {
+	fields := strings.Split(s, ",")
+	if len(fields) < 2 || len(fields) > 4 {
+		return Observer{}, fmt.Errorf("observer %q: want lat,lon[,elevation[,timezone]]", s)
+	}
+
+	var values [3]float64
+	for i := 0; i < len(fields) && i < len(values); i++ {
+		v, err := strconv.ParseFloat(strings.TrimSpace(fields[i]), 64)
+		if err != nil {
+			return Observer{}, fmt.Errorf("observer %q: %w", s, err)
+		}
+		values[i] = v
+	}
+
+	observer := Observer{Latitude: values[0], Longitude: values[1], Elevation: values[2]}
+	if len(fields) == 4 {
+		observer.TimeZone = strings.TrimSpace(fields[3])
+	}
+	if !observer.IsValid() {
+		return Observer{}, fmt.Errorf("observer %q: coordinates or time zone out of range", s)
+	}
+	return observer, nil
+}
+
+// ParseLocations parses saved locations written as
+// "name=lat,lon[,elevation[,timezone]];name=..."
+func ParseLocations(s string) (map[string]Observer, error) {
+	locations := make(map[string]Observer)
+	for _, entry := range strings.Split(s, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		name, spec, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("location %q: want name=lat,lon[,elevation[,timezone]]", entry)
+		}
+		observer, err := ParseObserver(spec)
+		if err != nil {
+			return nil, fmt.Errorf("location %s: %w", name, err)
+		}
+		observer.Name = name
+		locations[name] = observer
+	}
+	return locations, nil
+}
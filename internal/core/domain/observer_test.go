@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseObserver(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Observer
+		wantErr bool
+	}{
+		{
+			name:  "latitude and longitude",
+			input: "40.7128,-74.0060",
+			want:  Observer{Latitude: 40.7128, Longitude: -74.0060},
+		},
+		{
+			name:  "with elevation and time zone",
+			input: "19.8207, -155.4681, 4205, Pacific/Honolulu",
+			want:  Observer{Latitude: 19.8207, Longitude: -155.4681, Elevation: 4205, TimeZone: "Pacific/Honolulu"},
+		},
+		{name: "missing longitude", input: "40.7128", wantErr: true},
+		{name: "latitude out of range", input: "91,0", wantErr: true},
+		{name: "not a number", input: "north,0", wantErr: true},
+		{name: "unknown time zone", input: "0,0,0,Mars/Olympus_Mons", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseObserver(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseObserver() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseObserver() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLocations(t *testing.T) {
+	locations, err := ParseLocations("home=40.7128,-74.0060,10,America/New_York; cabin=44.27,-71.30")
+	if err != nil {
+		t.Fatalf("ParseLocations() error = %v", err)
+	}
+	if len(locations) != 2 {
+		t.Fatalf("ParseLocations() got %v locations, want 2", len(locations))
+	}
+	if home := locations["home"]; home.Name != "home" || home.TimeZone != "America/New_York" {
+		t.Errorf("ParseLocations() home = %v", home)
+	}
+
+	if _, err := ParseLocations("home"); err == nil {
+		t.Error("ParseLocations() error = nil, want error for a location without coordinates")
+	}
+}
+
+func TestObserver_Location(t *testing.T) {
+	if got := (Observer{}).Location(); got != time.UTC {
+		t.Errorf("Location() = %v, want UTC", got)
+	}
+	if got := (Observer{TimeZone: "Europe/Paris"}).Location().String(); got != "Europe/Paris" {
+		t.Errorf("Location() = %v, want Europe/Paris", got)
+	}
+
+	paris := Observer{TimeZone: "Europe/Paris"}.WithLocation()
+	if paris.Location() != paris.Location() {
+		t.Error("Location() loaded the time zone again after WithLocation()")
+	}
+	paris.TimeZone = "Asia/Tokyo"
+	if got := paris.Location().String(); got != "Asia/Tokyo" {
+		t.Errorf("Location() = %v after changing the time zone, want Asia/Tokyo", got)
+	}
+}
+
+func TestObserver_DistanceKm(t *testing.T) {
+	newYork := Observer{Latitude: 40.7128, Longitude: -74.0060}
+	tests := []struct {
+		name     string
+		lat, lon float64
+		want     float64
+	}{
+		{name: "same place", lat: 40.7128, lon: -74.0060, want: 0},
+		{name: "London", lat: 51.5074, lon: -0.1278, want: 5570},
+		{name: "across the antimeridian", lat: -40.7128, lon: 105.9940, want: 20015},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newYork.DistanceKm(tt.lat, tt.lon); math.Abs(got-tt.want) > 5 {
+				t.Errorf("DistanceKm() = %.1f, want %.0f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObserver_NightDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		observer Observer
+		at       time.Time
+		want     string
+	}{
+		{name: "evening in New York", observer: Observer{Longitude: -74.0060}, at: time.Date(2024, 5, 11, 1, 0, 0, 0, time.UTC), want: "2024-05-10"},
+		{name: "small hours in New York", observer: Observer{Longitude: -74.0060}, at: time.Date(2024, 5, 11, 9, 0, 0, 0, time.UTC), want: "2024-05-10"},
+		{name: "afternoon in New York", observer: Observer{Longitude: -74.0060}, at: time.Date(2024, 5, 11, 18, 0, 0, 0, time.UTC), want: "2024-05-11"},
+		{name: "small hours in Sydney", observer: Observer{Longitude: 151.2093}, at: time.Date(2024, 5, 10, 16, 0, 0, 0, time.UTC), want: "2024-05-10"},
+		{name: "evening in Sydney", observer: Observer{Longitude: 151.2093}, at: time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC), want: "2024-05-10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.observer.NightDate(tt.at).Format("2006-01-02"); got != tt.want {
+				t.Errorf("NightDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObserver_Clock(t *testing.T) {
+	at := time.Date(2024, 5, 11, 1, 4, 30, 0, time.UTC)
+	newYork := Observer{TimeZone: "America/New_York"}
+	if got := newYork.Clock(at); got != "21:04 EDT" {
+		t.Errorf("Clock() = %v, want 21:04 EDT", got)
+	}
+	if got := newYork.ClockSeconds(at); got != "21:04:30 EDT" {
+		t.Errorf("ClockSeconds() = %v, want 21:04:30 EDT", got)
+	}
+}
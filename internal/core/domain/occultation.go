@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Limb is the side of the Moon at which an occulted body disappears or
+// reappears
+type Limb string
+
+const (
+	BrightLimb Limb = "BRIGHT"
+	DarkLimb   Limb = "DARK"
+)
+
+// OccultationContact is the disappearance or reappearance of an occulted body
+type OccultationContact struct {
+	Time time.Time `json:"time"`
+	Limb Limb      `json:"limb"`
+	// PositionAngleDeg locates the contact on the Moon's limb, measured from
+	// north through east
+	PositionAngleDeg float64 `json:"position_angle_deg"`
+	// MoonAltitudeDeg is the altitude of the Moon at the contact
+	MoonAltitudeDeg float64 `json:"moon_altitude_deg"`
+}
+
+// OccultationDetails describes the Moon passing in front of a star or planet
+type OccultationDetails struct {
+	Body string `json:"body"`
+	// Designation is the Bayer or Flamsteed designation of an occulted star
+	Designation   string             `json:"designation,omitempty"`
+	Planet        bool               `json:"planet"`
+	Magnitude     float64            `json:"magnitude"`
+	Disappearance OccultationContact `json:"disappearance"`
+	Reappearance  OccultationContact `json:"reappearance"`
+	// MoonIllumination is the illuminated fraction of the Moon's disk
+	MoonIllumination float64 `json:"moon_illumination"`
+	// SunAltitudeDeg is the altitude of the Sun at disappearance
+	SunAltitudeDeg float64 `json:"sun_altitude_deg"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+// PlanetDirection qualifies greatest elongations and stationary points
+type PlanetDirection string
+
+const (
+	East       PlanetDirection = "EAST"
+	West       PlanetDirection = "WEST"
+	Retrograde PlanetDirection = "RETROGRADE"
+	Direct     PlanetDirection = "DIRECT"
+)
+
+// PlanetDetails describes how a planet appears at the instant of an event
+type PlanetDetails struct {
+	Name string `json:"name"`
+	// Direction is EAST or WEST of the Sun for greatest elongations, and
+	// the motion that begins for stations
+	Direction PlanetDirection `json:"direction,omitempty"`
+	Magnitude float64         `json:"magnitude"`
+	// AngularDiameter is the apparent equatorial diameter in arcseconds
+	AngularDiameter float64 `json:"angular_diameter_arcsec"`
+	// ElongationDeg is the angular distance from the Sun in degrees
+	ElongationDeg float64 `json:"elongation_deg"`
+	DistanceAU    float64 `json:"distance_au"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+// Relation says how an event is related to another
+type Relation string
+
+const (
+	// CausedBy points to an earlier event that gave rise to this one, such as
+	// from a geomagnetic storm to the coronal mass ejection that drove it
+	CausedBy Relation = "CAUSED_BY"
+	// FollowedBy points to a later event that this one gave rise to, such as
+	// from a solar flare to the coronal mass ejection launched with it
+	FollowedBy Relation = "FOLLOWED_BY"
+)
+
+// EventLink points from an event to a related event by its ID
+type EventLink struct {
+	ID       string   `json:"id"`
+	Relation Relation `json:"relation"`
+	// Source names the repository that reports the related event, so that
+	// only it is asked for the event
+	Source string `json:"source,omitempty"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// SatellitePassDetails describes a pass of an artificial satellite over the
+// observer. Azimuths are in degrees from north through east.
+type SatellitePassDetails struct {
+	Name          string    `json:"name"`
+	CatalogNumber int       `json:"catalog_number"`
+	Rise          time.Time `json:"rise"`
+	Culmination   time.Time `json:"culmination"`
+	Set           time.Time `json:"set"`
+	RiseAzimuth   float64   `json:"rise_azimuth_deg"`
+	SetAzimuth    float64   `json:"set_azimuth_deg"`
+	// MaxElevation is the altitude above the horizon at culmination in degrees
+	MaxElevation       float64 `json:"max_elevation_deg"`
+	CulminationAzimuth float64 `json:"culmination_azimuth_deg"`
+	// Visible is true when the satellite is sunlit while the observer is in darkness
+	Visible bool `json:"visible"`
+	// ElementsEpoch is the epoch of the two-line element set the pass was computed from
+	ElementsEpoch time.Time `json:"elements_epoch"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// SeasonMarker names an equinox, solstice or apsis of the Earth's orbit
+type SeasonMarker string
+
+const (
+	MarchEquinox     SeasonMarker = "MARCH_EQUINOX"
+	JuneSolstice     SeasonMarker = "JUNE_SOLSTICE"
+	SeptemberEquinox SeasonMarker = "SEPTEMBER_EQUINOX"
+	DecemberSolstice SeasonMarker = "DECEMBER_SOLSTICE"
+	EarthPerihelion  SeasonMarker = "PERIHELION"
+	EarthAphelion    SeasonMarker = "APHELION"
+)
+
+// SeasonDetails gives the instant of a season marker in UTC and in the
+// observer's time zone
+type SeasonDetails struct {
+	Marker   SeasonMarker `json:"marker"`
+	UTC      time.Time    `json:"utc"`
+	Local    time.Time    `json:"local"`
+	TimeZone string       `json:"time_zone"`
+	// DistanceAU is the Sun-Earth distance, set at perihelion and aphelion
+	DistanceAU float64 `json:"distance_au,omitempty"`
+}
@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+// SmallBodyKind distinguishes comets from asteroids
+type SmallBodyKind string
+
+const (
+	Comet    SmallBodyKind = "COMET"
+	Asteroid SmallBodyKind = "ASTEROID"
+)
+
+// SmallBody is a comet or asteroid described by heliocentric osculating
+// elements referred to the J2000 ecliptic and equinox, in degrees and AU
+type SmallBody struct {
+	Kind SmallBodyKind `json:"kind"`
+	// Designation is the number or provisional designation, e.g. "1P",
+	// "C/2023 A3" or "433"
+	Designation          string    `json:"designation"`
+	Name                 string    `json:"name,omitempty"`
+	PerihelionDistanceAU float64   `json:"perihelion_distance_au"`
+	Eccentricity         float64   `json:"eccentricity"`
+	InclinationDeg       float64   `json:"inclination_deg"`
+	NodeDeg              float64   `json:"node_deg"`
+	ArgPerihelionDeg     float64   `json:"arg_perihelion_deg"`
+	PerihelionTime       time.Time `json:"perihelion_time"`
+	// AbsoluteMagnitude and Slope are H and G for asteroids, and the total
+	// magnitude parameters H and K of m = H + 5 log Δ + 2.5 K log r for comets
+	AbsoluteMagnitude float64 `json:"absolute_magnitude"`
+	Slope             float64 `json:"slope"`
+}
+
+// FullName joins the designation and name the way the MPC writes them
+func (b SmallBody) FullName() string {
+	switch {
+	case b.Name == "":
+		return b.Designation
+	case b.Kind == Comet && len(b.Designation) > 0 && b.Designation[len(b.Designation)-1] == 'P':
+		return b.Designation + "/" + b.Name
+	case b.Kind == Comet:
+		return b.Designation + " (" + b.Name + ")"
+	default:
+		return "(" + b.Designation + ") " + b.Name
+	}
+}
+
+// SmallBodyDetails gives the position and brightness of a small body at the
+// instant of an event. Coordinates are astrometric J2000.
+type SmallBodyDetails struct {
+	Body            SmallBody `json:"body"`
+	Magnitude       float64   `json:"magnitude"`
+	RA              float64   `json:"ra_deg"`
+	Dec             float64   `json:"dec_deg"`
+	SunDistanceAU   float64   `json:"sun_distance_au"`
+	EarthDistanceAU float64   `json:"earth_distance_au"`
+	ElongationDeg   float64   `json:"elongation_deg"`
+	// PeakMagnitude and PeakTime give the brightest point of the period
+	// during which a body stays above the magnitude limit
+	PeakMagnitude float64    `json:"peak_magnitude,omitempty"`
+	PeakTime      *time.Time `json:"peak_time,omitempty"`
+}
@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// SpaceWeatherDetails describes an event of the NASA DONKI space weather
+// database. Fields that do not apply to the kind of event are left empty.
+type SpaceWeatherDetails struct {
+	// ActivityID is the DONKI identifier, such as
+	// "2024-05-10T17:36:00-GST-001"
+	ActivityID  string   `json:"activity_id"`
+	Instruments []string `json:"instruments,omitempty"`
+	// LinkedEvents holds the activity IDs of the events DONKI associates
+	// with this one
+	LinkedEvents []string `json:"linked_events,omitempty"`
+	Link         string   `json:"link,omitempty"`
+
+	// SourceLocation is the heliographic position of the source on the Sun,
+	// such as "S17E65", and ActiveRegion its NOAA active region number
+	SourceLocation string `json:"source_location,omitempty"`
+	ActiveRegion   int    `json:"active_region,omitempty"`
+
+	// ClassType is the GOES X-ray class of a flare, such as "X5.8", and
+	// PeakFlux the peak 1-8 Å X-ray flux it stands for in W/m²
+	ClassType string     `json:"class_type,omitempty"`
+	PeakFlux  float64    `json:"peak_flux_w_m2,omitempty"`
+	PeakTime  *time.Time `json:"peak_time,omitempty"`
+
+	// SpeedKmS and HalfAngleDeg are the speed and angular half width of the
+	// most accurate analysis of a coronal mass ejection
+	SpeedKmS     float64 `json:"speed_km_s,omitempty"`
+	HalfAngleDeg float64 `json:"half_angle_deg,omitempty"`
+	// PredictedArrival is when the WSA-ENLIL model expects a coronal mass
+	// ejection to reach Earth, and PredictedKpIndex the highest planetary K
+	// index it estimates for the impact
+	PredictedArrival *time.Time `json:"predicted_arrival,omitempty"`
+	PredictedKpIndex float64    `json:"predicted_kp_index,omitempty"`
+
+	// MaxKpIndex is the highest planetary K index observed during a
+	// geomagnetic storm, StormLevel its NOAA G scale level and KpIndices the
+	// three-hourly observations
+	MaxKpIndex float64   `json:"max_kp_index,omitempty"`
+	StormLevel string    `json:"storm_level,omitempty"`
+	KpIndices  []KpIndex `json:"kp_indices,omitempty"`
+
+	// Observatory is where an interplanetary shock was detected, such as
+	// "Earth" or "STEREO A"
+	Observatory string `json:"observatory,omitempty"`
+}
+
+// KpIndex is one three-hourly observation of the planetary K index
+type KpIndex struct {
+	Time   time.Time `json:"time"`
+	Kp     float64   `json:"kp"`
+	Source string    `json:"source,omitempty"`
+}
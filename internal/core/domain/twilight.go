@@ -0,0 +1,35 @@
+package domain
+
+// TwilightKind is the depth of the Sun below the horizon during twilight
+type TwilightKind string
+
+const (
+	// CivilTwilight lasts while the Sun is up to 6° below the horizon
+	CivilTwilight TwilightKind = "CIVIL"
+	// NauticalTwilight lasts while the Sun is 6° to 12° below the horizon
+	NauticalTwilight TwilightKind = "NAUTICAL"
+	// AstronomicalTwilight lasts while the Sun is 12° to 18° below the horizon
+	AstronomicalTwilight TwilightKind = "ASTRONOMICAL"
+)
+
+// TwilightPhase tells whether twilight falls in the evening or the morning.
+// At high latitudes in summer the Sun may not sink below a twilight band,
+// which then lasts all night.
+type TwilightPhase string
+
+const (
+	Evening  TwilightPhase = "EVENING"
+	Morning  TwilightPhase = "MORNING"
+	AllNight TwilightPhase = "ALL_NIGHT"
+)
+
+// TwilightDetails describes a twilight, darkness or dark window event
+type TwilightDetails struct {
+	// Kind and Phase are set on twilight events only
+	Kind            TwilightKind  `json:"kind,omitempty"`
+	Phase           TwilightPhase `json:"phase,omitempty"`
+	DurationMinutes float64       `json:"duration_minutes"`
+	// MoonUpMinutes is how long the Moon is above the horizon during the event
+	MoonUpMinutes    float64 `json:"moon_up_minutes"`
+	MoonIllumination float64 `json:"moon_illumination"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// VariableStarDetails describes a primary minimum of an eclipsing binary.
+// The event's start and end times are the beginning and end of the eclipse.
+type VariableStarDetails struct {
+	Star string `json:"star"`
+	// Designation is the name of the star in the General Catalogue of
+	// Variable Stars, such as "bet Per"
+	Designation string `json:"designation"`
+	// VariableType is the GCVS type, such as "EA" for Algol-type binaries
+	VariableType string `json:"variable_type"`
+	// MaxMagnitude is the brightness outside eclipse and MinMagnitude at
+	// minimum
+	MaxMagnitude  float64   `json:"max_magnitude"`
+	MinMagnitude  float64   `json:"min_magnitude"`
+	PeriodDays    float64   `json:"period_days"`
+	Minimum       time.Time `json:"minimum"`
+	DurationHours float64   `json:"duration_hours"`
+	// StarAltitudeDeg and SunAltitudeDeg are the altitudes at minimum
+	StarAltitudeDeg float64 `json:"star_altitude_deg"`
+	SunAltitudeDeg  float64 `json:"sun_altitude_deg"`
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"astralis/internal/core/domain"
+)
+
+// EphemerisRepository defines the interface for tabulating the positions of
+// arbitrary bodies
+type EphemerisRepository interface {
+	// GetEphemeris tabulates the position of a body, as seen by the
+	// observer, at steps through a time range. It returns nil when the body
+	// is unknown.
+	GetEphemeris(ctx context.Context, body string, timeRange domain.TimeRange, step time.Duration, observer domain.Observer) (*domain.Ephemeris, error)
+}
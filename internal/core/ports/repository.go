@@ -8,14 +8,15 @@ import (
 
 // EventRepository defines the interface for accessing astronomical events
 type EventRepository interface {
-	// GetEvents retrieves events within a specific time range
-	GetEvents(ctx context.Context, timeRange domain.TimeRange) ([]domain.Event, error)
+	// GetEvents retrieves events within a specific time range, as seen by
+	// the observer
+	GetEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error)
 	
 	// GetEventByID retrieves a specific event by its ID
-	GetEventByID(ctx context.Context, id string) (*domain.Event, error)
+	GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error)
 	
 	// GetEventsByType retrieves events of a specific type within a time range
-	GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange) ([]domain.Event, error)
+	GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error)
 	
 	// Name returns the name of the repository implementation
 	Name() string
@@ -10,17 +10,21 @@ import (
 // EventService defines the interface for the business logic layer
 type EventService interface {
 	// GetUpcomingEvents retrieves upcoming events within a specific time range
-	GetUpcomingEvents(ctx context.Context, timeRange domain.TimeRange) ([]domain.Event, error)
+	// for an observer
+	GetUpcomingEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error)
 
 	// GetEventByID retrieves a specific event by its ID
-	GetEventByID(ctx context.Context, id string) (*domain.Event, error)
+	GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error)
 
 	// GetEventsByDate retrieves events for a specific date
-	GetEventsByDate(ctx context.Context, date time.Time) ([]domain.Event, error)
+	GetEventsByDate(ctx context.Context, date time.Time, observer domain.Observer) ([]domain.Event, error)
 
 	// GetEventsByDateRange retrieves events within a date range
-	GetEventsByDateRange(ctx context.Context, start, end time.Time) ([]domain.Event, error)
+	GetEventsByDateRange(ctx context.Context, start, end time.Time, observer domain.Observer) ([]domain.Event, error)
 
 	// GetEventsByType retrieves events of a specific type
-	GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange) ([]domain.Event, error)
+	GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error)
+
+	// GetRelatedEvents retrieves the events an event links to
+	GetRelatedEvents(ctx context.Context, event domain.Event, observer domain.Observer) ([]domain.Event, error)
 }
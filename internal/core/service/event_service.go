@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"astralis/internal/core/domain"
@@ -20,11 +21,11 @@ func NewEventService(repositories []ports.EventRepository) ports.EventService {
 }
 
 // GetUpcomingEvents retrieves upcoming events from all repositories
-func (s *eventService) GetUpcomingEvents(ctx context.Context, timeRange domain.TimeRange) ([]domain.Event, error) {
+func (s *eventService) GetUpcomingEvents(ctx context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
 	var allEvents []domain.Event
 	
 	for _, repo := range s.repositories {
-		events, err := repo.GetEvents(ctx, timeRange)
+		events, err := repo.GetEvents(ctx, timeRange, observer)
 		if err != nil {
 			continue // Skip failed repository but continue with others
 		}
@@ -35,9 +36,9 @@ func (s *eventService) GetUpcomingEvents(ctx context.Context, timeRange domain.T
 }
 
 // GetEventByID retrieves a specific event by its ID from all repositories
-func (s *eventService) GetEventByID(ctx context.Context, id string) (*domain.Event, error) {
+func (s *eventService) GetEventByID(ctx context.Context, id string, observer domain.Observer) (*domain.Event, error) {
 	for _, repo := range s.repositories {
-		event, err := repo.GetEventByID(ctx, id)
+		event, err := repo.GetEventByID(ctx, id, observer)
 		if err == nil && event != nil {
 			return event, nil
 		}
@@ -46,28 +47,28 @@ func (s *eventService) GetEventByID(ctx context.Context, id string) (*domain.Eve
 }
 
 // GetEventsByDate retrieves events for a specific date
-func (s *eventService) GetEventsByDate(ctx context.Context, date time.Time) ([]domain.Event, error) {
+func (s *eventService) GetEventsByDate(ctx context.Context, date time.Time, observer domain.Observer) ([]domain.Event, error) {
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
 	
-	return s.GetEventsByDateRange(ctx, startOfDay, endOfDay)
+	return s.GetEventsByDateRange(ctx, startOfDay, endOfDay, observer)
 }
 
 // GetEventsByDateRange retrieves events within a date range
-func (s *eventService) GetEventsByDateRange(ctx context.Context, start, end time.Time) ([]domain.Event, error) {
+func (s *eventService) GetEventsByDateRange(ctx context.Context, start, end time.Time, observer domain.Observer) ([]domain.Event, error) {
 	timeRange := domain.TimeRange{
 		Start: start,
 		End:   end,
 	}
-	return s.GetUpcomingEvents(ctx, timeRange)
+	return s.GetUpcomingEvents(ctx, timeRange, observer)
 }
 
 // GetEventsByType retrieves events of a specific type
-func (s *eventService) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange) ([]domain.Event, error) {
+func (s *eventService) GetEventsByType(ctx context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
 	var typedEvents []domain.Event
 	
 	for _, repo := range s.repositories {
-		events, err := repo.GetEventsByType(ctx, eventType, timeRange)
+		events, err := repo.GetEventsByType(ctx, eventType, timeRange, observer)
 		if err != nil {
 			continue
 		}
@@ -76,3 +77,30 @@ func (s *eventService) GetEventsByType(ctx context.Context, eventType domain.Eve
 
 	return typedEvents, nil
 }
+
+// GetRelatedEvents retrieves the events an event links to, sorted by start
+// time. Each link is looked up in the repository named by its source, or in
+// all of them when it names none. Links that cannot be resolved, or whose
+// lookup fails, are skipped.
+func (s *eventService) GetRelatedEvents(ctx context.Context, event domain.Event, observer domain.Observer) ([]domain.Event, error) {
+	var related []domain.Event
+
+	for _, link := range event.Related {
+		for _, repo := range s.repositories {
+			if link.Source != "" && repo.Name() != link.Source {
+				continue
+			}
+			linked, err := repo.GetEventByID(ctx, link.ID, observer)
+			if err != nil || linked == nil {
+				continue
+			}
+			related = append(related, *linked)
+			break
+		}
+	}
+
+	sort.Slice(related, func(i, j int) bool {
+		return related[i].StartTime.Before(related[j].StartTime)
+	})
+	return related, nil
+}
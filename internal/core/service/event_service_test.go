@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"testing"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 func TestEventService_GetUpcomingEvents(t *testing.T) {
 	mockRepo := newMockRepository()
 	service := NewEventService([]ports.EventRepository{mockRepo})
+	observer := domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
 
 	now := time.Now()
 	timeRange := domain.TimeRange{
@@ -19,7 +22,7 @@ func TestEventService_GetUpcomingEvents(t *testing.T) {
 		End:   now.Add(96 * time.Hour),
 	}
 
-	events, err := service.GetUpcomingEvents(context.Background(), timeRange)
+	events, err := service.GetUpcomingEvents(context.Background(), timeRange, observer)
 	if err != nil {
 		t.Errorf("GetUpcomingEvents() error = %v", err)
 		return
@@ -28,11 +31,16 @@ func TestEventService_GetUpcomingEvents(t *testing.T) {
 	if len(events) != 2 {
 		t.Errorf("GetUpcomingEvents() got %v events, want %v", len(events), 2)
 	}
+
+	if mockRepo.observer != observer {
+		t.Errorf("GetUpcomingEvents() passed observer %v, want %v", mockRepo.observer, observer)
+	}
 }
 
 func TestEventService_GetEventByID(t *testing.T) {
 	mockRepo := newMockRepository()
 	service := NewEventService([]ports.EventRepository{mockRepo})
+	observer := domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
 
 	tests := []struct {
 		name    string
@@ -55,7 +63,7 @@ func TestEventService_GetEventByID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			event, err := service.GetEventByID(context.Background(), tt.id)
+			event, err := service.GetEventByID(context.Background(), tt.id, observer)
 			if err != nil {
 				t.Errorf("GetEventByID() error = %v", err)
 				return
@@ -81,6 +89,7 @@ func TestEventService_GetEventByID(t *testing.T) {
 func TestEventService_GetEventsByType(t *testing.T) {
 	mockRepo := newMockRepository()
 	service := NewEventService([]ports.EventRepository{mockRepo})
+	observer := domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
 
 	now := time.Now()
 	timeRange := domain.TimeRange{
@@ -112,7 +121,7 @@ func TestEventService_GetEventsByType(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			events, err := service.GetEventsByType(context.Background(), tt.eventType, timeRange)
+			events, err := service.GetEventsByType(context.Background(), tt.eventType, timeRange, observer)
 			if err != nil {
 				t.Errorf("GetEventsByType() error = %v", err)
 				return
@@ -134,6 +143,7 @@ func TestEventService_GetEventsByType(t *testing.T) {
 func TestEventService_GetEventsByDate(t *testing.T) {
 	mockRepo := newMockRepository()
 	service := NewEventService([]ports.EventRepository{mockRepo})
+	observer := domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
 
 	now := time.Now()
 	tests := []struct {
@@ -160,7 +170,7 @@ func TestEventService_GetEventsByDate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			events, err := service.GetEventsByDate(context.Background(), tt.date)
+			events, err := service.GetEventsByDate(context.Background(), tt.date, observer)
 			if err != nil {
 				t.Errorf("GetEventsByDate() error = %v", err)
 				return
@@ -172,3 +182,68 @@ func TestEventService_GetEventsByDate(t *testing.T) {
 		})
 	}
 }
+
+func TestEventService_GetRelatedEvents(t *testing.T) {
+	mockRepo := newMockRepository()
+	service := NewEventService([]ports.EventRepository{mockRepo})
+	observer := domain.Observer{Latitude: 40.7128, Longitude: -74.0060, TimeZone: "America/New_York"}
+
+	event := domain.Event{
+		ID: "storm-1",
+		Related: []domain.EventLink{
+			{ID: "eclipse-1", Relation: domain.FollowedBy},
+			{ID: "non-existing", Relation: domain.FollowedBy},
+			{ID: "meteor-1", Relation: domain.CausedBy},
+		},
+	}
+
+	related, err := service.GetRelatedEvents(context.Background(), event, observer)
+	if err != nil {
+		t.Fatalf("GetRelatedEvents() error = %v", err)
+	}
+
+	// Unresolved links are skipped and the rest sorted by start time
+	if len(related) != 2 || related[0].ID != "meteor-1" || related[1].ID != "eclipse-1" {
+		t.Errorf("GetRelatedEvents() got %v, want meteor-1 and eclipse-1", related)
+	}
+
+	if mockRepo.observer != observer {
+		t.Errorf("GetRelatedEvents() passed observer %v, want %v", mockRepo.observer, observer)
+	}
+}
+
+func TestEventService_GetRelatedEvents_Sources(t *testing.T) {
+	donki := newMockRepository()
+	donki.name = "DONKI"
+	donki.events = map[string]domain.Event{"storm-2": {ID: "storm-2", Type: domain.GeomagneticStorm}}
+	failing := newMockRepository()
+	failing.name = "Failing"
+	failing.err = errors.New("unavailable")
+	other := newMockRepository()
+	service := NewEventService([]ports.EventRepository{failing, other, donki})
+
+	event := domain.Event{
+		ID: "storm-1",
+		Related: []domain.EventLink{
+			{ID: "storm-2", Relation: domain.FollowedBy, Source: "DONKI"},
+			{ID: "meteor-1", Relation: domain.CausedBy},
+		},
+	}
+
+	related, err := service.GetRelatedEvents(context.Background(), event, domain.Observer{})
+	if err != nil {
+		t.Fatalf("GetRelatedEvents() error = %v", err)
+	}
+
+	// The failing repository is skipped, and links naming a source are only
+	// looked up there
+	if len(related) != 2 {
+		t.Fatalf("GetRelatedEvents() got %v, want storm-2 and meteor-1", related)
+	}
+	if !slices.Equal(failing.lookups, []string{"meteor-1"}) || !slices.Equal(other.lookups, []string{"meteor-1"}) {
+		t.Errorf("lookups = %v and %v, want only meteor-1 outside its source", failing.lookups, other.lookups)
+	}
+	if !slices.Equal(donki.lookups, []string{"storm-2"}) {
+		t.Errorf("DONKI lookups = %v, want storm-2", donki.lookups)
+	}
+}
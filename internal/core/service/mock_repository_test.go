@@ -8,7 +8,14 @@ import (
 )
 
 type mockRepository struct {
+	name   string
 	events map[string]domain.Event
+	// err fails every query when set
+	err error
+	// observer records the observer of the last query
+	observer domain.Observer
+	// lookups records the IDs asked for
+	lookups []string
 }
 
 func newMockRepository() *mockRepository {
@@ -33,11 +40,13 @@ func newMockRepository() *mockRepository {
 	}
 
 	return &mockRepository{
+		name:   "Mock Repository",
 		events: events,
 	}
 }
 
-func (r *mockRepository) GetEvents(_ context.Context, timeRange domain.TimeRange) ([]domain.Event, error) {
+func (r *mockRepository) GetEvents(_ context.Context, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	r.observer = observer
 	var result []domain.Event
 	for _, event := range r.events {
 		if event.StartTime.After(timeRange.Start) && event.StartTime.Before(timeRange.End) {
@@ -47,14 +56,20 @@ func (r *mockRepository) GetEvents(_ context.Context, timeRange domain.TimeRange
 	return result, nil
 }
 
-func (r *mockRepository) GetEventByID(_ context.Context, id string) (*domain.Event, error) {
+func (r *mockRepository) GetEventByID(_ context.Context, id string, observer domain.Observer) (*domain.Event, error) {
+	r.observer = observer
+	r.lookups = append(r.lookups, id)
+	if r.err != nil {
+		return nil, r.err
+	}
 	if event, ok := r.events[id]; ok {
 		return &event, nil
 	}
 	return nil, nil
 }
 
-func (r *mockRepository) GetEventsByType(_ context.Context, eventType domain.EventType, timeRange domain.TimeRange) ([]domain.Event, error) {
+func (r *mockRepository) GetEventsByType(_ context.Context, eventType domain.EventType, timeRange domain.TimeRange, observer domain.Observer) ([]domain.Event, error) {
+	r.observer = observer
 	var result []domain.Event
 	for _, event := range r.events {
 		if event.Type == eventType && event.StartTime.After(timeRange.Start) && event.StartTime.Before(timeRange.End) {
@@ -65,5 +80,5 @@ func (r *mockRepository) GetEventsByType(_ context.Context, eventType domain.Eve
 }
 
 func (r *mockRepository) Name() string {
-	return "Mock Repository"
+	return r.name
 }
@@ -6,29 +6,111 @@ import "flag"
 type Config interface {
 	// Server
 	APIPort() string
+	// Observer
+	Latitude() float64
+	Longitude() float64
+	Elevation() float64
+	TimeZone() string
+	Locations() string
+	// Local computations
+	ConjunctionSeparation() float64
+	TLEPath() string
+	SatelliteMinElevation() float64
+	SmallBodyPath() string
+	SmallBodyMagnitude() float64
+	OccultationMagnitude() float64
+	DeepSkyMinAltitude() float64
 	// Third-party APIs
 	NasaAPIKey() string
+	HorizonsURL() string
+	HorizonsBodies() string
+	Fireballs() bool
+	LaunchURL() string
+	ICalFeeds() string
+	ICalCategories() string
 }
 
 type config struct {
 	// Server
 	apiPort string
 
+	// Observer
+	latitude  float64
+	longitude float64
+	elevation float64
+	timeZone  string
+	locations string
+
+	// Local computations
+	conjunctionSeparation float64
+	tlePath               string
+	satelliteMinElevation float64
+	smallBodyPath         string
+	smallBodyMagnitude    float64
+	occultationMagnitude  float64
+	deepSkyMinAltitude    float64
+
 	// Third-party APIs
-	nasaAPIKey string
+	nasaAPIKey     string
+	horizonsURL    string
+	horizonsBodies string
+	fireballs      bool
+	launchURL      string
+	icalFeeds      string
+	icalCategories string
 }
 
 func LoadConfig() Config {
 	// Server
 	apiPort := flag.String("api_port", ":8080", "Astralis API port. Defaults to 8080")
 
+	// Observer
+	latitude := flag.Float64("latitude", 0, "Observer latitude in degrees, north positive")
+	longitude := flag.Float64("longitude", 0, "Observer longitude in degrees, east positive")
+	elevation := flag.Float64("elevation", 0, "Observer elevation in meters above sea level")
+	timeZone := flag.String("time_zone", "UTC", "Observer IANA time zone, e.g. America/New_York")
+	locations := flag.String("locations", "", "Saved locations as name=lat,lon[,elevation[,timezone]] separated by semicolons")
+
+	// Local computations
+	conjunctionSeparation := flag.Float64("conjunction_separation", 2, "Maximum separation in degrees reported as a conjunction")
+	tlePath := flag.String("tle_path", "", "Two-line element file or directory for satellite pass predictions")
+	satelliteMinElevation := flag.Float64("satellite_min_elevation", 10, "Minimum culmination altitude in degrees of reported satellite passes")
+	smallBodyPath := flag.String("small_body_path", "", "MPCORB or CometEls orbital element file or directory for comets and asteroids")
+	smallBodyMagnitude := flag.Float64("small_body_magnitude", 10, "Magnitude a comet or asteroid must become brighter than to be reported")
+	occultationMagnitude := flag.Float64("occultation_magnitude", 4, "Faintest star, up to magnitude 4.5, whose lunar occultations are reported")
+	deepSkyMinAltitude := flag.Float64("deep_sky_min_altitude", 30, "Minimum culmination altitude in degrees of deep-sky objects reported for viewing")
+
 	// Third-party APIs
 	nasaAPIKey := flag.String("nasa_api_key", "", "NASA API Key")
+	horizonsURL := flag.String("horizons_url", "https://ssd.jpl.nasa.gov/api/horizons.api", "JPL Horizons API URL, e.g. of a local stand-in server")
+	horizonsBodies := flag.String("horizons_bodies", "", "Horizons target IDs separated by commas whose rise, transit and set are reported, e.g. 499,599")
+	fireballs := flag.Bool("fireballs", false, "Report the fireballs of the CNEOS fireball API")
+	launchURL := flag.String("launch_url", "", "Launch Library 2 launch feed URL, e.g. https://ll.thespacedevs.com/2.2.0/launch/; launches are not reported without one")
+	icalFeeds := flag.String("ical_feeds", "", "iCalendar feed URLs or files separated by commas, e.g. of a planetarium or club calendar")
+	icalCategories := flag.String("ical_categories", "", "Event types of iCalendar categories as category=TYPE separated by semicolons, e.g. Star Party=DARK_WINDOW;Eclipse=ECLIPSE")
 
 	flag.Parse()
 	return &config{
 		apiPort: *apiPort,
+		latitude: *latitude,
+		longitude: *longitude,
+		elevation: *elevation,
+		timeZone: *timeZone,
+		locations: *locations,
+		conjunctionSeparation: *conjunctionSeparation,
+		tlePath: *tlePath,
+		satelliteMinElevation: *satelliteMinElevation,
+		smallBodyPath: *smallBodyPath,
+		smallBodyMagnitude: *smallBodyMagnitude,
+		occultationMagnitude: *occultationMagnitude,
+		deepSkyMinAltitude: *deepSkyMinAltitude,
 		nasaAPIKey: *nasaAPIKey,
+		horizonsURL: *horizonsURL,
+		horizonsBodies: *horizonsBodies,
+		fireballs: *fireballs,
+		launchURL: *launchURL,
+		icalFeeds: *icalFeeds,
+		icalCategories: *icalCategories,
 	}
 }
 
@@ -36,6 +118,78 @@ func (c *config) APIPort() string {
 	return c.apiPort
 }
 
+func (c *config) Latitude() float64 {
+	return c.latitude
+}
+
+func (c *config) Longitude() float64 {
+	return c.longitude
+}
+
+func (c *config) Elevation() float64 {
+	return c.elevation
+}
+
+func (c *config) TimeZone() string {
+	return c.timeZone
+}
+
+func (c *config) Locations() string {
+	return c.locations
+}
+
+func (c *config) ConjunctionSeparation() float64 {
+	return c.conjunctionSeparation
+}
+
+func (c *config) TLEPath() string {
+	return c.tlePath
+}
+
+func (c *config) SatelliteMinElevation() float64 {
+	return c.satelliteMinElevation
+}
+
+func (c *config) SmallBodyPath() string {
+	return c.smallBodyPath
+}
+
+func (c *config) SmallBodyMagnitude() float64 {
+	return c.smallBodyMagnitude
+}
+
+func (c *config) OccultationMagnitude() float64 {
+	return c.occultationMagnitude
+}
+
+func (c *config) DeepSkyMinAltitude() float64 {
+	return c.deepSkyMinAltitude
+}
+
 func (c *config) NasaAPIKey() string {
 	return c.nasaAPIKey
 }
+
+func (c *config) HorizonsURL() string {
+	return c.horizonsURL
+}
+
+func (c *config) HorizonsBodies() string {
+	return c.horizonsBodies
+}
+
+func (c *config) Fireballs() bool {
+	return c.fireballs
+}
+
+func (c *config) LaunchURL() string {
+	return c.launchURL
+}
+
+func (c *config) ICalFeeds() string {
+	return c.icalFeeds
+}
+
+func (c *config) ICalCategories() string {
+	return c.icalCategories
+}